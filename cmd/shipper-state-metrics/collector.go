@@ -7,12 +7,14 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	kubelisters "k8s.io/client-go/listers/core/v1"
 	klog "k8s.io/klog"
 
 	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
 	shipperlisters "github.com/bookingcom/shipper/pkg/client/listers/shipper/v1alpha1"
+	"github.com/bookingcom/shipper/pkg/util/headroom"
 	releaseutil "github.com/bookingcom/shipper/pkg/util/release"
 )
 
@@ -45,6 +47,20 @@ var (
 		nil,
 	)
 
+	relStepDurationDesc = prometheus.NewDesc(
+		fqn("release_step_durations"),
+		"Duration of each phase of achieved release strategy steps, as recorded in release history",
+		[]string{"phase"},
+		nil,
+	)
+
+	relAbortsDesc = prometheus.NewDesc(
+		fqn("release_aborts"),
+		"Number of aborted releases recorded in release history",
+		[]string{"namespace", "shipper_app"},
+		nil,
+	)
+
 	itsDesc = prometheus.NewDesc(
 		fqn("installationtargets"),
 		"Number of InstallationTarget objects",
@@ -73,6 +89,13 @@ var (
 		nil,
 	)
 
+	clusterHeadroomDesc = prometheus.NewDesc(
+		fqn("cluster_headroom"),
+		"CPU cores and memory bytes allocatable, requested and available for new pods in a Cluster",
+		[]string{"name", "resource", "kind"},
+		nil,
+	)
+
 	rolloutblocksDesc = prometheus.NewDesc(
 		fqn("rolloutblocks"),
 		"Number of RolloutBlock objects",
@@ -113,10 +136,13 @@ func (ssm ShipperStateMetrics) Collect(ch chan<- prometheus.Metric) {
 func (ssm ShipperStateMetrics) Describe(ch chan<- *prometheus.Desc) {
 	ch <- appsDesc
 	ch <- relsDesc
+	ch <- relStepDurationDesc
+	ch <- relAbortsDesc
 	ch <- itsDesc
 	ch <- ctsDesc
 	ch <- ttsDesc
 	ch <- clustersDesc
+	ch <- clusterHeadroomDesc
 	ch <- rolloutblocksDesc
 }
 
@@ -162,6 +188,8 @@ func (ssm ShipperStateMetrics) collectReleases(ch chan<- prometheus.Metric) {
 
 	now := time.Now()
 	relAgesByCondition := make(map[string][]float64)
+	stepDurationsByPhase := make(map[string][]float64)
+	abortsPerApp := make(map[string]float64)
 
 	releasesPerCluster := make(map[string]float64)
 	releasesPerCondition := make(map[string]float64)
@@ -221,6 +249,25 @@ func (ssm ShipperStateMetrics) collectReleases(ch chan<- prometheus.Metric) {
 			}
 		}
 
+		for _, entry := range rel.Status.History {
+			switch entry.Type {
+			case shipper.ReleaseHistoryStepAchieved:
+				phases := map[string]*metav1.Duration{
+					"installation": entry.InstallationDuration,
+					"capacity":     entry.CapacityDuration,
+					"traffic":      entry.TrafficDuration,
+				}
+				for phase, d := range phases {
+					if d == nil {
+						continue
+					}
+
+					stepDurationsByPhase[phase] = append(stepDurationsByPhase[phase], d.Seconds())
+				}
+			case shipper.ReleaseHistoryAborted:
+				abortsPerApp[key(rel.Namespace, appName)]++
+			}
+		}
 	}
 
 	for k, v := range releasesPerCondition {
@@ -239,6 +286,19 @@ func (ssm ShipperStateMetrics) collectReleases(ch chan<- prometheus.Metric) {
 		ch <- prometheus.MustNewConstHistogram(relDurationDesc, count,
 			sum, histogram, condition)
 	}
+
+	for phase, durations := range stepDurationsByPhase {
+		count := uint64(len(durations))
+		sum := Sum(durations)
+		histogram := MakeHistogram(durations, ssm.releaseDurationBuckets)
+
+		ch <- prometheus.MustNewConstHistogram(relStepDurationDesc, count,
+			sum, histogram, phase)
+	}
+
+	for k, v := range abortsPerApp {
+		ch <- prometheus.MustNewConstMetric(relAbortsDesc, prometheus.GaugeValue, v, unkey(k)...)
+	}
 }
 
 func (ssm ShipperStateMetrics) collectInstallationTargets(ch chan<- prometheus.Metric) {
@@ -352,6 +412,39 @@ func (ssm ShipperStateMetrics) collectClusters(ch chan<- prometheus.Metric) {
 		}
 
 		ch <- prometheus.MustNewConstMetric(clustersDesc, prometheus.GaugeValue, 1.0, cluster.Name, schedulable, hasSecret)
+
+		collectClusterHeadroom(ch, cluster)
+	}
+}
+
+func collectClusterHeadroom(ch chan<- prometheus.Metric, cluster *shipper.Cluster) {
+	h := cluster.Status.Headroom
+	if h == nil {
+		return
+	}
+
+	lists := map[string]corev1.ResourceList{
+		"allocatable": h.Allocatable,
+		"requested":   h.Requested,
+		"available":   h.Available,
+	}
+
+	for kind, list := range lists {
+		for _, name := range headroom.Resources {
+			q, ok := list[name]
+			if !ok {
+				continue
+			}
+
+			// CPU is reported in cores, so fractions of a core
+			// don't get lost.
+			v := float64(q.Value())
+			if name == corev1.ResourceCPU {
+				v = float64(q.MilliValue()) / 1000
+			}
+
+			ch <- prometheus.MustNewConstMetric(clusterHeadroomDesc, prometheus.GaugeValue, v, cluster.Name, string(name), kind)
+		}
 	}
 }
 
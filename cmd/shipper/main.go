@@ -36,6 +36,7 @@ import (
 	"github.com/bookingcom/shipper/pkg/clusterclientstore"
 	"github.com/bookingcom/shipper/pkg/controller/application"
 	"github.com/bookingcom/shipper/pkg/controller/capacity"
+	"github.com/bookingcom/shipper/pkg/controller/cluster"
 	"github.com/bookingcom/shipper/pkg/controller/installation"
 	"github.com/bookingcom/shipper/pkg/controller/janitor"
 	"github.com/bookingcom/shipper/pkg/controller/release"
@@ -54,6 +55,7 @@ var controllers = []string{
 	"traffic",
 	"rolloutblock",
 	"janitor",
+	"cluster",
 	"webhook",
 }
 
@@ -357,6 +359,7 @@ func buildInitializers() map[string]initFunc {
 	controllers["traffic"] = startTrafficController
 	controllers["rolloutblock"] = startRolloutBlockController
 	controllers["janitor"] = startJanitorController
+	controllers["cluster"] = startClusterController
 	controllers["webhook"] = startWebhook
 	return controllers
 }
@@ -464,6 +467,26 @@ func startCapacityController(cfg *cfg) (bool, error) {
 	return true, nil
 }
 
+func startClusterController(cfg *cfg) (bool, error) {
+	enabled := cfg.enabledControllers["cluster"]
+	if !enabled {
+		return false, nil
+	}
+
+	c := cluster.NewController(
+		client.NewShipperClientOrDie(cfg.restCfg, cluster.AgentName, cfg.restTimeout),
+		cfg.shipperInformerFactory,
+		cfg.store,
+		cfg.recorder(cluster.AgentName),
+	)
+	cfg.wg.Add(1)
+	go func() {
+		c.Run(cfg.workers, cfg.stopCh)
+		cfg.wg.Done()
+	}()
+	return true, nil
+}
+
 func startTrafficController(cfg *cfg) (bool, error) {
 	enabled := cfg.enabledControllers["traffic"]
 	if !enabled {
@@ -166,6 +166,10 @@ func setupManagementCluster(managementCluster *config.ClusterConfiguration, cmd
 		return err
 	}
 
+	if err := createMutatingWebhookConfiguration(cmd, configurator); err != nil {
+		return err
+	}
+
 	if err := createValidatingWebhookService(cmd, configurator); err != nil {
 		return err
 	}
@@ -250,6 +254,18 @@ func createOrUpdateCrds(cmd *cobra.Command, configurator *configurator.Cluster)
 		return err
 	}
 
+	if err := configurator.CreateOrUpdateCRD(crds.StrategyTemplate); err != nil {
+		return err
+	}
+
+	if err := configurator.CreateOrUpdateCRD(crds.ClusterStrategyTemplate); err != nil {
+		return err
+	}
+
+	if err := configurator.CreateOrUpdateCRD(crds.StepApproval); err != nil {
+		return err
+	}
+
 	cmd.Println("done")
 
 	return nil
@@ -566,3 +582,23 @@ func loadClustersConfiguration() (*config.ClustersConfiguration, error) {
 
 	return configuration, nil
 }
+
+func createMutatingWebhookConfiguration(cmd *cobra.Command, configurator *configurator.Cluster) error {
+	cmd.Printf("Creating the MutatingWebhookConfiguration in %s namespace... ", shipperSystemNamespace)
+	caBundle, err := configurator.FetchKubernetesCABundle()
+	if err != nil {
+		return err
+	}
+
+	if err := configurator.CreateOrUpdateMutatingWebhookConfiguration(caBundle, shipperSystemNamespace); err != nil {
+		if errors.IsAlreadyExists(err) {
+			cmd.Println("already exists. Skipping")
+			return nil
+		}
+
+		return err
+	}
+	cmd.Println("done")
+
+	return nil
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+)
+
+var explainSchedulingCmd = &cobra.Command{
+	Use:   "explain-scheduling [release]",
+	Short: "Show why a release was or wasn't scheduled on each cluster",
+	Long: `Show, for every cluster Shipper considered the last time it scheduled a
+Release, whether it was picked and why: it was marked unschedulable, it's in a
+region the Release doesn't ask for, it's missing capabilities, the cluster
+selector doesn't match it, another cluster in its zone is preferred, or other
+clusters are preferred over it.
+
+Clusters are listed in the order the Application prefers them in. The Release
+is read from the management cluster, or from a file with -f.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runExplainSchedulingCommand,
+}
+
+// Parameters
+var (
+	explainSchedulingFile           string
+	explainSchedulingKubeConfigFile string
+	explainSchedulingContext        string
+	explainSchedulingNamespace      string
+)
+
+func init() {
+	fileFlagName := "file"
+	kubeConfigFlagName := "kube-config"
+	explainSchedulingCmd.Flags().StringVarP(&explainSchedulingFile, fileFlagName, "f", "", "a file with the Release to explain the scheduling of")
+	explainSchedulingCmd.Flags().StringVar(&explainSchedulingKubeConfigFile, kubeConfigFlagName, "~/.kube/config", "the path to the Kubernetes configuration file")
+	explainSchedulingCmd.Flags().StringVar(&explainSchedulingContext, "context", "", "the context of the management cluster (defaults to the current context)")
+	explainSchedulingCmd.Flags().StringVarP(&explainSchedulingNamespace, "namespace", "n", "default", "the namespace of the Release")
+
+	err := explainSchedulingCmd.MarkFlagFilename(fileFlagName, "yaml")
+	if err != nil {
+		explainSchedulingCmd.Printf("warning: could not mark %q for filename autocompletion: %s\n", fileFlagName, err)
+	}
+	err = explainSchedulingCmd.MarkFlagFilename(kubeConfigFlagName, "yaml")
+	if err != nil {
+		explainSchedulingCmd.Printf("warning: could not mark %q for filename autocompletion: %s\n", kubeConfigFlagName, err)
+	}
+}
+
+func runExplainSchedulingCommand(cmd *cobra.Command, args []string) error {
+	var rel *shipper.Release
+	var err error
+	switch {
+	case explainSchedulingFile != "" && len(args) == 0:
+		rel, err = loadRelease(explainSchedulingFile)
+	case explainSchedulingFile == "" && len(args) == 1:
+		rel, err = getRelease(explainSchedulingKubeConfigFile, explainSchedulingContext, explainSchedulingNamespace, args[0])
+	default:
+		return fmt.Errorf("give either the name of a release or a file with -f")
+	}
+	if err != nil {
+		return err
+	}
+
+	return printSchedulingDecisions(cmd.OutOrStdout(), rel)
+}
+
+func printSchedulingDecisions(out io.Writer, rel *shipper.Release) error {
+	for _, cond := range rel.Status.Conditions {
+		if cond.Type == shipper.ReleaseConditionTypeScheduled && cond.Status == corev1.ConditionFalse {
+			fmt.Fprintf(out, "release %q could not be scheduled: %s\n\n", rel.Name, cond.Message)
+		}
+	}
+
+	if len(rel.Status.SchedulingDecisions) == 0 {
+		fmt.Fprintf(out, "release %q has no scheduling decisions recorded\n", rel.Name)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+
+	fmt.Fprintln(w, "PREFERENCE\tCLUSTER\tREGION\tSELECTED\tREASON\tDETAILS")
+	for _, decision := range rel.Status.SchedulingDecisions {
+		preference := "-"
+		if decision.Preference > 0 {
+			preference = fmt.Sprintf("%d", decision.Preference)
+		}
+
+		selected := "no"
+		if decision.Selected {
+			selected = "yes"
+		}
+
+		fmt.Fprintf(
+			w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			preference, decision.Name, decision.Region,
+			selected, decision.Reason, decision.Message,
+		)
+	}
+
+	return w.Flush()
+}
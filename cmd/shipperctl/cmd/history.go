@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/bookingcom/shipper/cmd/shipperctl/config"
+	"github.com/bookingcom/shipper/cmd/shipperctl/configurator"
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history [release]",
+	Short: "Show the timeline of a release's rollout",
+	Long: `Show when the target step of a Release changed and who changed it, when
+each step was achieved and how long installation, capacity and traffic took,
+and which of the releases that followed it were aborted.
+
+The Release is read from the management cluster, or from a file with -f.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runHistoryCommand,
+}
+
+// Parameters
+var (
+	historyFile           string
+	historyKubeConfigFile string
+	historyContext        string
+	historyNamespace      string
+)
+
+func init() {
+	fileFlagName := "file"
+	kubeConfigFlagName := "kube-config"
+	historyCmd.Flags().StringVarP(&historyFile, fileFlagName, "f", "", "a file with the Release to show the history of")
+	historyCmd.Flags().StringVar(&historyKubeConfigFile, kubeConfigFlagName, "~/.kube/config", "the path to the Kubernetes configuration file")
+	historyCmd.Flags().StringVar(&historyContext, "context", "", "the context of the management cluster (defaults to the current context)")
+	historyCmd.Flags().StringVarP(&historyNamespace, "namespace", "n", "default", "the namespace of the Release")
+
+	err := historyCmd.MarkFlagFilename(fileFlagName, "yaml")
+	if err != nil {
+		historyCmd.Printf("warning: could not mark %q for filename autocompletion: %s\n", fileFlagName, err)
+	}
+	err = historyCmd.MarkFlagFilename(kubeConfigFlagName, "yaml")
+	if err != nil {
+		historyCmd.Printf("warning: could not mark %q for filename autocompletion: %s\n", kubeConfigFlagName, err)
+	}
+}
+
+func runHistoryCommand(cmd *cobra.Command, args []string) error {
+	var rel *shipper.Release
+	var err error
+	switch {
+	case historyFile != "" && len(args) == 0:
+		rel, err = loadRelease(historyFile)
+	case historyFile == "" && len(args) == 1:
+		rel, err = getRelease(historyKubeConfigFile, historyContext, historyNamespace, args[0])
+	default:
+		return fmt.Errorf("give either the name of a release or a file with -f")
+	}
+	if err != nil {
+		return err
+	}
+
+	return printHistory(cmd.OutOrStdout(), rel)
+}
+
+func loadRelease(path string) (*shipper.Release, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var rel shipper.Release
+	if err := yaml.Unmarshal(b, &rel); err != nil {
+		return nil, err
+	}
+
+	if rel.Kind != "Release" {
+		return nil, fmt.Errorf("%s must contain a Release, got %q", path, rel.Kind)
+	}
+
+	return &rel, nil
+}
+
+func getRelease(kubeConfigFile, context, namespace, name string) (*shipper.Release, error) {
+	cluster, err := configurator.NewClusterConfigurator(
+		&config.ClusterConfiguration{Context: context},
+		kubeConfigFile,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return cluster.ShipperClient.ShipperV1alpha1().Releases(namespace).
+		Get(name, metav1.GetOptions{})
+}
+
+func printHistory(out io.Writer, rel *shipper.Release) error {
+	if len(rel.Status.History) == 0 {
+		fmt.Fprintf(out, "release %q has no history yet\n", rel.Name)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+
+	fmt.Fprintln(w, "TIME\tEVENT\tSTEP\tBY\tDETAILS")
+	for _, entry := range rel.Status.History {
+		user := entry.User
+		if user == "" {
+			user = "-"
+		}
+
+		fmt.Fprintf(
+			w, "%s\t%s\t%d: %s\t%s\t%s\n",
+			entry.Time.UTC().Format(time.RFC3339), entry.Type,
+			entry.Step, entry.StepName, user, historyDetails(entry),
+		)
+	}
+
+	return w.Flush()
+}
+
+func historyDetails(entry shipper.ReleaseHistoryEntry) string {
+	if entry.Type != shipper.ReleaseHistoryStepAchieved {
+		return entry.Message
+	}
+
+	phases := []struct {
+		name     string
+		duration *metav1.Duration
+	}{
+		{"installation", entry.InstallationDuration},
+		{"capacity", entry.CapacityDuration},
+		{"traffic", entry.TrafficDuration},
+	}
+
+	details := make([]string, 0, len(phases))
+	for _, phase := range phases {
+		if phase.duration == nil {
+			continue
+		}
+
+		details = append(details, fmt.Sprintf("%s %s", phase.name, phase.duration.Duration))
+	}
+
+	return strings.Join(details, ", ")
+}
@@ -0,0 +1,298 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+	"github.com/bookingcom/shipper/pkg/util/replicas"
+	strategyutil "github.com/bookingcom/shipper/pkg/util/strategy"
+)
+
+var planCmd = &cobra.Command{
+	Use:   "plan",
+	Short: "Show how many pods and how much traffic each release gets at every step of a rollout",
+	Long: `Show how many pods and how much traffic the contender and the incumbent get
+in every cluster at every step of the strategy of an Application or a Release.
+
+Pods are rounded the same way Shipper rounds them, and the traffic column
+shows the share of the traffic each release actually gets once traffic
+weights are turned into a number of pods.`,
+	RunE: runPlanCommand,
+}
+
+// Parameters
+var (
+	planFile        string
+	planReplicas    int32
+	planClusters    []string
+	planNoIncumbent bool
+)
+
+const (
+	// planAllClusters stands for every cluster when we don't know which
+	// clusters a release goes to.
+	planAllClusters  = "*"
+	planReplicaCount = "replicaCount"
+
+	// planDefaultClusterWeight is the scheduler weight of clusters that
+	// don't set one.
+	planDefaultClusterWeight = 100
+)
+
+func init() {
+	fileFlagName := "file"
+	planCmd.Flags().StringVarP(&planFile, fileFlagName, "f", "", "the Application or Release to plan a rollout for")
+	planCmd.Flags().Int32Var(&planReplicas, "replicas", 0, "the number of replicas in each cluster (defaults to the replicaCount chart value)")
+	planCmd.Flags().StringSliceVar(&planClusters, "clusters", nil, "the clusters to plan for, as name, name:region or name:region:weight (defaults to the clusters a Release is scheduled on)")
+	planCmd.Flags().BoolVar(&planNoIncumbent, "no-incumbent", false, "plan for the first release of an application")
+
+	err := planCmd.MarkFlagRequired(fileFlagName)
+	if err != nil {
+		planCmd.Printf("warning: could not mark %q as required: %s\n", fileFlagName, err)
+	}
+	err = planCmd.MarkFlagFilename(fileFlagName, "yaml")
+	if err != nil {
+		planCmd.Printf("warning: could not mark %q for filename autocompletion: %s\n", fileFlagName, err)
+	}
+}
+
+func runPlanCommand(cmd *cobra.Command, args []string) error {
+	env, annotations, err := loadReleaseEnvironment(planFile)
+	if err != nil {
+		return err
+	}
+
+	strategy := env.Strategy
+	if strategy == nil {
+		if env.StrategyRef != nil {
+			return fmt.Errorf("%s uses a strategyRef, which can only be planned for once it is part of a Release", planFile)
+		}
+		return fmt.Errorf("%s has no strategy", planFile)
+	}
+
+	if err := strategyutil.ValidateStrategy(strategy); err != nil {
+		return fmt.Errorf("invalid strategy: %s", err)
+	}
+
+	replicaCount := planReplicas
+	if replicaCount == 0 {
+		replicaCount, err = chartReplicaCount(env)
+		if err != nil {
+			return err
+		}
+	}
+
+	clusters, regions, weights, err := planTargetClusters(annotations)
+	if err != nil {
+		return err
+	}
+	if strategyutil.HasClusterSteps(strategy) && len(clusters) == 1 && clusters[0] == planAllClusters {
+		return fmt.Errorf("the strategy has steps for some clusters only: use --clusters to tell which clusters to plan for")
+	}
+
+	replicaCounts := planReplicaCounts(env, annotations, replicaCount, clusters, regions, weights)
+	plans := strategyutil.Plan(strategy, replicaCounts, clusters, regions, !planNoIncumbent)
+
+	return printPlan(cmd.OutOrStdout(), plans, !planNoIncumbent)
+}
+
+// loadReleaseEnvironment reads an Application or a Release from a file, and
+// returns its release environment along with its annotations.
+func loadReleaseEnvironment(path string) (*shipper.ReleaseEnvironment, map[string]string, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var typeMeta metav1.TypeMeta
+	if err := yaml.Unmarshal(b, &typeMeta); err != nil {
+		return nil, nil, err
+	}
+
+	switch typeMeta.Kind {
+	case "Application":
+		var app shipper.Application
+		if err := yaml.Unmarshal(b, &app); err != nil {
+			return nil, nil, err
+		}
+		return &app.Spec.Template, app.Annotations, nil
+	case "Release":
+		var rel shipper.Release
+		if err := yaml.Unmarshal(b, &rel); err != nil {
+			return nil, nil, err
+		}
+		return &rel.Spec.Environment, rel.Annotations, nil
+	default:
+		return nil, nil, fmt.Errorf("%s must contain an Application or a Release, got %q", path, typeMeta.Kind)
+	}
+}
+
+// chartReplicaCount returns the replicaCount chart value, which is what most
+// charts use to set the number of replicas.
+func chartReplicaCount(env *shipper.ReleaseEnvironment) (int32, error) {
+	if env.Values != nil {
+		switch v := (*env.Values)[planReplicaCount].(type) {
+		case float64:
+			return int32(v), nil
+		case int64:
+			return int32(v), nil
+		}
+	}
+
+	return 0, fmt.Errorf("could not find a %s chart value: use --replicas to tell how many replicas there are", planReplicaCount)
+}
+
+// planTargetClusters returns the clusters given in --clusters, or the ones a
+// Release is scheduled on, along with the regions and weights given for
+// them. Without either, all clusters go through the same steps, so they are
+// planned for as a single one.
+func planTargetClusters(annotations map[string]string) ([]string, map[string]string, map[string]int32, error) {
+	regions := make(map[string]string)
+	weights := make(map[string]int32)
+
+	clusters := planClusters
+	if len(clusters) == 0 && annotations[shipper.ReleaseClustersAnnotation] != "" {
+		clusters = strings.Split(annotations[shipper.ReleaseClustersAnnotation], ",")
+	}
+
+	if len(clusters) == 0 {
+		return []string{planAllClusters}, regions, weights, nil
+	}
+
+	names := make([]string, 0, len(clusters))
+	for _, cluster := range clusters {
+		parts := strings.SplitN(cluster, ":", 3)
+		names = append(names, parts[0])
+		if len(parts) >= 2 {
+			regions[parts[0]] = parts[1]
+		}
+		if len(parts) == 3 {
+			weight, err := strconv.ParseInt(parts[2], 10, 32)
+			if err != nil {
+				return nil, nil, nil, fmt.Errorf("invalid weight for cluster %q: %s", parts[0], err)
+			}
+			weights[parts[0]] = int32(weight)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names, regions, weights, nil
+}
+
+// planReplicaCounts works out how many replicas the release runs in each
+// cluster the same way Shipper does: replicaCount in each of them, unless
+// the release asks for them to be weighted in the cluster's region.
+// Clusters a Release is being rescheduled away from are left out of the
+// split.
+func planReplicaCounts(
+	env *shipper.ReleaseEnvironment,
+	annotations map[string]string,
+	replicaCount int32,
+	clusters []string,
+	regions map[string]string,
+	weights map[string]int32,
+) map[string]int32 {
+	counts := make(map[string]int32, len(clusters))
+	for _, cluster := range clusters {
+		counts[cluster] = replicaCount
+	}
+
+	weightedRegions := map[string]struct{}{}
+	for _, region := range env.ClusterRequirements.Regions {
+		if region.WeightedReplicas {
+			weightedRegions[region.Name] = struct{}{}
+		}
+	}
+
+	draining := map[string]struct{}{}
+	if annotations[shipper.ReleaseDrainingClustersAnnotation] != "" {
+		for _, cluster := range strings.Split(annotations[shipper.ReleaseDrainingClustersAnnotation], ",") {
+			draining[cluster] = struct{}{}
+		}
+	}
+
+	regionWeights := map[string]map[string]int32{}
+	for _, cluster := range clusters {
+		region, ok := regions[cluster]
+		if !ok {
+			continue
+		}
+		if _, ok := weightedRegions[region]; !ok {
+			continue
+		}
+		if _, ok := draining[cluster]; ok {
+			continue
+		}
+
+		weight, ok := weights[cluster]
+		if !ok {
+			weight = planDefaultClusterWeight
+		}
+
+		if regionWeights[region] == nil {
+			regionWeights[region] = map[string]int32{}
+		}
+		regionWeights[region][cluster] = weight
+	}
+
+	for _, clusterWeights := range regionWeights {
+		for cluster, count := range replicas.WeightedReplicaCounts(replicaCount, clusterWeights) {
+			counts[cluster] = count
+		}
+	}
+
+	return counts
+}
+
+func printPlan(out io.Writer, plans []strategyutil.StepPlan, hasIncumbent bool) error {
+	w := tabwriter.NewWriter(out, 0, 8, 2, ' ', 0)
+
+	header := "STEP\tCLUSTER\tCONTENDER PODS\tCONTENDER TRAFFIC"
+	if hasIncumbent {
+		header += "\tINCUMBENT PODS\tINCUMBENT TRAFFIC"
+	}
+	fmt.Fprintln(w, header)
+
+	for _, plan := range plans {
+		for _, cluster := range plan.Clusters {
+			clusterName := cluster.Name
+			if cluster.Step != plan.Step {
+				clusterName = fmt.Sprintf("%s (at step %d)", cluster.Name, cluster.Step)
+			}
+
+			row := fmt.Sprintf(
+				"%d: %s\t%s\t%s\t%s",
+				plan.Step, plan.Name, clusterName,
+				formatPlanPods(cluster.Contender), formatPlanTraffic(cluster.Contender),
+			)
+			if cluster.Incumbent != nil {
+				row += fmt.Sprintf(
+					"\t%s\t%s",
+					formatPlanPods(*cluster.Incumbent), formatPlanTraffic(*cluster.Incumbent),
+				)
+			}
+			fmt.Fprintln(w, row)
+		}
+	}
+
+	return w.Flush()
+}
+
+func formatPlanPods(plan strategyutil.ReleasePlan) string {
+	return fmt.Sprintf("%d (%d%%)", plan.Pods, plan.Capacity)
+}
+
+func formatPlanTraffic(plan strategyutil.ReleasePlan) string {
+	return fmt.Sprintf("%.1f%% (weight %d)", plan.TrafficPercent, plan.TrafficWeight)
+}
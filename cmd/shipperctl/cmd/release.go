@@ -0,0 +1,14 @@
+package cmd
+
+import "github.com/spf13/cobra"
+
+var releaseCmd = &cobra.Command{
+	Use:   "release",
+	Short: "work with Shipper Releases",
+}
+
+func init() {
+	releaseCmd.AddCommand(planCmd)
+	releaseCmd.AddCommand(historyCmd)
+	releaseCmd.AddCommand(explainSchedulingCmd)
+}
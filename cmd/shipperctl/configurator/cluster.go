@@ -34,6 +34,8 @@ const (
 	shipperValidatingWebhookName        = "shipper.booking.com"
 	shipperValidatingWebhookServiceName = "shipper-validating-webhook"
 	shipperValidatingWebhookServicePath = "/validate"
+	shipperMutatingWebhookName          = "mutating.shipper.booking.com"
+	shipperMutatingWebhookServicePath   = "/mutate"
 	MaximumRetries                      = 20
 	AgentName                           = "configurator"
 )
@@ -471,6 +473,70 @@ func (c *Cluster) CreateOrUpdateValidatingWebhookConfiguration(caBundle []byte,
 	return err
 }
 
+// CreateOrUpdateMutatingWebhookConfiguration points the API server at the
+// webhook for the objects it fills in: StepApprovals, Releases, which get
+// told who changed their target step, and Applications, which get told who
+// changed their spec. It is served by the same service as the validating
+// webhook.
+func (c *Cluster) CreateOrUpdateMutatingWebhookConfiguration(caBundle []byte, namespace string) error {
+	path := shipperMutatingWebhookServicePath
+	mutatingWebhookConfiguration := &admissionregistrationv1beta1.MutatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: shipperMutatingWebhookName,
+		},
+		Webhooks: []admissionregistrationv1beta1.MutatingWebhook{
+			admissionregistrationv1beta1.MutatingWebhook{
+				Name: shipperMutatingWebhookName,
+				ClientConfig: admissionregistrationv1beta1.WebhookClientConfig{
+					CABundle: caBundle,
+					Service: &admissionregistrationv1beta1.ServiceReference{
+						Name:      shipperValidatingWebhookServiceName,
+						Namespace: namespace,
+						Path:      &path,
+					},
+				},
+				Rules: []admissionregistrationv1beta1.RuleWithOperations{
+					admissionregistrationv1beta1.RuleWithOperations{
+						Operations: []admissionregistrationv1beta1.OperationType{
+							admissionregistrationv1beta1.Create,
+						},
+						Rule: admissionregistrationv1beta1.Rule{
+							APIGroups:   []string{shipper.SchemeGroupVersion.Group},
+							APIVersions: []string{shipper.SchemeGroupVersion.Version},
+							Resources:   []string{"stepapprovals"},
+						},
+					},
+					admissionregistrationv1beta1.RuleWithOperations{
+						Operations: []admissionregistrationv1beta1.OperationType{
+							admissionregistrationv1beta1.Create,
+							admissionregistrationv1beta1.Update,
+						},
+						Rule: admissionregistrationv1beta1.Rule{
+							APIGroups:   []string{shipper.SchemeGroupVersion.Group},
+							APIVersions: []string{shipper.SchemeGroupVersion.Version},
+							Resources:   []string{"releases", "applications"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	existingConfig, err := c.KubeClient.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().Get(shipperMutatingWebhookName, metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			_, err = c.KubeClient.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().Create(mutatingWebhookConfiguration)
+			return err
+		} else {
+			return err
+		}
+	}
+
+	existingConfig.Webhooks = mutatingWebhookConfiguration.Webhooks
+	_, err = c.KubeClient.AdmissionregistrationV1beta1().MutatingWebhookConfigurations().Update(existingConfig)
+	return err
+}
+
 func (c *Cluster) CreateOrUpdateValidatingWebhookService(namespace string) error {
 	service := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
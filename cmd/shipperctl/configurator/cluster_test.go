@@ -9,7 +9,9 @@ import (
 	"k8s.io/apimachinery/pkg/util/intstr"
 	kubefake "k8s.io/client-go/kubernetes/fake"
 	kubetesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/yaml"
 
+	"github.com/bookingcom/shipper/cmd/shipperctl/config"
 	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
 	shipperfake "github.com/bookingcom/shipper/pkg/client/clientset/versioned/fake"
 	shippertesting "github.com/bookingcom/shipper/pkg/testing"
@@ -126,6 +128,35 @@ func TestUpdateValidatingWebhookService(t *testing.T) {
 	shippertesting.CheckActions(f.actions, actualActions, f.t)
 }
 
+func TestCreateClusterCarriesZone(t *testing.T) {
+	f := newFixture(t)
+
+	var configuration config.ClustersConfiguration
+	err := yaml.Unmarshal([]byte(`
+applicationClusters:
+- name: eu-1
+  region: eu-west
+  zone: a
+`), &configuration)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := f.configurator.CreateOrUpdateClusterWithConfig(configuration.ApplicationClusters[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	cluster, err := f.configurator.ShipperClient.ShipperV1alpha1().Clusters().Get("eu-1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cluster.Spec.Region != "eu-west" || cluster.Spec.Zone != "a" {
+		t.Errorf("expected cluster in region %q and zone %q, got %q and %q",
+			"eu-west", "a", cluster.Spec.Region, cluster.Spec.Zone)
+	}
+}
+
 type fixture struct {
 	t            *testing.T
 	configurator *Cluster
@@ -43,3 +43,74 @@ func deepCopyJSONValue(x interface{}) interface{} {
 		panic(fmt.Errorf("cannot deep copy %T", x))
 	}
 }
+
+// stepReplicas is how a strategy step asks for an absolute number of
+// replicas instead of a percentage.
+type stepReplicas struct {
+	Replicas int32 `json:"replicas"`
+}
+
+// MarshalJSON writes the incumbent and contender values of a step as
+// percentages, or as {"replicas": N} when they ask for an absolute number
+// of replicas.
+func (v RolloutStrategyStepValue) MarshalJSON() ([]byte, error) {
+	out := struct {
+		Incumbent interface{} `json:"incumbent"`
+		Contender interface{} `json:"contender"`
+	}{
+		Incumbent: v.Incumbent,
+		Contender: v.Contender,
+	}
+
+	if v.IncumbentReplicas != nil {
+		out.Incumbent = stepReplicas{Replicas: *v.IncumbentReplicas}
+	}
+	if v.ContenderReplicas != nil {
+		out.Contender = stepReplicas{Replicas: *v.ContenderReplicas}
+	}
+
+	return encodingjson.Marshal(out)
+}
+
+// UnmarshalJSON reads the incumbent and contender values of a step, which
+// can each be either a percentage or {"replicas": N}.
+func (v *RolloutStrategyStepValue) UnmarshalJSON(data []byte) error {
+	var in struct {
+		Incumbent encodingjson.RawMessage `json:"incumbent"`
+		Contender encodingjson.RawMessage `json:"contender"`
+	}
+	if err := encodingjson.Unmarshal(data, &in); err != nil {
+		return err
+	}
+
+	var err error
+	if v.Incumbent, v.IncumbentReplicas, err = unmarshalStepAmount(in.Incumbent); err != nil {
+		return fmt.Errorf("incumbent: %s", err)
+	}
+	if v.Contender, v.ContenderReplicas, err = unmarshalStepAmount(in.Contender); err != nil {
+		return fmt.Errorf("contender: %s", err)
+	}
+
+	return nil
+}
+
+func unmarshalStepAmount(data encodingjson.RawMessage) (int32, *int32, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return 0, nil, nil
+	}
+
+	if data[0] == '{' {
+		var r stepReplicas
+		if err := encodingjson.Unmarshal(data, &r); err != nil {
+			return 0, nil, err
+		}
+		return 0, &r.Replicas, nil
+	}
+
+	var percent int32
+	if err := encodingjson.Unmarshal(data, &percent); err != nil {
+		return 0, nil, err
+	}
+
+	return percent, nil, nil
+}
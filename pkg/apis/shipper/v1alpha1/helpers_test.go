@@ -0,0 +1,47 @@
+package v1alpha1
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestRolloutStrategyStepValueJSON(t *testing.T) {
+	replicas := int32(1)
+	tests := []struct {
+		json  string
+		value RolloutStrategyStepValue
+	}{
+		{
+			`{"incumbent":100,"contender":1}`,
+			RolloutStrategyStepValue{Incumbent: 100, Contender: 1},
+		},
+		{
+			`{"incumbent":100,"contender":{"replicas":1}}`,
+			RolloutStrategyStepValue{Incumbent: 100, ContenderReplicas: &replicas},
+		},
+	}
+
+	for _, tt := range tests {
+		var value RolloutStrategyStepValue
+		if err := json.Unmarshal([]byte(tt.json), &value); err != nil {
+			t.Fatalf("could not unmarshal %s: %s", tt.json, err)
+		}
+		if !reflect.DeepEqual(value, tt.value) {
+			t.Errorf("expected %s to unmarshal to %+v, got %+v", tt.json, tt.value, value)
+		}
+
+		out, err := json.Marshal(tt.value)
+		if err != nil {
+			t.Fatalf("could not marshal %+v: %s", tt.value, err)
+		}
+		if string(out) != tt.json {
+			t.Errorf("expected %+v to marshal to %s, got %s", tt.value, tt.json, out)
+		}
+	}
+
+	var value RolloutStrategyStepValue
+	if err := json.Unmarshal([]byte(`{"incumbent":100,"contender":"one"}`), &value); err == nil {
+		t.Errorf("expected a contender that is neither a percentage nor replicas to fail, got %+v", value)
+	}
+}
@@ -47,6 +47,12 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&TrafficTargetList{},
 		&RolloutBlock{},
 		&RolloutBlockList{},
+		&StrategyTemplate{},
+		&StrategyTemplateList{},
+		&ClusterStrategyTemplate{},
+		&ClusterStrategyTemplateList{},
+		&StepApproval{},
+		&StepApprovalList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
@@ -25,10 +25,34 @@ const (
 	AppChartNameAnnotation            = "shipper.booking.com/app.chart.name"
 	AppChartVersionResolvedAnnotation = "shipper.booking.com/app.chart.version.resolved"
 	AppChartVersionRawAnnotation      = "shipper.booking.com/app.chart.version.raw"
+	// AppSpecChangedByAnnotation is set by the webhook to the user who
+	// created an application or last changed its spec.
+	AppSpecChangedByAnnotation = "shipper.booking.com/app.spec.changedBy"
 
 	ReleaseGenerationAnnotation        = "shipper.booking.com/release.generation"
 	ReleaseTemplateIterationAnnotation = "shipper.booking.com/release.template.iteration"
 	ReleaseClustersAnnotation          = "shipper.booking.com/release.clusters"
+	// ReleaseTargetStepChangedByAnnotation is set by the webhook to the
+	// user who last changed a release's target step.
+	ReleaseTargetStepChangedByAnnotation = "shipper.booking.com/release.targetStep.changedBy"
+	// ReleaseRequestedByAnnotation is the user whose change to an
+	// application made Shipper create a release, or who created the
+	// release themselves.
+	ReleaseRequestedByAnnotation = "shipper.booking.com/release.requestedBy"
+	// ReleaseDrainingClustersAnnotation lists the clusters a release is
+	// being rescheduled away from. They stay in ReleaseClustersAnnotation
+	// until they are drained.
+	ReleaseDrainingClustersAnnotation = "shipper.booking.com/release.clusters.draining"
+
+	// HPAMinReplicasAnnotation and HPAMaxReplicasAnnotation keep the
+	// replica bounds a HorizontalPodAutoscaler was installed with, while
+	// the capacity controller scales them to a release's capacity.
+	HPAMinReplicasAnnotation = "shipper.booking.com/hpa.minReplicas"
+	HPAMaxReplicasAnnotation = "shipper.booking.com/hpa.maxReplicas"
+	// CapacityOptOutAnnotation, set to "true" on a Deployment or
+	// StatefulSet in a chart, keeps it at the replicas the chart gives it
+	// instead of scaling it with the strategy.
+	CapacityOptOutAnnotation = "shipper.booking.com/capacity.optOut"
 
 	SecretChecksumAnnotation             = "shipper.booking.com/cluster-secret.checksum"
 	SecretClusterSkipTlsVerifyAnnotation = "shipper.booking.com/cluster-secret.insecure-tls-skip-verify"
@@ -79,6 +103,27 @@ type ApplicationList struct {
 type ApplicationSpec struct {
 	RevisionHistoryLimit *int32             `json:"revisionHistoryLimit"`
 	Template             ReleaseEnvironment `json:"template"`
+	RollbackPolicy       *RollbackPolicy    `json:"rollbackPolicy,omitempty"`
+}
+
+// RollbackPolicy tells Shipper when to give up on a contender and roll back to
+// the incumbent on its own. Any threshold left unset is not checked.
+type RollbackPolicy struct {
+	// MaxSadPodPercent is the percentage of the contender's pods that can
+	// be not Ready before it is considered unhealthy.
+	MaxSadPodPercent *int32 `json:"maxSadPodPercent,omitempty"`
+
+	// MaxRestarts is the number of times a container of the contender can
+	// restart before it is considered unhealthy.
+	MaxRestarts *int32 `json:"maxRestarts,omitempty"`
+
+	// GracePeriod is how long the contender has to stay above
+	// MaxSadPodPercent or MaxRestarts before it is rolled back.
+	GracePeriod *metav1.Duration `json:"gracePeriod,omitempty"`
+
+	// ProgressDeadline is how long the contender can go without
+	// achieving the step it is targeting before it is rolled back.
+	ProgressDeadline *metav1.Duration `json:"progressDeadline,omitempty"`
 }
 
 type ApplicationStatus struct {
@@ -146,10 +191,13 @@ type ClusterList struct {
 }
 
 type ClusterSpec struct {
-	Capabilities []string                 `json:"capabilities"`
-	Region       string                   `json:"region"`
-	APIMaster    string                   `json:"apiMaster"`
-	Scheduler    ClusterSchedulerSettings `json:"scheduler"`
+	Capabilities []string `json:"capabilities"`
+	Region       string   `json:"region"`
+	// Zone is the failure domain the cluster is in within its region.
+	// Releases can ask to be spread across clusters in distinct zones.
+	Zone      string                   `json:"zone,omitempty"`
+	APIMaster string                   `json:"apiMaster"`
+	Scheduler ClusterSchedulerSettings `json:"scheduler"`
 }
 
 type ClusterSchedulerSettings struct {
@@ -161,7 +209,26 @@ type ClusterSchedulerSettings struct {
 // NOTE(btyler) when we introduce capacity based scheduling, the capacity can
 // be collected by a cluster controller and stored in cluster.status
 type ClusterStatus struct {
-	InService bool `json:"inService"`
+	// InService is whether the cluster is fit to run workloads, which
+	// clusters that don't say are. Operators take clusters out of service
+	// to decommission them, and releases that ask to be rescheduled are
+	// then moved away.
+	InService *bool `json:"inService,omitempty"`
+
+	// Headroom is how much CPU and memory the cluster has left for new
+	// pods, as last seen by the cluster controller.
+	Headroom *ClusterHeadroom `json:"headroom,omitempty"`
+}
+
+type ClusterHeadroom struct {
+	// Allocatable is the CPU and memory of all of the cluster's
+	// schedulable nodes, and Requested how much of it the pods running
+	// on them request. Available is what's left.
+	Allocatable corev1.ResourceList `json:"allocatable"`
+	Requested   corev1.ResourceList `json:"requested"`
+	Available   corev1.ResourceList `json:"available"`
+
+	LastUpdateTime metav1.Time `json:"lastUpdateTime"`
 }
 
 // +genclient
@@ -197,11 +264,87 @@ type ReleaseStatus struct {
 	AchievedStep *AchievedStep          `json:"achievedStep,omitempty"`
 	Strategy     *ReleaseStrategyStatus `json:"strategy,omitempty"`
 	Conditions   []ReleaseCondition     `json:"conditions,omitempty"`
+
+	// History is what happened to the release over time, oldest first.
+	// Entries are only ever appended, and only the latest
+	// ReleaseHistoryLimit of them are kept.
+	History []ReleaseHistoryEntry `json:"history,omitempty"`
+
+	// SchedulingDecisions tells why each cluster was or wasn't picked
+	// the last time the release was scheduled, most preferred first.
+	// Only the first ReleaseSchedulingDecisionsLimit of them are kept.
+	SchedulingDecisions []ClusterSchedulingDecision `json:"schedulingDecisions,omitempty"`
+}
+
+const ReleaseHistoryLimit = 50
+
+const ReleaseSchedulingDecisionsLimit = 50
+
+type ClusterSchedulingReason string
+
+const (
+	ClusterSchedulingSelected             ClusterSchedulingReason = "Selected"
+	ClusterSchedulingSelectorMismatch     ClusterSchedulingReason = "SelectorMismatch"
+	ClusterSchedulingUnschedulable        ClusterSchedulingReason = "Unschedulable"
+	ClusterSchedulingOutOfService         ClusterSchedulingReason = "OutOfService"
+	ClusterSchedulingWrongRegion          ClusterSchedulingReason = "WrongRegion"
+	ClusterSchedulingMissingCapabilities  ClusterSchedulingReason = "MissingCapabilities"
+	ClusterSchedulingNoZone               ClusterSchedulingReason = "NoZone"
+	ClusterSchedulingZoneTaken            ClusterSchedulingReason = "ZoneTaken"
+	ClusterSchedulingInsufficientHeadroom ClusterSchedulingReason = "InsufficientHeadroom"
+	ClusterSchedulingLowerPreference      ClusterSchedulingReason = "LowerPreference"
+	ClusterSchedulingRegionUnsatisfiable  ClusterSchedulingReason = "RegionUnsatisfiable"
+)
+
+type ClusterSchedulingDecision struct {
+	Name     string                  `json:"name"`
+	Region   string                  `json:"region,omitempty"`
+	Selected bool                    `json:"selected"`
+	Reason   ClusterSchedulingReason `json:"reason"`
+
+	// Preference is where the cluster stands in the application's
+	// preference list, starting at 1. Clusters the cluster selector
+	// doesn't match aren't in it, and have no preference.
+	Preference int32 `json:"preference,omitempty"`
+
+	Message string `json:"message,omitempty"`
+}
+
+type ReleaseHistoryEntryType string
+
+const (
+	ReleaseHistoryTargetStepChanged ReleaseHistoryEntryType = "TargetStepChanged"
+	ReleaseHistoryStepAchieved      ReleaseHistoryEntryType = "StepAchieved"
+	ReleaseHistoryAborted           ReleaseHistoryEntryType = "Aborted"
+)
+
+type ReleaseHistoryEntry struct {
+	Type     ReleaseHistoryEntryType `json:"type"`
+	Time     metav1.Time             `json:"time"`
+	Step     int32                   `json:"step"`
+	StepName string                  `json:"stepName,omitempty"`
+
+	// User is who changed the target step, for TargetStepChanged
+	// entries, when known.
+	User string `json:"user,omitempty"`
+
+	// InstallationDuration, CapacityDuration and TrafficDuration are how
+	// long it took to achieve each part of the step after it was
+	// targeted, for StepAchieved entries. Parts that were already
+	// achieved take no time.
+	InstallationDuration *metav1.Duration `json:"installationDuration,omitempty"`
+	CapacityDuration     *metav1.Duration `json:"capacityDuration,omitempty"`
+	TrafficDuration      *metav1.Duration `json:"trafficDuration,omitempty"`
+
+	Message string `json:"message,omitempty"`
 }
 
 type AchievedStep struct {
 	Step int32  `json:"step"`
 	Name string `json:"name"`
+	// AchievedTime is when the step was achieved. It is used to time step
+	// pauses, so they survive controller restarts.
+	AchievedTime metav1.Time `json:"achievedTime,omitempty"`
 }
 
 type ReleaseConditionType string
@@ -211,6 +354,8 @@ const (
 	ReleaseConditionTypeStrategyExecuted ReleaseConditionType = "StrategyExecuted"
 	ReleaseConditionTypeComplete         ReleaseConditionType = "Complete"
 	ReleaseConditionTypeBlocked          ReleaseConditionType = "Blocked"
+	ReleaseConditionTypeProgressing      ReleaseConditionType = "Progressing"
+	ReleaseConditionTypeRescheduled      ReleaseConditionType = "Rescheduled"
 )
 
 type ReleaseCondition struct {
@@ -232,32 +377,175 @@ type ReleaseEnvironment struct {
 	ClusterRequirements ClusterRequirements `json:"clusterRequirements"`
 
 	Strategy *RolloutStrategy `json:"strategy,omitempty"`
+
+	// StrategyRef points at a StrategyTemplate or ClusterStrategyTemplate
+	// to use instead of an inline Strategy. It is resolved into Strategy
+	// when a Release is created, so changing the template only affects
+	// Releases created afterwards.
+	StrategyRef *StrategyReference `json:"strategyRef,omitempty"`
+}
+
+const (
+	StrategyTemplateKind        = "StrategyTemplate"
+	ClusterStrategyTemplateKind = "ClusterStrategyTemplate"
+)
+
+type StrategyReference struct {
+	// Kind is either StrategyTemplate, the default, or
+	// ClusterStrategyTemplate.
+	Kind string `json:"kind,omitempty"`
+	Name string `json:"name"`
 }
 
 type ClusterRequirements struct {
 	// it is an error to not specify any regions
 	Regions      []RegionRequirement `json:"regions"`
 	Capabilities []string            `json:"capabilities,omitempty"`
+	// Selector picks clusters by their labels, alongside or instead of
+	// capabilities. Clusters it doesn't match are never scheduled on.
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	// Reschedule moves a complete release off clusters that become
+	// unschedulable, go out of service or are removed, onto the clusters
+	// the scheduler would pick now. Releases asking for it are never
+	// scheduled on clusters out of service. Capacity is brought up in the new clusters before
+	// the old ones are drained.
+	Reschedule bool `json:"reschedule,omitempty"`
 }
 
 type RegionRequirement struct {
 	Name     string `json:"name"`
 	Replicas *int32 `json:"replicas,omitempty"`
+	// SpreadAcrossZones makes sure every cluster picked in the region is
+	// in a different zone, so a single zone going down never takes out
+	// more than one of them. Clusters without a zone are never picked.
+	SpreadAcrossZones bool `json:"spreadAcrossZones,omitempty"`
+	// WeightedReplicas splits the replicas of the clusters picked in the
+	// region among them according to their scheduler weights, instead of
+	// running as many replicas as the chart asks for in each of them. The
+	// region as a whole still runs that many replicas per cluster.
+	WeightedReplicas bool `json:"weightedReplicas,omitempty"`
 }
 
+type RolloutStrategyType string
+
+const (
+	// RolloutStrategyTypeIncremental shifts traffic gradually by labeling
+	// pods to receive it. It is the default.
+	RolloutStrategyTypeIncremental RolloutStrategyType = "Incremental"
+	// RolloutStrategyTypeBlueGreen sends all of the traffic to a single
+	// release at a time, by pointing the production Service at it.
+	RolloutStrategyTypeBlueGreen RolloutStrategyType = "BlueGreen"
+)
+
 type RolloutStrategy struct {
+	// Type is how traffic moves between releases. It defaults to
+	// Incremental.
+	Type RolloutStrategyType `json:"type,omitempty"`
+
 	Steps []RolloutStrategyStep `json:"steps"`
+
+	// ProgressDeadlineSeconds is how long a release can take to achieve
+	// any of its steps before it is considered stuck. Steps can override
+	// it with their own.
+	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
 }
 
 type RolloutStrategyStep struct {
 	Name     string                   `json:"name"`
 	Capacity RolloutStrategyStepValue `json:"capacity"`
 	Traffic  RolloutStrategyStepValue `json:"traffic"`
+
+	// Pause makes the release controller advance the release to the next
+	// step on its own once this step has been achieved for this long.
+	Pause *metav1.Duration `json:"pause,omitempty"`
+
+	// ProgressDeadlineSeconds overrides the strategy's deadline for
+	// achieving this step.
+	ProgressDeadlineSeconds *int32 `json:"progressDeadlineSeconds,omitempty"`
+
+	// Analysis gates the step on metric queries: the step is only
+	// achieved once all of them pass.
+	Analysis *RolloutStrategyStepAnalysis `json:"analysis,omitempty"`
+
+	// Clusters restricts this step to a subset of the clusters the
+	// release was scheduled on. A step without it applies to every
+	// cluster. Clusters not selected by a step stay at the last step that
+	// did select them, or at zero contender capacity and traffic if no
+	// step has selected them yet. The last step can't have it, as every
+	// cluster has to end up there.
+	Clusters *RolloutStrategyStepClusters `json:"clusters,omitempty"`
+
+	// Hooks are URLs Shipper calls before starting this step and after
+	// achieving it.
+	Hooks *RolloutStrategyStepHooks `json:"hooks,omitempty"`
+
+	// RequiresApproval keeps releases from moving on to this step until
+	// a StepApproval for it exists. Shipper then advances them by itself.
+	RequiresApproval bool `json:"requiresApproval,omitempty"`
+}
+
+// RolloutStrategyStepClusters selects clusters for a strategy step. A
+// cluster is selected if it matches any of the given names, regions or
+// ordinals.
+type RolloutStrategyStepClusters struct {
+	Names   []string `json:"names,omitempty"`
+	Regions []string `json:"regions,omitempty"`
+	// Ordinals are indices into the alphabetically sorted list of clusters
+	// the release was scheduled on.
+	Ordinals []int32 `json:"ordinals,omitempty"`
+}
+
+// RolloutStrategyStepHooks are HTTP endpoints that get POSTed a description
+// of the release and step. A step doesn't progress until they answer with a
+// 2xx status, and without {"proceed": false} in the body.
+type RolloutStrategyStepHooks struct {
+	// PreStep is called before any capacity or traffic is shifted for
+	// the step.
+	PreStep string `json:"preStep,omitempty"`
+	// PostStep is called once the step's capacity and traffic have been
+	// achieved, and its analysis has passed. The step is only considered
+	// achieved once this hook lets it.
+	PostStep string `json:"postStep,omitempty"`
+}
+
+// RolloutStrategyStepAnalysis describes metric checks run against a
+// Prometheus-compatible HTTP API once a step's capacity and traffic have been
+// achieved.
+type RolloutStrategyStepAnalysis struct {
+	// URL is the base address of the API, like http://prometheus:9090.
+	URL     string                      `json:"url"`
+	Metrics []RolloutStrategyStepMetric `json:"metrics"`
+	// AbortOnFailure makes Shipper abort the rollout as soon as a check
+	// fails, instead of waiting for it to pass.
+	AbortOnFailure bool `json:"abortOnFailure,omitempty"`
+}
+
+// RolloutStrategyStepMetric is a single metric check. Thresholds are decimal
+// numbers written as strings; a check fails if any threshold it sets is
+// crossed.
+type RolloutStrategyStepMetric struct {
+	Name string `json:"name"`
+	// Query is an instant query returning a single value. It is a Go
+	// template: {{.Release}}, {{.Namespace}} and {{.Application}} expand to
+	// the release being evaluated.
+	Query string `json:"query"`
+	Min   string `json:"min,omitempty"`
+	Max   string `json:"max,omitempty"`
+	// MaxIncumbentRatio fails the check if the contender's value is more
+	// than this many times the incumbent's.
+	MaxIncumbentRatio string `json:"maxIncumbentRatio,omitempty"`
 }
 
 type RolloutStrategyStepValue struct {
 	Incumbent int32 `json:"incumbent"`
 	Contender int32 `json:"contender"`
+
+	// IncumbentReplicas and ContenderReplicas ask for an absolute number
+	// of replicas instead of a percentage, and are written as
+	// "contender: {replicas: 1}". Only capacity takes them. See
+	// MarshalJSON and UnmarshalJSON.
+	IncumbentReplicas *int32 `json:"-"`
+	ContenderReplicas *int32 `json:"-"`
 }
 
 type TargetConditionType string
@@ -370,12 +658,21 @@ type ClusterCapacityReportContainerBreakdown struct {
 	States []ClusterCapacityReportContainerStateBreakdown `json:"states"`
 }
 
+type ClusterCapacityReportEventBreakdown struct {
+	Count   uint32                                         `json:"count"`
+	Example ClusterCapacityReportContainerBreakdownExample `json:"example"`
+	Reason  string                                         `json:"reason"`
+}
+
 type ClusterCapacityReportBreakdown struct {
 	Containers []ClusterCapacityReportContainerBreakdown `json:"containers,omitempty"`
 	Count      uint32                                    `json:"count"`
 	Reason     string                                    `json:"reason,omitempty"`
 	Status     string                                    `json:"status"`
 	Type       string                                    `json:"type"`
+	// Events counts the pods in this breakdown that Kubernetes warned
+	// about, by the reason of the warning, most common first.
+	Events []ClusterCapacityReportEventBreakdown `json:"events,omitempty"`
 }
 
 type ClusterCapacityReportOwner struct {
@@ -394,6 +691,17 @@ type ClusterCapacityStatus struct {
 	SadPods           []PodStatus                `json:"sadPods,omitempty"`
 	Conditions        []ClusterCapacityCondition `json:"conditions,omitempty"`
 	Reports           []ClusterCapacityReport    `json:"reports,omitempty"`
+	// Workloads reports on each workload listed in the cluster's spec.
+	// AvailableReplicas then adds them all up, and AchievedPercent is
+	// that of the workload furthest behind.
+	Workloads []WorkloadCapacityStatus `json:"workloads,omitempty"`
+}
+
+type WorkloadCapacityStatus struct {
+	Kind              string `json:"kind"`
+	Name              string `json:"name"`
+	AvailableReplicas int32  `json:"availableReplicas"`
+	AchievedPercent   int32  `json:"achievedPercent"`
 }
 
 type ClusterConditionType string
@@ -416,6 +724,18 @@ type PodStatus struct {
 	Containers     []corev1.ContainerStatus `json:"containers"`
 	InitContainers []corev1.ContainerStatus `json:"initContainers"`
 	Condition      corev1.PodCondition      `json:"condition"`
+	// Events are the most recent warnings Kubernetes recorded about the
+	// pod, newest first, such as it not fitting on any node or failing
+	// its probes.
+	Events []PodEvent `json:"events,omitempty"`
+}
+
+// PodEvent is a warning Kubernetes recorded about a pod. Only its reason
+// and message are kept, so that the same warning coming up again doesn't
+// change the status of the object it is reported in.
+type PodEvent struct {
+	Reason  string `json:"reason"`
+	Message string `json:"message"`
 }
 
 // the capacity and traffic controllers need context to pick the right
@@ -431,6 +751,21 @@ type ClusterCapacityTarget struct {
 	Name              string `json:"name"`
 	Percent           int32  `json:"percent"`
 	TotalReplicaCount int32  `json:"totalReplicaCount"`
+	// Replicas, when set, is the number of replicas the release should
+	// have in this cluster, for strategy steps asking for an absolute
+	// number of them. Percent then only approximates it.
+	Replicas *int32 `json:"replicas,omitempty"`
+	// Workloads lists the Deployments and StatefulSets capacity is scaled
+	// through, for charts that have more than one. Percent applies to
+	// each of them, and TotalReplicaCount adds them all up. Charts with a
+	// single workload leave it empty.
+	Workloads []WorkloadCapacityTarget `json:"workloads,omitempty"`
+}
+
+type WorkloadCapacityTarget struct {
+	Kind              string `json:"kind"`
+	Name              string `json:"name"`
+	TotalReplicaCount int32  `json:"totalReplicaCount"`
 }
 
 // +genclient
@@ -479,6 +814,11 @@ type ClusterTrafficCondition struct {
 
 type TrafficTargetSpec struct {
 	Clusters []ClusterTrafficTarget `json:"clusters"`
+
+	// StrategyType is the type of the strategy of the release this
+	// TrafficTarget belongs to. The latest release of an application
+	// decides how traffic is shifted for all of them.
+	StrategyType RolloutStrategyType `json:"strategyType,omitempty"`
 }
 
 type ClusterTrafficTarget struct {
@@ -490,6 +830,18 @@ type ClusterTrafficTarget struct {
 type ReleaseStrategyStatus struct {
 	State      ReleaseStrategyState       `json:"state,omitempty"`
 	Conditions []ReleaseStrategyCondition `json:"conditions,omitempty"`
+	// Clusters is only populated for strategies containing steps that
+	// target a subset of clusters.
+	Clusters []ClusterStrategyStatus `json:"clusters,omitempty"`
+}
+
+// ClusterStrategyStatus reports which strategy step a single cluster is at.
+type ClusterStrategyStatus struct {
+	Name string `json:"name"`
+	// Step is -1 if no step has selected this cluster yet.
+	Step     int32  `json:"step"`
+	StepName string `json:"stepName,omitempty"`
+	Achieved bool   `json:"achieved"`
 }
 
 type ReleaseStrategyState struct {
@@ -516,6 +868,10 @@ const (
 	StrategyConditionContenderAchievedTraffic      StrategyConditionType = "ContenderAchievedTraffic"
 	StrategyConditionIncumbentAchievedCapacity     StrategyConditionType = "IncumbentAchievedCapacity"
 	StrategyConditionIncumbentAchievedTraffic      StrategyConditionType = "IncumbentAchievedTraffic"
+	StrategyConditionContenderPassedAnalysis       StrategyConditionType = "ContenderPassedAnalysis"
+	StrategyConditionContenderPassedPreStepHook    StrategyConditionType = "ContenderPassedPreStepHook"
+	StrategyConditionContenderPassedPostStepHook   StrategyConditionType = "ContenderPassedPostStepHook"
+	StrategyConditionContenderApproved             StrategyConditionType = "ContenderApproved"
 )
 
 type StrategyState string
@@ -571,6 +927,84 @@ const (
 	RolloutBlockReason = "RolloutsBlocked"
 )
 
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// A StrategyTemplate is a rollout strategy that Applications in its namespace
+// can refer to by name instead of spelling out their own.
+type StrategyTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec RolloutStrategy `json:"spec"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// StrategyTemplateList is a list of StrategyTemplates.
+type StrategyTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []StrategyTemplate `json:"items"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// A ClusterStrategyTemplate is a rollout strategy that Applications in any
+// namespace can refer to by name.
+type ClusterStrategyTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec RolloutStrategy `json:"spec"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterStrategyTemplateList is a list of ClusterStrategyTemplates.
+type ClusterStrategyTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []ClusterStrategyTemplate `json:"items"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// A StepApproval lets a Release move on to a strategy step that requires
+// approval.
+type StepApproval struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec StepApprovalSpec `json:"spec"`
+}
+
+type StepApprovalSpec struct {
+	// Release is the name of the approved Release, in the same namespace
+	// as the approval.
+	Release string `json:"release"`
+	// Step is the index of the approved strategy step.
+	Step int32 `json:"step"`
+	// ApprovedBy is the user that created the approval. It is filled in
+	// by the webhook.
+	ApprovedBy string `json:"approvedBy,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// StepApprovalList is a list of StepApprovals.
+type StepApprovalList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []StepApproval `json:"items"`
+}
+
 func (ss *StrategyState) UnmarshalJSON(b []byte) error {
 	var s string
 	if err := json.Unmarshal(b, &s); err != nil {
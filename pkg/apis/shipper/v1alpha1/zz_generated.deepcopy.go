@@ -6,12 +6,14 @@ package v1alpha1
 
 import (
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AchievedStep) DeepCopyInto(out *AchievedStep) {
 	*out = *in
+	in.AchievedTime.DeepCopyInto(&out.AchievedTime)
 	return
 }
 
@@ -112,6 +114,11 @@ func (in *ApplicationSpec) DeepCopyInto(out *ApplicationSpec) {
 		**out = **in
 	}
 	in.Template.DeepCopyInto(&out.Template)
+	if in.RollbackPolicy != nil {
+		in, out := &in.RollbackPolicy, &out.RollbackPolicy
+		*out = new(RollbackPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -220,7 +227,9 @@ func (in *CapacityTargetSpec) DeepCopyInto(out *CapacityTargetSpec) {
 	if in.Clusters != nil {
 		in, out := &in.Clusters, &out.Clusters
 		*out = make([]ClusterCapacityTarget, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	return
 }
@@ -297,7 +306,7 @@ func (in *Cluster) DeepCopyInto(out *Cluster) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -370,6 +379,13 @@ func (in *ClusterCapacityReportBreakdown) DeepCopyInto(out *ClusterCapacityRepor
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = make([]ClusterCapacityReportEventBreakdown, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -444,6 +460,23 @@ func (in *ClusterCapacityReportContainerStateBreakdown) DeepCopy() *ClusterCapac
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterCapacityReportEventBreakdown) DeepCopyInto(out *ClusterCapacityReportEventBreakdown) {
+	*out = *in
+	in.Example.DeepCopyInto(&out.Example)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterCapacityReportEventBreakdown.
+func (in *ClusterCapacityReportEventBreakdown) DeepCopy() *ClusterCapacityReportEventBreakdown {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterCapacityReportEventBreakdown)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterCapacityReportOwner) DeepCopyInto(out *ClusterCapacityReportOwner) {
 	*out = *in
@@ -484,6 +517,11 @@ func (in *ClusterCapacityStatus) DeepCopyInto(out *ClusterCapacityStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Workloads != nil {
+		in, out := &in.Workloads, &out.Workloads
+		*out = make([]WorkloadCapacityStatus, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -500,6 +538,16 @@ func (in *ClusterCapacityStatus) DeepCopy() *ClusterCapacityStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterCapacityTarget) DeepCopyInto(out *ClusterCapacityTarget) {
 	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Workloads != nil {
+		in, out := &in.Workloads, &out.Workloads
+		*out = make([]WorkloadCapacityTarget, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -513,6 +561,44 @@ func (in *ClusterCapacityTarget) DeepCopy() *ClusterCapacityTarget {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterHeadroom) DeepCopyInto(out *ClusterHeadroom) {
+	*out = *in
+	if in.Allocatable != nil {
+		in, out := &in.Allocatable, &out.Allocatable
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Requested != nil {
+		in, out := &in.Requested, &out.Requested
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	if in.Available != nil {
+		in, out := &in.Available, &out.Available
+		*out = make(v1.ResourceList, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val.DeepCopy()
+		}
+	}
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterHeadroom.
+func (in *ClusterHeadroom) DeepCopy() *ClusterHeadroom {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterHeadroom)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterInstallationCondition) DeepCopyInto(out *ClusterInstallationCondition) {
 	*out = *in
@@ -601,6 +687,11 @@ func (in *ClusterRequirements) DeepCopyInto(out *ClusterRequirements) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -640,6 +731,22 @@ func (in *ClusterSchedulerSettings) DeepCopy() *ClusterSchedulerSettings {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterSchedulingDecision) DeepCopyInto(out *ClusterSchedulingDecision) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSchedulingDecision.
+func (in *ClusterSchedulingDecision) DeepCopy() *ClusterSchedulingDecision {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterSchedulingDecision)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 	*out = *in
@@ -665,6 +772,16 @@ func (in *ClusterSpec) DeepCopy() *ClusterSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 	*out = *in
+	if in.InService != nil {
+		in, out := &in.InService, &out.InService
+		*out = new(bool)
+		**out = **in
+	}
+	if in.Headroom != nil {
+		in, out := &in.Headroom, &out.Headroom
+		*out = new(ClusterHeadroom)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -678,6 +795,82 @@ func (in *ClusterStatus) DeepCopy() *ClusterStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterStrategyStatus) DeepCopyInto(out *ClusterStrategyStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterStrategyStatus.
+func (in *ClusterStrategyStatus) DeepCopy() *ClusterStrategyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterStrategyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterStrategyTemplate) DeepCopyInto(out *ClusterStrategyTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterStrategyTemplate.
+func (in *ClusterStrategyTemplate) DeepCopy() *ClusterStrategyTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterStrategyTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterStrategyTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterStrategyTemplateList) DeepCopyInto(out *ClusterStrategyTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterStrategyTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterStrategyTemplateList.
+func (in *ClusterStrategyTemplateList) DeepCopy() *ClusterStrategyTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterStrategyTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterStrategyTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterTrafficCondition) DeepCopyInto(out *ClusterTrafficCondition) {
 	*out = *in
@@ -860,6 +1053,22 @@ func (in *InstallationTargetStatus) DeepCopy() *InstallationTargetStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PodEvent) DeepCopyInto(out *PodEvent) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PodEvent.
+func (in *PodEvent) DeepCopy() *PodEvent {
+	if in == nil {
+		return nil
+	}
+	out := new(PodEvent)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PodStatus) DeepCopyInto(out *PodStatus) {
 	*out = *in
@@ -878,6 +1087,11 @@ func (in *PodStatus) DeepCopyInto(out *PodStatus) {
 		}
 	}
 	in.Condition.DeepCopyInto(&out.Condition)
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = make([]PodEvent, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -972,6 +1186,11 @@ func (in *ReleaseEnvironment) DeepCopyInto(out *ReleaseEnvironment) {
 		*out = new(RolloutStrategy)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.StrategyRef != nil {
+		in, out := &in.StrategyRef, &out.StrategyRef
+		*out = new(StrategyReference)
+		**out = **in
+	}
 	return
 }
 
@@ -985,6 +1204,38 @@ func (in *ReleaseEnvironment) DeepCopy() *ReleaseEnvironment {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReleaseHistoryEntry) DeepCopyInto(out *ReleaseHistoryEntry) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.InstallationDuration != nil {
+		in, out := &in.InstallationDuration, &out.InstallationDuration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.CapacityDuration != nil {
+		in, out := &in.CapacityDuration, &out.CapacityDuration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.TrafficDuration != nil {
+		in, out := &in.TrafficDuration, &out.TrafficDuration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReleaseHistoryEntry.
+func (in *ReleaseHistoryEntry) DeepCopy() *ReleaseHistoryEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(ReleaseHistoryEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReleaseList) DeepCopyInto(out *ReleaseList) {
 	*out = *in
@@ -1041,7 +1292,7 @@ func (in *ReleaseStatus) DeepCopyInto(out *ReleaseStatus) {
 	if in.AchievedStep != nil {
 		in, out := &in.AchievedStep, &out.AchievedStep
 		*out = new(AchievedStep)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Strategy != nil {
 		in, out := &in.Strategy, &out.Strategy
@@ -1055,6 +1306,18 @@ func (in *ReleaseStatus) DeepCopyInto(out *ReleaseStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]ReleaseHistoryEntry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SchedulingDecisions != nil {
+		in, out := &in.SchedulingDecisions, &out.SchedulingDecisions
+		*out = make([]ClusterSchedulingDecision, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -1112,6 +1375,11 @@ func (in *ReleaseStrategyStatus) DeepCopyInto(out *ReleaseStrategyStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]ClusterStrategyStatus, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -1125,6 +1393,42 @@ func (in *ReleaseStrategyStatus) DeepCopy() *ReleaseStrategyStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RollbackPolicy) DeepCopyInto(out *RollbackPolicy) {
+	*out = *in
+	if in.MaxSadPodPercent != nil {
+		in, out := &in.MaxSadPodPercent, &out.MaxSadPodPercent
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MaxRestarts != nil {
+		in, out := &in.MaxRestarts, &out.MaxRestarts
+		*out = new(int32)
+		**out = **in
+	}
+	if in.GracePeriod != nil {
+		in, out := &in.GracePeriod, &out.GracePeriod
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ProgressDeadline != nil {
+		in, out := &in.ProgressDeadline, &out.ProgressDeadline
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RollbackPolicy.
+func (in *RollbackPolicy) DeepCopy() *RollbackPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RollbackPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RolloutBlock) DeepCopyInto(out *RolloutBlock) {
 	*out = *in
@@ -1258,7 +1562,14 @@ func (in *RolloutStrategy) DeepCopyInto(out *RolloutStrategy) {
 	if in.Steps != nil {
 		in, out := &in.Steps, &out.Steps
 		*out = make([]RolloutStrategyStep, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ProgressDeadlineSeconds != nil {
+		in, out := &in.ProgressDeadlineSeconds, &out.ProgressDeadlineSeconds
+		*out = new(int32)
+		**out = **in
 	}
 	return
 }
@@ -1276,8 +1587,33 @@ func (in *RolloutStrategy) DeepCopy() *RolloutStrategy {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RolloutStrategyStep) DeepCopyInto(out *RolloutStrategyStep) {
 	*out = *in
-	out.Capacity = in.Capacity
-	out.Traffic = in.Traffic
+	in.Capacity.DeepCopyInto(&out.Capacity)
+	in.Traffic.DeepCopyInto(&out.Traffic)
+	if in.Pause != nil {
+		in, out := &in.Pause, &out.Pause
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ProgressDeadlineSeconds != nil {
+		in, out := &in.ProgressDeadlineSeconds, &out.ProgressDeadlineSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Analysis != nil {
+		in, out := &in.Analysis, &out.Analysis
+		*out = new(RolloutStrategyStepAnalysis)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = new(RolloutStrategyStepClusters)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Hooks != nil {
+		in, out := &in.Hooks, &out.Hooks
+		*out = new(RolloutStrategyStepHooks)
+		**out = **in
+	}
 	return
 }
 
@@ -1291,9 +1627,103 @@ func (in *RolloutStrategyStep) DeepCopy() *RolloutStrategyStep {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStrategyStepAnalysis) DeepCopyInto(out *RolloutStrategyStepAnalysis) {
+	*out = *in
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]RolloutStrategyStepMetric, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStrategyStepAnalysis.
+func (in *RolloutStrategyStepAnalysis) DeepCopy() *RolloutStrategyStepAnalysis {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStrategyStepAnalysis)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStrategyStepClusters) DeepCopyInto(out *RolloutStrategyStepClusters) {
+	*out = *in
+	if in.Names != nil {
+		in, out := &in.Names, &out.Names
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Regions != nil {
+		in, out := &in.Regions, &out.Regions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Ordinals != nil {
+		in, out := &in.Ordinals, &out.Ordinals
+		*out = make([]int32, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStrategyStepClusters.
+func (in *RolloutStrategyStepClusters) DeepCopy() *RolloutStrategyStepClusters {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStrategyStepClusters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStrategyStepHooks) DeepCopyInto(out *RolloutStrategyStepHooks) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStrategyStepHooks.
+func (in *RolloutStrategyStepHooks) DeepCopy() *RolloutStrategyStepHooks {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStrategyStepHooks)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RolloutStrategyStepMetric) DeepCopyInto(out *RolloutStrategyStepMetric) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStrategyStepMetric.
+func (in *RolloutStrategyStepMetric) DeepCopy() *RolloutStrategyStepMetric {
+	if in == nil {
+		return nil
+	}
+	out := new(RolloutStrategyStepMetric)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RolloutStrategyStepValue) DeepCopyInto(out *RolloutStrategyStepValue) {
 	*out = *in
+	if in.IncumbentReplicas != nil {
+		in, out := &in.IncumbentReplicas, &out.IncumbentReplicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ContenderReplicas != nil {
+		in, out := &in.ContenderReplicas, &out.ContenderReplicas
+		*out = new(int32)
+		**out = **in
+	}
 	return
 }
 
@@ -1307,6 +1737,158 @@ func (in *RolloutStrategyStepValue) DeepCopy() *RolloutStrategyStepValue {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StepApproval) DeepCopyInto(out *StepApproval) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StepApproval.
+func (in *StepApproval) DeepCopy() *StepApproval {
+	if in == nil {
+		return nil
+	}
+	out := new(StepApproval)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StepApproval) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StepApprovalList) DeepCopyInto(out *StepApprovalList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]StepApproval, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StepApprovalList.
+func (in *StepApprovalList) DeepCopy() *StepApprovalList {
+	if in == nil {
+		return nil
+	}
+	out := new(StepApprovalList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StepApprovalList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StepApprovalSpec) DeepCopyInto(out *StepApprovalSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StepApprovalSpec.
+func (in *StepApprovalSpec) DeepCopy() *StepApprovalSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StepApprovalSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StrategyReference) DeepCopyInto(out *StrategyReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StrategyReference.
+func (in *StrategyReference) DeepCopy() *StrategyReference {
+	if in == nil {
+		return nil
+	}
+	out := new(StrategyReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StrategyTemplate) DeepCopyInto(out *StrategyTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StrategyTemplate.
+func (in *StrategyTemplate) DeepCopy() *StrategyTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(StrategyTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StrategyTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StrategyTemplateList) DeepCopyInto(out *StrategyTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]StrategyTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StrategyTemplateList.
+func (in *StrategyTemplateList) DeepCopy() *StrategyTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(StrategyTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *StrategyTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TargetCondition) DeepCopyInto(out *TargetCondition) {
 	*out = *in
@@ -1439,3 +2021,35 @@ func (in *TrafficTargetStatus) DeepCopy() *TrafficTargetStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadCapacityStatus) DeepCopyInto(out *WorkloadCapacityStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadCapacityStatus.
+func (in *WorkloadCapacityStatus) DeepCopy() *WorkloadCapacityStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadCapacityStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadCapacityTarget) DeepCopyInto(out *WorkloadCapacityTarget) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadCapacityTarget.
+func (in *WorkloadCapacityTarget) DeepCopy() *WorkloadCapacityTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadCapacityTarget)
+	in.DeepCopyInto(out)
+	return out
+}
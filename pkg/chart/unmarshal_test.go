@@ -65,3 +65,40 @@ func TestGetDeploymentsValid(t *testing.T) {
 		t.Errorf("expected %d replicas but got %d", expectedReplicas, *d.Spec.Replicas)
 	}
 }
+
+const statefulSetText = `
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: my-stateful-app
+  namespace: default
+spec:
+  replicas: 3
+  serviceName: my-stateful-app
+  template:
+    spec:
+      containers:
+        - name: my-stateful-app
+          image: "redis:stable"
+`
+
+func TestGetStatefulSetsValid(t *testing.T) {
+	statefulSets := GetStatefulSets([]string{deploymentText, statefulSetText, somethingElseText, garbage})
+	if len(statefulSets) != 1 {
+		t.Fatalf("expected exactly one StatefulSet but got %d", len(statefulSets))
+	}
+
+	s := statefulSets[0]
+
+	const (
+		expectedName     = "my-stateful-app"
+		expectedReplicas = 3
+	)
+
+	if s.GetName() != expectedName {
+		t.Errorf("expected name %q but got %q", expectedName, s.GetName())
+	}
+	if *s.Spec.Replicas != expectedReplicas {
+		t.Errorf("expected %d replicas but got %d", expectedReplicas, *s.Spec.Replicas)
+	}
+}
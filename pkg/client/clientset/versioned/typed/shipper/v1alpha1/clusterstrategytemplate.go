@@ -0,0 +1,148 @@
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"time"
+
+	v1alpha1 "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+	scheme "github.com/bookingcom/shipper/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// ClusterStrategyTemplatesGetter has a method to return a ClusterStrategyTemplateInterface.
+// A group's client should implement this interface.
+type ClusterStrategyTemplatesGetter interface {
+	ClusterStrategyTemplates() ClusterStrategyTemplateInterface
+}
+
+// ClusterStrategyTemplateInterface has methods to work with ClusterStrategyTemplate resources.
+type ClusterStrategyTemplateInterface interface {
+	Create(*v1alpha1.ClusterStrategyTemplate) (*v1alpha1.ClusterStrategyTemplate, error)
+	Update(*v1alpha1.ClusterStrategyTemplate) (*v1alpha1.ClusterStrategyTemplate, error)
+	Delete(name string, options *v1.DeleteOptions) error
+	DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error
+	Get(name string, options v1.GetOptions) (*v1alpha1.ClusterStrategyTemplate, error)
+	List(opts v1.ListOptions) (*v1alpha1.ClusterStrategyTemplateList, error)
+	Watch(opts v1.ListOptions) (watch.Interface, error)
+	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.ClusterStrategyTemplate, err error)
+	ClusterStrategyTemplateExpansion
+}
+
+// clusterStrategyTemplates implements ClusterStrategyTemplateInterface
+type clusterStrategyTemplates struct {
+	client rest.Interface
+}
+
+// newClusterStrategyTemplates returns a ClusterStrategyTemplates
+func newClusterStrategyTemplates(c *ShipperV1alpha1Client) *clusterStrategyTemplates {
+	return &clusterStrategyTemplates{
+		client: c.RESTClient(),
+	}
+}
+
+// Get takes name of the clusterStrategyTemplate, and returns the corresponding clusterStrategyTemplate object, and an error if there is any.
+func (c *clusterStrategyTemplates) Get(name string, options v1.GetOptions) (result *v1alpha1.ClusterStrategyTemplate, err error) {
+	result = &v1alpha1.ClusterStrategyTemplate{}
+	err = c.client.Get().
+		Resource("clusterstrategytemplates").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of ClusterStrategyTemplates that match those selectors.
+func (c *clusterStrategyTemplates) List(opts v1.ListOptions) (result *v1alpha1.ClusterStrategyTemplateList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha1.ClusterStrategyTemplateList{}
+	err = c.client.Get().
+		Resource("clusterstrategytemplates").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do().
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested clusterStrategyTemplates.
+func (c *clusterStrategyTemplates) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Resource("clusterstrategytemplates").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch()
+}
+
+// Create takes the representation of a clusterStrategyTemplate and creates it.  Returns the server's representation of the clusterStrategyTemplate, and an error, if there is any.
+func (c *clusterStrategyTemplates) Create(clusterStrategyTemplate *v1alpha1.ClusterStrategyTemplate) (result *v1alpha1.ClusterStrategyTemplate, err error) {
+	result = &v1alpha1.ClusterStrategyTemplate{}
+	err = c.client.Post().
+		Resource("clusterstrategytemplates").
+		Body(clusterStrategyTemplate).
+		Do().
+		Into(result)
+	return
+}
+
+// Update takes the representation of a clusterStrategyTemplate and updates it. Returns the server's representation of the clusterStrategyTemplate, and an error, if there is any.
+func (c *clusterStrategyTemplates) Update(clusterStrategyTemplate *v1alpha1.ClusterStrategyTemplate) (result *v1alpha1.ClusterStrategyTemplate, err error) {
+	result = &v1alpha1.ClusterStrategyTemplate{}
+	err = c.client.Put().
+		Resource("clusterstrategytemplates").
+		Name(clusterStrategyTemplate.Name).
+		Body(clusterStrategyTemplate).
+		Do().
+		Into(result)
+	return
+}
+
+// Delete takes name of the clusterStrategyTemplate and deletes it. Returns an error if one occurs.
+func (c *clusterStrategyTemplates) Delete(name string, options *v1.DeleteOptions) error {
+	return c.client.Delete().
+		Resource("clusterstrategytemplates").
+		Name(name).
+		Body(options).
+		Do().
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *clusterStrategyTemplates) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	var timeout time.Duration
+	if listOptions.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOptions.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Resource("clusterstrategytemplates").
+		VersionedParams(&listOptions, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(options).
+		Do().
+		Error()
+}
+
+// Patch applies the patch and returns the patched clusterStrategyTemplate.
+func (c *clusterStrategyTemplates) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.ClusterStrategyTemplate, err error) {
+	result = &v1alpha1.ClusterStrategyTemplate{}
+	err = c.client.Patch(pt).
+		Resource("clusterstrategytemplates").
+		SubResource(subresources...).
+		Name(name).
+		Body(data).
+		Do().
+		Into(result)
+	return
+}
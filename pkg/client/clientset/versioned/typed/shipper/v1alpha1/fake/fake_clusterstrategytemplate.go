@@ -0,0 +1,104 @@
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	v1alpha1 "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeClusterStrategyTemplates implements ClusterStrategyTemplateInterface
+type FakeClusterStrategyTemplates struct {
+	Fake *FakeShipperV1alpha1
+}
+
+var clusterstrategytemplatesResource = schema.GroupVersionResource{Group: "shipper.booking.com", Version: "v1alpha1", Resource: "clusterstrategytemplates"}
+
+var clusterstrategytemplatesKind = schema.GroupVersionKind{Group: "shipper.booking.com", Version: "v1alpha1", Kind: "ClusterStrategyTemplate"}
+
+// Get takes name of the clusterStrategyTemplate, and returns the corresponding clusterStrategyTemplate object, and an error if there is any.
+func (c *FakeClusterStrategyTemplates) Get(name string, options v1.GetOptions) (result *v1alpha1.ClusterStrategyTemplate, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootGetAction(clusterstrategytemplatesResource, name), &v1alpha1.ClusterStrategyTemplate{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ClusterStrategyTemplate), err
+}
+
+// List takes label and field selectors, and returns the list of ClusterStrategyTemplates that match those selectors.
+func (c *FakeClusterStrategyTemplates) List(opts v1.ListOptions) (result *v1alpha1.ClusterStrategyTemplateList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootListAction(clusterstrategytemplatesResource, clusterstrategytemplatesKind, opts), &v1alpha1.ClusterStrategyTemplateList{})
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.ClusterStrategyTemplateList{ListMeta: obj.(*v1alpha1.ClusterStrategyTemplateList).ListMeta}
+	for _, item := range obj.(*v1alpha1.ClusterStrategyTemplateList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested clusterStrategyTemplates.
+func (c *FakeClusterStrategyTemplates) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewRootWatchAction(clusterstrategytemplatesResource, opts))
+}
+
+// Create takes the representation of a clusterStrategyTemplate and creates it.  Returns the server's representation of the clusterStrategyTemplate, and an error, if there is any.
+func (c *FakeClusterStrategyTemplates) Create(clusterStrategyTemplate *v1alpha1.ClusterStrategyTemplate) (result *v1alpha1.ClusterStrategyTemplate, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootCreateAction(clusterstrategytemplatesResource, clusterStrategyTemplate), &v1alpha1.ClusterStrategyTemplate{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ClusterStrategyTemplate), err
+}
+
+// Update takes the representation of a clusterStrategyTemplate and updates it. Returns the server's representation of the clusterStrategyTemplate, and an error, if there is any.
+func (c *FakeClusterStrategyTemplates) Update(clusterStrategyTemplate *v1alpha1.ClusterStrategyTemplate) (result *v1alpha1.ClusterStrategyTemplate, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootUpdateAction(clusterstrategytemplatesResource, clusterStrategyTemplate), &v1alpha1.ClusterStrategyTemplate{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ClusterStrategyTemplate), err
+}
+
+// Delete takes name of the clusterStrategyTemplate and deletes it. Returns an error if one occurs.
+func (c *FakeClusterStrategyTemplates) Delete(name string, options *v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewRootDeleteAction(clusterstrategytemplatesResource, name), &v1alpha1.ClusterStrategyTemplate{})
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeClusterStrategyTemplates) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	action := testing.NewRootDeleteCollectionAction(clusterstrategytemplatesResource, listOptions)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.ClusterStrategyTemplateList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched clusterStrategyTemplate.
+func (c *FakeClusterStrategyTemplates) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.ClusterStrategyTemplate, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewRootPatchSubresourceAction(clusterstrategytemplatesResource, name, pt, data, subresources...), &v1alpha1.ClusterStrategyTemplate{})
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ClusterStrategyTemplate), err
+}
@@ -24,6 +24,10 @@ func (c *FakeShipperV1alpha1) Clusters() v1alpha1.ClusterInterface {
 	return &FakeClusters{c}
 }
 
+func (c *FakeShipperV1alpha1) ClusterStrategyTemplates() v1alpha1.ClusterStrategyTemplateInterface {
+	return &FakeClusterStrategyTemplates{c}
+}
+
 func (c *FakeShipperV1alpha1) InstallationTargets(namespace string) v1alpha1.InstallationTargetInterface {
 	return &FakeInstallationTargets{c, namespace}
 }
@@ -36,6 +40,14 @@ func (c *FakeShipperV1alpha1) RolloutBlocks(namespace string) v1alpha1.RolloutBl
 	return &FakeRolloutBlocks{c, namespace}
 }
 
+func (c *FakeShipperV1alpha1) StepApprovals(namespace string) v1alpha1.StepApprovalInterface {
+	return &FakeStepApprovals{c, namespace}
+}
+
+func (c *FakeShipperV1alpha1) StrategyTemplates(namespace string) v1alpha1.StrategyTemplateInterface {
+	return &FakeStrategyTemplates{c, namespace}
+}
+
 func (c *FakeShipperV1alpha1) TrafficTargets(namespace string) v1alpha1.TrafficTargetInterface {
 	return &FakeTrafficTargets{c, namespace}
 }
@@ -0,0 +1,112 @@
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	v1alpha1 "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeStepApprovals implements StepApprovalInterface
+type FakeStepApprovals struct {
+	Fake *FakeShipperV1alpha1
+	ns   string
+}
+
+var stepapprovalsResource = schema.GroupVersionResource{Group: "shipper.booking.com", Version: "v1alpha1", Resource: "stepapprovals"}
+
+var stepapprovalsKind = schema.GroupVersionKind{Group: "shipper.booking.com", Version: "v1alpha1", Kind: "StepApproval"}
+
+// Get takes name of the stepApproval, and returns the corresponding stepApproval object, and an error if there is any.
+func (c *FakeStepApprovals) Get(name string, options v1.GetOptions) (result *v1alpha1.StepApproval, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(stepapprovalsResource, c.ns, name), &v1alpha1.StepApproval{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.StepApproval), err
+}
+
+// List takes label and field selectors, and returns the list of StepApprovals that match those selectors.
+func (c *FakeStepApprovals) List(opts v1.ListOptions) (result *v1alpha1.StepApprovalList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(stepapprovalsResource, stepapprovalsKind, c.ns, opts), &v1alpha1.StepApprovalList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.StepApprovalList{ListMeta: obj.(*v1alpha1.StepApprovalList).ListMeta}
+	for _, item := range obj.(*v1alpha1.StepApprovalList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested stepApprovals.
+func (c *FakeStepApprovals) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(stepapprovalsResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a stepApproval and creates it.  Returns the server's representation of the stepApproval, and an error, if there is any.
+func (c *FakeStepApprovals) Create(stepApproval *v1alpha1.StepApproval) (result *v1alpha1.StepApproval, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(stepapprovalsResource, c.ns, stepApproval), &v1alpha1.StepApproval{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.StepApproval), err
+}
+
+// Update takes the representation of a stepApproval and updates it. Returns the server's representation of the stepApproval, and an error, if there is any.
+func (c *FakeStepApprovals) Update(stepApproval *v1alpha1.StepApproval) (result *v1alpha1.StepApproval, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(stepapprovalsResource, c.ns, stepApproval), &v1alpha1.StepApproval{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.StepApproval), err
+}
+
+// Delete takes name of the stepApproval and deletes it. Returns an error if one occurs.
+func (c *FakeStepApprovals) Delete(name string, options *v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(stepapprovalsResource, c.ns, name), &v1alpha1.StepApproval{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeStepApprovals) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(stepapprovalsResource, c.ns, listOptions)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.StepApprovalList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched stepApproval.
+func (c *FakeStepApprovals) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.StepApproval, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(stepapprovalsResource, c.ns, name, pt, data, subresources...), &v1alpha1.StepApproval{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.StepApproval), err
+}
@@ -0,0 +1,112 @@
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	v1alpha1 "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeStrategyTemplates implements StrategyTemplateInterface
+type FakeStrategyTemplates struct {
+	Fake *FakeShipperV1alpha1
+	ns   string
+}
+
+var strategytemplatesResource = schema.GroupVersionResource{Group: "shipper.booking.com", Version: "v1alpha1", Resource: "strategytemplates"}
+
+var strategytemplatesKind = schema.GroupVersionKind{Group: "shipper.booking.com", Version: "v1alpha1", Kind: "StrategyTemplate"}
+
+// Get takes name of the strategyTemplate, and returns the corresponding strategyTemplate object, and an error if there is any.
+func (c *FakeStrategyTemplates) Get(name string, options v1.GetOptions) (result *v1alpha1.StrategyTemplate, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(strategytemplatesResource, c.ns, name), &v1alpha1.StrategyTemplate{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.StrategyTemplate), err
+}
+
+// List takes label and field selectors, and returns the list of StrategyTemplates that match those selectors.
+func (c *FakeStrategyTemplates) List(opts v1.ListOptions) (result *v1alpha1.StrategyTemplateList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(strategytemplatesResource, strategytemplatesKind, c.ns, opts), &v1alpha1.StrategyTemplateList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.StrategyTemplateList{ListMeta: obj.(*v1alpha1.StrategyTemplateList).ListMeta}
+	for _, item := range obj.(*v1alpha1.StrategyTemplateList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested strategyTemplates.
+func (c *FakeStrategyTemplates) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(strategytemplatesResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a strategyTemplate and creates it.  Returns the server's representation of the strategyTemplate, and an error, if there is any.
+func (c *FakeStrategyTemplates) Create(strategyTemplate *v1alpha1.StrategyTemplate) (result *v1alpha1.StrategyTemplate, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(strategytemplatesResource, c.ns, strategyTemplate), &v1alpha1.StrategyTemplate{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.StrategyTemplate), err
+}
+
+// Update takes the representation of a strategyTemplate and updates it. Returns the server's representation of the strategyTemplate, and an error, if there is any.
+func (c *FakeStrategyTemplates) Update(strategyTemplate *v1alpha1.StrategyTemplate) (result *v1alpha1.StrategyTemplate, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(strategytemplatesResource, c.ns, strategyTemplate), &v1alpha1.StrategyTemplate{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.StrategyTemplate), err
+}
+
+// Delete takes name of the strategyTemplate and deletes it. Returns an error if one occurs.
+func (c *FakeStrategyTemplates) Delete(name string, options *v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(strategytemplatesResource, c.ns, name), &v1alpha1.StrategyTemplate{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeStrategyTemplates) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(strategytemplatesResource, c.ns, listOptions)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.StrategyTemplateList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched strategyTemplate.
+func (c *FakeStrategyTemplates) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.StrategyTemplate, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(strategytemplatesResource, c.ns, name, pt, data, subresources...), &v1alpha1.StrategyTemplate{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.StrategyTemplate), err
+}
@@ -8,10 +8,16 @@ type CapacityTargetExpansion interface{}
 
 type ClusterExpansion interface{}
 
+type ClusterStrategyTemplateExpansion interface{}
+
 type InstallationTargetExpansion interface{}
 
 type ReleaseExpansion interface{}
 
 type RolloutBlockExpansion interface{}
 
+type StepApprovalExpansion interface{}
+
+type StrategyTemplateExpansion interface{}
+
 type TrafficTargetExpansion interface{}
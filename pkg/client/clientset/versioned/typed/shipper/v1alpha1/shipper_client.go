@@ -13,9 +13,12 @@ type ShipperV1alpha1Interface interface {
 	ApplicationsGetter
 	CapacityTargetsGetter
 	ClustersGetter
+	ClusterStrategyTemplatesGetter
 	InstallationTargetsGetter
 	ReleasesGetter
 	RolloutBlocksGetter
+	StepApprovalsGetter
+	StrategyTemplatesGetter
 	TrafficTargetsGetter
 }
 
@@ -36,6 +39,10 @@ func (c *ShipperV1alpha1Client) Clusters() ClusterInterface {
 	return newClusters(c)
 }
 
+func (c *ShipperV1alpha1Client) ClusterStrategyTemplates() ClusterStrategyTemplateInterface {
+	return newClusterStrategyTemplates(c)
+}
+
 func (c *ShipperV1alpha1Client) InstallationTargets(namespace string) InstallationTargetInterface {
 	return newInstallationTargets(c, namespace)
 }
@@ -48,6 +55,14 @@ func (c *ShipperV1alpha1Client) RolloutBlocks(namespace string) RolloutBlockInte
 	return newRolloutBlocks(c, namespace)
 }
 
+func (c *ShipperV1alpha1Client) StepApprovals(namespace string) StepApprovalInterface {
+	return newStepApprovals(c, namespace)
+}
+
+func (c *ShipperV1alpha1Client) StrategyTemplates(namespace string) StrategyTemplateInterface {
+	return newStrategyTemplates(c, namespace)
+}
+
 func (c *ShipperV1alpha1Client) TrafficTargets(namespace string) TrafficTargetInterface {
 	return newTrafficTargets(c, namespace)
 }
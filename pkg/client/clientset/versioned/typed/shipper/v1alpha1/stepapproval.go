@@ -0,0 +1,158 @@
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"time"
+
+	v1alpha1 "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+	scheme "github.com/bookingcom/shipper/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// StepApprovalsGetter has a method to return a StepApprovalInterface.
+// A group's client should implement this interface.
+type StepApprovalsGetter interface {
+	StepApprovals(namespace string) StepApprovalInterface
+}
+
+// StepApprovalInterface has methods to work with StepApproval resources.
+type StepApprovalInterface interface {
+	Create(*v1alpha1.StepApproval) (*v1alpha1.StepApproval, error)
+	Update(*v1alpha1.StepApproval) (*v1alpha1.StepApproval, error)
+	Delete(name string, options *v1.DeleteOptions) error
+	DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error
+	Get(name string, options v1.GetOptions) (*v1alpha1.StepApproval, error)
+	List(opts v1.ListOptions) (*v1alpha1.StepApprovalList, error)
+	Watch(opts v1.ListOptions) (watch.Interface, error)
+	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.StepApproval, err error)
+	StepApprovalExpansion
+}
+
+// stepApprovals implements StepApprovalInterface
+type stepApprovals struct {
+	client rest.Interface
+	ns     string
+}
+
+// newStepApprovals returns a StepApprovals
+func newStepApprovals(c *ShipperV1alpha1Client, namespace string) *stepApprovals {
+	return &stepApprovals{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the stepApproval, and returns the corresponding stepApproval object, and an error if there is any.
+func (c *stepApprovals) Get(name string, options v1.GetOptions) (result *v1alpha1.StepApproval, err error) {
+	result = &v1alpha1.StepApproval{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("stepapprovals").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of StepApprovals that match those selectors.
+func (c *stepApprovals) List(opts v1.ListOptions) (result *v1alpha1.StepApprovalList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha1.StepApprovalList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("stepapprovals").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do().
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested stepApprovals.
+func (c *stepApprovals) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("stepapprovals").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch()
+}
+
+// Create takes the representation of a stepApproval and creates it.  Returns the server's representation of the stepApproval, and an error, if there is any.
+func (c *stepApprovals) Create(stepApproval *v1alpha1.StepApproval) (result *v1alpha1.StepApproval, err error) {
+	result = &v1alpha1.StepApproval{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("stepapprovals").
+		Body(stepApproval).
+		Do().
+		Into(result)
+	return
+}
+
+// Update takes the representation of a stepApproval and updates it. Returns the server's representation of the stepApproval, and an error, if there is any.
+func (c *stepApprovals) Update(stepApproval *v1alpha1.StepApproval) (result *v1alpha1.StepApproval, err error) {
+	result = &v1alpha1.StepApproval{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("stepapprovals").
+		Name(stepApproval.Name).
+		Body(stepApproval).
+		Do().
+		Into(result)
+	return
+}
+
+// Delete takes name of the stepApproval and deletes it. Returns an error if one occurs.
+func (c *stepApprovals) Delete(name string, options *v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("stepapprovals").
+		Name(name).
+		Body(options).
+		Do().
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *stepApprovals) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	var timeout time.Duration
+	if listOptions.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOptions.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("stepapprovals").
+		VersionedParams(&listOptions, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(options).
+		Do().
+		Error()
+}
+
+// Patch applies the patch and returns the patched stepApproval.
+func (c *stepApprovals) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.StepApproval, err error) {
+	result = &v1alpha1.StepApproval{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("stepapprovals").
+		SubResource(subresources...).
+		Name(name).
+		Body(data).
+		Do().
+		Into(result)
+	return
+}
@@ -0,0 +1,158 @@
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"time"
+
+	v1alpha1 "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+	scheme "github.com/bookingcom/shipper/pkg/client/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// StrategyTemplatesGetter has a method to return a StrategyTemplateInterface.
+// A group's client should implement this interface.
+type StrategyTemplatesGetter interface {
+	StrategyTemplates(namespace string) StrategyTemplateInterface
+}
+
+// StrategyTemplateInterface has methods to work with StrategyTemplate resources.
+type StrategyTemplateInterface interface {
+	Create(*v1alpha1.StrategyTemplate) (*v1alpha1.StrategyTemplate, error)
+	Update(*v1alpha1.StrategyTemplate) (*v1alpha1.StrategyTemplate, error)
+	Delete(name string, options *v1.DeleteOptions) error
+	DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error
+	Get(name string, options v1.GetOptions) (*v1alpha1.StrategyTemplate, error)
+	List(opts v1.ListOptions) (*v1alpha1.StrategyTemplateList, error)
+	Watch(opts v1.ListOptions) (watch.Interface, error)
+	Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.StrategyTemplate, err error)
+	StrategyTemplateExpansion
+}
+
+// strategyTemplates implements StrategyTemplateInterface
+type strategyTemplates struct {
+	client rest.Interface
+	ns     string
+}
+
+// newStrategyTemplates returns a StrategyTemplates
+func newStrategyTemplates(c *ShipperV1alpha1Client, namespace string) *strategyTemplates {
+	return &strategyTemplates{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the strategyTemplate, and returns the corresponding strategyTemplate object, and an error if there is any.
+func (c *strategyTemplates) Get(name string, options v1.GetOptions) (result *v1alpha1.StrategyTemplate, err error) {
+	result = &v1alpha1.StrategyTemplate{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("strategytemplates").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do().
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of StrategyTemplates that match those selectors.
+func (c *strategyTemplates) List(opts v1.ListOptions) (result *v1alpha1.StrategyTemplateList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha1.StrategyTemplateList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("strategytemplates").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do().
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested strategyTemplates.
+func (c *strategyTemplates) Watch(opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("strategytemplates").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch()
+}
+
+// Create takes the representation of a strategyTemplate and creates it.  Returns the server's representation of the strategyTemplate, and an error, if there is any.
+func (c *strategyTemplates) Create(strategyTemplate *v1alpha1.StrategyTemplate) (result *v1alpha1.StrategyTemplate, err error) {
+	result = &v1alpha1.StrategyTemplate{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("strategytemplates").
+		Body(strategyTemplate).
+		Do().
+		Into(result)
+	return
+}
+
+// Update takes the representation of a strategyTemplate and updates it. Returns the server's representation of the strategyTemplate, and an error, if there is any.
+func (c *strategyTemplates) Update(strategyTemplate *v1alpha1.StrategyTemplate) (result *v1alpha1.StrategyTemplate, err error) {
+	result = &v1alpha1.StrategyTemplate{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("strategytemplates").
+		Name(strategyTemplate.Name).
+		Body(strategyTemplate).
+		Do().
+		Into(result)
+	return
+}
+
+// Delete takes name of the strategyTemplate and deletes it. Returns an error if one occurs.
+func (c *strategyTemplates) Delete(name string, options *v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("strategytemplates").
+		Name(name).
+		Body(options).
+		Do().
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *strategyTemplates) DeleteCollection(options *v1.DeleteOptions, listOptions v1.ListOptions) error {
+	var timeout time.Duration
+	if listOptions.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOptions.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("strategytemplates").
+		VersionedParams(&listOptions, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(options).
+		Do().
+		Error()
+}
+
+// Patch applies the patch and returns the patched strategyTemplate.
+func (c *strategyTemplates) Patch(name string, pt types.PatchType, data []byte, subresources ...string) (result *v1alpha1.StrategyTemplate, err error) {
+	result = &v1alpha1.StrategyTemplate{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("strategytemplates").
+		SubResource(subresources...).
+		Name(name).
+		Body(data).
+		Do().
+		Into(result)
+	return
+}
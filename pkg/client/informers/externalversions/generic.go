@@ -43,12 +43,18 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Shipper().V1alpha1().CapacityTargets().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("clusters"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Shipper().V1alpha1().Clusters().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("clusterstrategytemplates"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Shipper().V1alpha1().ClusterStrategyTemplates().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("installationtargets"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Shipper().V1alpha1().InstallationTargets().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("releases"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Shipper().V1alpha1().Releases().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("rolloutblocks"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Shipper().V1alpha1().RolloutBlocks().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("stepapprovals"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Shipper().V1alpha1().StepApprovals().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("strategytemplates"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Shipper().V1alpha1().StrategyTemplates().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("traffictargets"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Shipper().V1alpha1().TrafficTargets().Informer()}, nil
 
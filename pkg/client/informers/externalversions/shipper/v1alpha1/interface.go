@@ -14,12 +14,18 @@ type Interface interface {
 	CapacityTargets() CapacityTargetInformer
 	// Clusters returns a ClusterInformer.
 	Clusters() ClusterInformer
+	// ClusterStrategyTemplates returns a ClusterStrategyTemplateInformer.
+	ClusterStrategyTemplates() ClusterStrategyTemplateInformer
 	// InstallationTargets returns a InstallationTargetInformer.
 	InstallationTargets() InstallationTargetInformer
 	// Releases returns a ReleaseInformer.
 	Releases() ReleaseInformer
 	// RolloutBlocks returns a RolloutBlockInformer.
 	RolloutBlocks() RolloutBlockInformer
+	// StepApprovals returns a StepApprovalInformer.
+	StepApprovals() StepApprovalInformer
+	// StrategyTemplates returns a StrategyTemplateInformer.
+	StrategyTemplates() StrategyTemplateInformer
 	// TrafficTargets returns a TrafficTargetInformer.
 	TrafficTargets() TrafficTargetInformer
 }
@@ -50,6 +56,11 @@ func (v *version) Clusters() ClusterInformer {
 	return &clusterInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
 }
 
+// ClusterStrategyTemplates returns a ClusterStrategyTemplateInformer.
+func (v *version) ClusterStrategyTemplates() ClusterStrategyTemplateInformer {
+	return &clusterStrategyTemplateInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
 // InstallationTargets returns a InstallationTargetInformer.
 func (v *version) InstallationTargets() InstallationTargetInformer {
 	return &installationTargetInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -65,6 +76,16 @@ func (v *version) RolloutBlocks() RolloutBlockInformer {
 	return &rolloutBlockInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// StepApprovals returns a StepApprovalInformer.
+func (v *version) StepApprovals() StepApprovalInformer {
+	return &stepApprovalInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// StrategyTemplates returns a StrategyTemplateInformer.
+func (v *version) StrategyTemplates() StrategyTemplateInformer {
+	return &strategyTemplateInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // TrafficTargets returns a TrafficTargetInformer.
 func (v *version) TrafficTargets() TrafficTargetInformer {
 	return &trafficTargetInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
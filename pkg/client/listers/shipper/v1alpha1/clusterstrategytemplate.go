@@ -0,0 +1,49 @@
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// ClusterStrategyTemplateLister helps list ClusterStrategyTemplates.
+type ClusterStrategyTemplateLister interface {
+	// List lists all ClusterStrategyTemplates in the indexer.
+	List(selector labels.Selector) (ret []*v1alpha1.ClusterStrategyTemplate, err error)
+	// Get retrieves the ClusterStrategyTemplate from the index for a given name.
+	Get(name string) (*v1alpha1.ClusterStrategyTemplate, error)
+	ClusterStrategyTemplateListerExpansion
+}
+
+// clusterStrategyTemplateLister implements the ClusterStrategyTemplateLister interface.
+type clusterStrategyTemplateLister struct {
+	indexer cache.Indexer
+}
+
+// NewClusterStrategyTemplateLister returns a new ClusterStrategyTemplateLister.
+func NewClusterStrategyTemplateLister(indexer cache.Indexer) ClusterStrategyTemplateLister {
+	return &clusterStrategyTemplateLister{indexer: indexer}
+}
+
+// List lists all ClusterStrategyTemplates in the indexer.
+func (s *clusterStrategyTemplateLister) List(selector labels.Selector) (ret []*v1alpha1.ClusterStrategyTemplate, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.ClusterStrategyTemplate))
+	})
+	return ret, err
+}
+
+// Get retrieves the ClusterStrategyTemplate from the index for a given name.
+func (s *clusterStrategyTemplateLister) Get(name string) (*v1alpha1.ClusterStrategyTemplate, error) {
+	obj, exists, err := s.indexer.GetByKey(name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("clusterstrategytemplate"), name)
+	}
+	return obj.(*v1alpha1.ClusterStrategyTemplate), nil
+}
@@ -22,6 +22,10 @@ type CapacityTargetNamespaceListerExpansion interface{}
 // ClusterLister.
 type ClusterListerExpansion interface{}
 
+// ClusterStrategyTemplateListerExpansion allows custom methods to be added to
+// ClusterStrategyTemplateLister.
+type ClusterStrategyTemplateListerExpansion interface{}
+
 // InstallationTargetListerExpansion allows custom methods to be added to
 // InstallationTargetLister.
 type InstallationTargetListerExpansion interface{}
@@ -38,6 +42,22 @@ type RolloutBlockListerExpansion interface{}
 // RolloutBlockNamespaceLister.
 type RolloutBlockNamespaceListerExpansion interface{}
 
+// StepApprovalListerExpansion allows custom methods to be added to
+// StepApprovalLister.
+type StepApprovalListerExpansion interface{}
+
+// StepApprovalNamespaceListerExpansion allows custom methods to be added to
+// StepApprovalNamespaceLister.
+type StepApprovalNamespaceListerExpansion interface{}
+
+// StrategyTemplateListerExpansion allows custom methods to be added to
+// StrategyTemplateLister.
+type StrategyTemplateListerExpansion interface{}
+
+// StrategyTemplateNamespaceListerExpansion allows custom methods to be added to
+// StrategyTemplateNamespaceLister.
+type StrategyTemplateNamespaceListerExpansion interface{}
+
 // TrafficTargetListerExpansion allows custom methods to be added to
 // TrafficTargetLister.
 type TrafficTargetListerExpansion interface{}
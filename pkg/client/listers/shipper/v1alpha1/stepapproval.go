@@ -0,0 +1,78 @@
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// StepApprovalLister helps list StepApprovals.
+type StepApprovalLister interface {
+	// List lists all StepApprovals in the indexer.
+	List(selector labels.Selector) (ret []*v1alpha1.StepApproval, err error)
+	// StepApprovals returns an object that can list and get StepApprovals.
+	StepApprovals(namespace string) StepApprovalNamespaceLister
+	StepApprovalListerExpansion
+}
+
+// stepApprovalLister implements the StepApprovalLister interface.
+type stepApprovalLister struct {
+	indexer cache.Indexer
+}
+
+// NewStepApprovalLister returns a new StepApprovalLister.
+func NewStepApprovalLister(indexer cache.Indexer) StepApprovalLister {
+	return &stepApprovalLister{indexer: indexer}
+}
+
+// List lists all StepApprovals in the indexer.
+func (s *stepApprovalLister) List(selector labels.Selector) (ret []*v1alpha1.StepApproval, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.StepApproval))
+	})
+	return ret, err
+}
+
+// StepApprovals returns an object that can list and get StepApprovals.
+func (s *stepApprovalLister) StepApprovals(namespace string) StepApprovalNamespaceLister {
+	return stepApprovalNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// StepApprovalNamespaceLister helps list and get StepApprovals.
+type StepApprovalNamespaceLister interface {
+	// List lists all StepApprovals in the indexer for a given namespace.
+	List(selector labels.Selector) (ret []*v1alpha1.StepApproval, err error)
+	// Get retrieves the StepApproval from the indexer for a given namespace and name.
+	Get(name string) (*v1alpha1.StepApproval, error)
+	StepApprovalNamespaceListerExpansion
+}
+
+// stepApprovalNamespaceLister implements the StepApprovalNamespaceLister
+// interface.
+type stepApprovalNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all StepApprovals in the indexer for a given namespace.
+func (s stepApprovalNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.StepApproval, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.StepApproval))
+	})
+	return ret, err
+}
+
+// Get retrieves the StepApproval from the indexer for a given namespace and name.
+func (s stepApprovalNamespaceLister) Get(name string) (*v1alpha1.StepApproval, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("stepapproval"), name)
+	}
+	return obj.(*v1alpha1.StepApproval), nil
+}
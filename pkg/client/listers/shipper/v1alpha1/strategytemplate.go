@@ -0,0 +1,78 @@
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// StrategyTemplateLister helps list StrategyTemplates.
+type StrategyTemplateLister interface {
+	// List lists all StrategyTemplates in the indexer.
+	List(selector labels.Selector) (ret []*v1alpha1.StrategyTemplate, err error)
+	// StrategyTemplates returns an object that can list and get StrategyTemplates.
+	StrategyTemplates(namespace string) StrategyTemplateNamespaceLister
+	StrategyTemplateListerExpansion
+}
+
+// strategyTemplateLister implements the StrategyTemplateLister interface.
+type strategyTemplateLister struct {
+	indexer cache.Indexer
+}
+
+// NewStrategyTemplateLister returns a new StrategyTemplateLister.
+func NewStrategyTemplateLister(indexer cache.Indexer) StrategyTemplateLister {
+	return &strategyTemplateLister{indexer: indexer}
+}
+
+// List lists all StrategyTemplates in the indexer.
+func (s *strategyTemplateLister) List(selector labels.Selector) (ret []*v1alpha1.StrategyTemplate, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.StrategyTemplate))
+	})
+	return ret, err
+}
+
+// StrategyTemplates returns an object that can list and get StrategyTemplates.
+func (s *strategyTemplateLister) StrategyTemplates(namespace string) StrategyTemplateNamespaceLister {
+	return strategyTemplateNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// StrategyTemplateNamespaceLister helps list and get StrategyTemplates.
+type StrategyTemplateNamespaceLister interface {
+	// List lists all StrategyTemplates in the indexer for a given namespace.
+	List(selector labels.Selector) (ret []*v1alpha1.StrategyTemplate, err error)
+	// Get retrieves the StrategyTemplate from the indexer for a given namespace and name.
+	Get(name string) (*v1alpha1.StrategyTemplate, error)
+	StrategyTemplateNamespaceListerExpansion
+}
+
+// strategyTemplateNamespaceLister implements the StrategyTemplateNamespaceLister
+// interface.
+type strategyTemplateNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all StrategyTemplates in the indexer for a given namespace.
+func (s strategyTemplateNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.StrategyTemplate, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.StrategyTemplate))
+	})
+	return ret, err
+}
+
+// Get retrieves the StrategyTemplate from the indexer for a given namespace and name.
+func (s strategyTemplateNamespaceLister) Get(name string) (*v1alpha1.StrategyTemplate, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("strategytemplate"), name)
+	}
+	return obj.(*v1alpha1.StrategyTemplate), nil
+}
@@ -52,9 +52,18 @@ type Controller struct {
 	rbLister listers.RolloutBlockLister
 	rbSynced cache.InformerSynced
 
+	ctLister listers.CapacityTargetLister
+	ctSynced cache.InformerSynced
+
+	stLister  listers.StrategyTemplateLister
+	stSynced  cache.InformerSynced
+	cstLister listers.ClusterStrategyTemplateLister
+	cstSynced cache.InformerSynced
+
 	versionResolver shipperrepo.ChartVersionResolver
 
 	recorder record.EventRecorder
+	now      func() time.Time
 }
 
 // NewController returns a new Application controller.
@@ -67,6 +76,9 @@ func NewController(
 	appInformer := shipperInformerFactory.Shipper().V1alpha1().Applications()
 	relInformer := shipperInformerFactory.Shipper().V1alpha1().Releases()
 	rbInformer := shipperInformerFactory.Shipper().V1alpha1().RolloutBlocks()
+	ctInformer := shipperInformerFactory.Shipper().V1alpha1().CapacityTargets()
+	stInformer := shipperInformerFactory.Shipper().V1alpha1().StrategyTemplates()
+	cstInformer := shipperInformerFactory.Shipper().V1alpha1().ClusterStrategyTemplates()
 
 	c := &Controller{
 		shipperClientset: shipperClientset,
@@ -81,8 +93,17 @@ func NewController(
 		rbLister: rbInformer.Lister(),
 		rbSynced: rbInformer.Informer().HasSynced,
 
+		ctLister: ctInformer.Lister(),
+		ctSynced: ctInformer.Informer().HasSynced,
+
+		stLister:  stInformer.Lister(),
+		stSynced:  stInformer.Informer().HasSynced,
+		cstLister: cstInformer.Lister(),
+		cstSynced: cstInformer.Informer().HasSynced,
+
 		versionResolver: versionResolver,
 		recorder:        recorder,
+		now:             time.Now,
 	}
 
 	appInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
@@ -105,6 +126,12 @@ func NewController(
 		DeleteFunc: c.enqueueAppFromRolloutBlock,
 	})
 
+	ctInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		UpdateFunc: func(old, new interface{}) {
+			c.enqueueAppFromCapacityTarget(new)
+		},
+	})
+
 	return c
 }
 
@@ -117,7 +144,7 @@ func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) {
 	klog.V(2).Info("Starting Application controller")
 	defer klog.V(2).Info("Shutting down Application controller")
 
-	if !cache.WaitForCacheSync(stopCh, c.appSynced, c.relSynced, c.rbSynced) {
+	if !cache.WaitForCacheSync(stopCh, c.appSynced, c.relSynced, c.rbSynced, c.ctSynced, c.stSynced, c.cstSynced) {
 		runtime.HandleError(fmt.Errorf("failed to sync caches for the Application controller"))
 		return
 	}
@@ -227,6 +254,24 @@ func (c *Controller) enqueueAppFromRolloutBlock(obj interface{}) {
 	}
 }
 
+// enqueueAppFromCapacityTarget enqueues the application a capacity target
+// belongs to, so its rollback policy gets to look at the latest capacity
+// status.
+func (c *Controller) enqueueAppFromCapacityTarget(obj interface{}) {
+	ct, ok := obj.(*shipper.CapacityTarget)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("not a shipper.CapacityTarget: %#v", obj))
+		return
+	}
+
+	appName, ok := ct.Labels[shipper.AppLabel]
+	if !ok {
+		return
+	}
+
+	c.workqueue.Add(fmt.Sprintf("%s/%s", ct.Namespace, appName))
+}
+
 func (c *Controller) syncApplication(key string) error {
 	ns, name, err := cache.SplitMetaNamespaceKey(key)
 	if err != nil {
@@ -509,6 +554,12 @@ func (c *Controller) processApplication(app *shipper.Application) error {
 			corev1.ConditionTrue,
 			"",
 			fmt.Sprintf("abort in progress, returning state to release %q", contender.Name))
+		if cond := apputil.GetApplicationCondition(app.Status, shipper.ApplicationConditionTypeAborting); cond != nil &&
+			cond.Status == corev1.ConditionTrue && cond.Reason == conditions.ContenderUnhealthy {
+			// Shipper aborted the rollout by itself, so keep
+			// telling users why.
+			abortingCond = cond.DeepCopy()
+		}
 		diff.Append(apputil.SetApplicationCondition(&app.Status, *abortingCond))
 
 		rollingOutCond := apputil.NewApplicationCondition(
@@ -531,7 +582,11 @@ func (c *Controller) processApplication(app *shipper.Application) error {
 		highestObserved = generation
 	}
 
-	if !identicalEnvironments(app.Spec.Template, contender.Spec.Environment) {
+	if identicalEnvironments(app.Spec.Template, contender.Spec.Environment) {
+		if rolledBack, err := c.rollBackUnhealthyContender(app, contender, appReleases, diff); err != nil || rolledBack {
+			return err
+		}
+	} else {
 		// The application's template has been modified and is different than
 		// the contender's environment. This means that a new release should
 		// be created with the new template.
@@ -140,6 +140,70 @@ func TestCreateFirstRelease(t *testing.T) {
 	f.run()
 }
 
+// TestCreateFirstReleaseRequestedBy verifies that releases are told who
+// changed the application they were created for.
+func TestCreateFirstReleaseRequestedBy(t *testing.T) {
+	f := newFixture(t)
+	app := newApplication(testAppName)
+	app.Annotations[shipper.AppSpecChangedByAnnotation] = "alice"
+
+	f.objects = append(f.objects, app)
+	expectedApp := app.DeepCopy()
+	expectedApp.Annotations[shipper.AppHighestObservedGenerationAnnotation] = "0"
+	apputil.UpdateChartNameAnnotation(expectedApp, "simple")
+	apputil.UpdateChartVersionRawAnnotation(expectedApp, "0.0.1")
+	apputil.UpdateChartVersionResolvedAnnotation(expectedApp, "0.0.1")
+	expectedApp.Spec.Template.Chart.Version = "0.0.1"
+
+	envHash := hashReleaseEnvironment(expectedApp.Spec.Template)
+	expectedRelName := fmt.Sprintf("%s-%s-0", testAppName, envHash)
+
+	expectedApp.Status.Conditions = []shipper.ApplicationCondition{
+		{
+			Type:   shipper.ApplicationConditionTypeAborting,
+			Status: corev1.ConditionFalse,
+		},
+		{
+			Type:   shipper.ApplicationConditionTypeBlocked,
+			Status: corev1.ConditionFalse,
+		},
+		{
+			Type:   shipper.ApplicationConditionTypeReleaseSynced,
+			Status: corev1.ConditionTrue,
+		},
+		{
+			Type:    shipper.ApplicationConditionTypeRollingOut,
+			Status:  corev1.ConditionTrue,
+			Message: fmt.Sprintf(InitialReleaseMessageFormat, expectedRelName),
+		},
+		{
+			Type:   shipper.ApplicationConditionTypeValidHistory,
+			Status: corev1.ConditionTrue,
+		},
+	}
+	expectedApp.Status.History = []string{expectedRelName}
+
+	// We do not expect entries in the history or 'RollingOut: true' in the state
+	// because the testing client does not update listers after Create actions.
+
+	expectedRelease := newRelease(expectedRelName, expectedApp)
+	expectedRelease.Labels[shipper.ReleaseEnvironmentHashLabel] = envHash
+	expectedRelease.Annotations[shipper.ReleaseTemplateIterationAnnotation] = "0"
+	expectedRelease.Annotations[shipper.ReleaseGenerationAnnotation] = "0"
+	expectedRelease.Annotations[shipper.RolloutBlocksOverrideAnnotation] = ""
+	expectedRelease.Annotations[shipper.ReleaseRequestedByAnnotation] = "alice"
+
+	f.expectReleaseCreate(expectedRelease)
+	f.expectApplicationUpdate(expectedApp)
+
+	f.expectedEvents = []string{
+		fmt.Sprintf(`Normal ApplicationConditionChanged [] -> [Aborting False], [] -> [ValidHistory True], [] -> [ReleaseSynced True], [] -> [RollingOut True Rolling out initial release "%s"]`, expectedRelease.Name),
+		"Normal ApplicationConditionChanged [] -> [Blocked False]",
+	}
+
+	f.run()
+}
+
 func TestCreateFirstReleaseWithChartVersionResolve(t *testing.T) {
 	f := newFixture(t)
 	app := newApplication(testAppName)
@@ -1218,6 +1282,7 @@ type fixture struct {
 	expectedEvents []string
 
 	resolveChartVersion shipperrepo.ChartVersionResolver
+	now                 func() time.Time
 }
 
 func newFixture(t *testing.T) *fixture {
@@ -1238,6 +1303,9 @@ func (f *fixture) newController() (*Controller, shipperinformers.SharedInformerF
 	shipperInformerFactory := shipperinformers.NewSharedInformerFactory(f.client, noResyncPeriod)
 
 	c := NewController(f.client, shipperInformerFactory, f.resolveChartVersion, f.recorder)
+	if f.now != nil {
+		c.now = f.now
+	}
 
 	return c, shipperInformerFactory
 }
@@ -1284,6 +1352,13 @@ func (f *fixture) expectReleaseCreate(rel *shipper.Release) {
 	f.actions = append(f.actions, action)
 }
 
+func (f *fixture) expectReleaseUpdate(rel *shipper.Release) {
+	gvr := shipper.SchemeGroupVersion.WithResource("releases")
+	action := kubetesting.NewUpdateAction(gvr, rel.GetNamespace(), rel)
+
+	f.actions = append(f.actions, action)
+}
+
 func (f *fixture) expectReleaseDelete(rel *shipper.Release) {
 	gvr := shipper.SchemeGroupVersion.WithResource("releases")
 	action := kubetesting.NewDeleteAction(gvr, rel.GetNamespace(), rel.GetName())
@@ -15,6 +15,7 @@ import (
 	"github.com/bookingcom/shipper/pkg/controller"
 	"github.com/bookingcom/shipper/pkg/errors"
 	shippererrors "github.com/bookingcom/shipper/pkg/errors"
+	strategyutil "github.com/bookingcom/shipper/pkg/util/strategy"
 )
 
 func (c *Controller) createReleaseForApplication(app *shipper.Application, releaseName string, iteration, generation int) (*shipper.Release, error) {
@@ -51,6 +52,10 @@ func (c *Controller) createReleaseForApplication(app *shipper.Application, relea
 		newRelease.Labels[k] = v
 	}
 
+	if requestedBy, ok := app.Annotations[shipper.AppSpecChangedByAnnotation]; ok {
+		newRelease.Annotations[shipper.ReleaseRequestedByAnnotation] = requestedBy
+	}
+
 	// application may contain semver range, need to convert it into a specific version
 	cv, err := c.versionResolver(&newRelease.Spec.Environment.Chart)
 	if err != nil {
@@ -58,6 +63,16 @@ func (c *Controller) createReleaseForApplication(app *shipper.Application, relea
 	}
 	newRelease.Spec.Environment.Chart.Version = cv.Version
 
+	// Releases carry the strategy they were created with, so later
+	// changes to the template they refer to don't affect them.
+	if ref := newRelease.Spec.Environment.StrategyRef; ref != nil {
+		strategy, err := strategyutil.ResolveStrategyReference(ref, app.Namespace, c.stLister, c.cstLister)
+		if err != nil {
+			return nil, err
+		}
+		newRelease.Spec.Environment.Strategy = strategy
+	}
+
 	klog.V(4).Infof("Release %q labels: %v", controller.MetaKey(newRelease), newRelease.Labels)
 	klog.V(4).Infof("Release %q annotations: %v", controller.MetaKey(newRelease), newRelease.Annotations)
 
@@ -130,6 +145,11 @@ func identicalEnvironments(envs ...shipper.ReleaseEnvironment) bool {
 
 func hashReleaseEnvironment(env shipper.ReleaseEnvironment) string {
 	copy := env.DeepCopy()
+	if copy.StrategyRef != nil {
+		// The strategy of a release is resolved from its reference,
+		// and the application doesn't have it.
+		copy.Strategy = nil
+	}
 	b, err := json.Marshal(copy)
 	if err != nil {
 		// TODO(btyler) ???
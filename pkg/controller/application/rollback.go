@@ -0,0 +1,210 @@
+package application
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog"
+
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+	"github.com/bookingcom/shipper/pkg/controller"
+	shippererrors "github.com/bookingcom/shipper/pkg/errors"
+	apputil "github.com/bookingcom/shipper/pkg/util/application"
+	"github.com/bookingcom/shipper/pkg/util/conditions"
+	diffutil "github.com/bookingcom/shipper/pkg/util/diff"
+	releaseutil "github.com/bookingcom/shipper/pkg/util/release"
+)
+
+// rollBackUnhealthyContender deletes the contender if the application's
+// rollback policy considers it unhealthy, after recording why in the
+// incumbent's history. Deleting the contender is the same
+// thing users do to abort a rollout by hand, so from then on the application
+// is reverted to the incumbent's environment by the regular abort process.
+// It returns true if the contender was deleted.
+func (c *Controller) rollBackUnhealthyContender(
+	app *shipper.Application,
+	contender *shipper.Release,
+	rels []*shipper.Release,
+	diff *diffutil.MultiDiff,
+) (bool, error) {
+	policy := app.Spec.RollbackPolicy
+	if policy == nil || releaseutil.ReleaseComplete(contender) || contender.DeletionTimestamp != nil {
+		return false, nil
+	}
+
+	// Without an incumbent there is nothing to roll back to.
+	incumbent, err := apputil.GetIncumbent(app.Name, rels)
+	if err != nil {
+		if shippererrors.IsIncumbentNotFoundError(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	ct, err := c.ctLister.CapacityTargets(contender.Namespace).Get(contender.Name)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			// The release controller hasn't gotten around to
+			// creating it yet.
+			ct = nil
+		} else {
+			return false, shippererrors.NewKubeclientGetError(contender.Namespace, contender.Name, err).
+				WithShipperKind("CapacityTarget")
+		}
+	}
+
+	now := c.now()
+	reason, recheckAfter := contenderUnhealthyReason(policy, contender, ct, now)
+	if reason == "" {
+		if recheckAfter > 0 {
+			c.workqueue.AddAfter(controller.MetaKey(app), recheckAfter)
+		}
+		return false, nil
+	}
+
+	msg := fmt.Sprintf("%s, rolling back to release %q", reason, incumbent.Name)
+	klog.Infof("Application %q: %s", controller.MetaKey(app), msg)
+	c.recorder.Event(app, corev1.EventTypeWarning, conditions.ContenderUnhealthy, msg)
+
+	// The contender's own history goes away with it, so the rollback is
+	// recorded in the history of the release it rolls back to.
+	incumbent = incumbent.DeepCopy()
+	if releaseutil.AppendAbortedHistory(&incumbent.Status, contender, reason, now) {
+		_, err = c.shipperClientset.ShipperV1alpha1().Releases(incumbent.Namespace).Update(incumbent)
+		if err != nil {
+			return false, shippererrors.NewKubeclientUpdateError(incumbent, err).
+				WithShipperKind("Release")
+		}
+	}
+
+	err = c.shipperClientset.ShipperV1alpha1().Releases(contender.Namespace).Delete(contender.Name, &metav1.DeleteOptions{})
+	if err != nil && !kerrors.IsNotFound(err) {
+		return false, shippererrors.NewKubeclientDeleteError(contender.Namespace, contender.Name, err).
+			WithShipperKind("Release")
+	}
+
+	abortingCond := apputil.NewApplicationCondition(
+		shipper.ApplicationConditionTypeAborting,
+		corev1.ConditionTrue,
+		conditions.ContenderUnhealthy,
+		msg,
+	)
+	diff.Append(apputil.SetApplicationCondition(&app.Status, *abortingCond))
+
+	return true, nil
+}
+
+// contenderUnhealthyReason checks a contender against a rollback policy. If
+// the contender should be rolled back, it returns a message explaining why.
+// Otherwise, it returns how long to wait before checking again, or 0 if
+// there is no point in checking until something changes.
+func contenderUnhealthyReason(
+	policy *shipper.RollbackPolicy,
+	contender *shipper.Release,
+	ct *shipper.CapacityTarget,
+	now time.Time,
+) (string, time.Duration) {
+	var recheckAfter time.Duration
+	recheckIn := func(d time.Duration) {
+		if recheckAfter == 0 || d < recheckAfter {
+			recheckAfter = d
+		}
+	}
+
+	if policy.ProgressDeadline != nil && !releaseAchievedTargetStep(contender) {
+		lastProgress := releaseutil.LastProgressTime(contender)
+		stalled := now.Sub(lastProgress)
+		if stalled >= policy.ProgressDeadline.Duration {
+			return fmt.Sprintf(
+				"contender %q has not achieved step %d for %s (deadline %s)",
+				contender.Name, contender.Spec.TargetStep,
+				stalled.Round(time.Second), policy.ProgressDeadline.Duration,
+			), 0
+		}
+		recheckIn(policy.ProgressDeadline.Duration - stalled)
+	}
+
+	if ct == nil {
+		return "", recheckAfter
+	}
+
+	var gracePeriod time.Duration
+	if policy.GracePeriod != nil {
+		gracePeriod = policy.GracePeriod.Duration
+	}
+
+	for _, status := range ct.Status.Clusters {
+		reason := clusterUnhealthyReason(policy, status)
+		if reason == "" {
+			continue
+		}
+
+		// The Ready condition went False when the cluster started
+		// having trouble, so that is how long it has been unhealthy.
+		var unhealthySince time.Time
+		for _, cond := range status.Conditions {
+			if cond.Type == shipper.ClusterConditionTypeReady && cond.Status == corev1.ConditionFalse {
+				unhealthySince = cond.LastTransitionTime.Time
+			}
+		}
+		if unhealthySince.IsZero() {
+			unhealthySince = now
+		}
+
+		unhealthyFor := now.Sub(unhealthySince)
+		if unhealthyFor >= gracePeriod {
+			return fmt.Sprintf("contender %q is unhealthy in cluster %q: %s", contender.Name, status.Name, reason), 0
+		}
+		recheckIn(gracePeriod - unhealthyFor)
+	}
+
+	return "", recheckAfter
+}
+
+func clusterUnhealthyReason(policy *shipper.RollbackPolicy, status shipper.ClusterCapacityStatus) string {
+	if policy.MaxSadPodPercent != nil {
+		var total, sad uint32
+		for _, report := range status.Reports {
+			for _, breakdown := range report.Breakdown {
+				if breakdown.Type != string(corev1.PodReady) {
+					continue
+				}
+				total += breakdown.Count
+				if breakdown.Status != string(corev1.ConditionTrue) {
+					sad += breakdown.Count
+				}
+			}
+		}
+		if total > 0 {
+			percent := int32(sad * 100 / total)
+			if percent > *policy.MaxSadPodPercent {
+				return fmt.Sprintf("%d%% of pods are not Ready (max %d%%)", percent, *policy.MaxSadPodPercent)
+			}
+		}
+	}
+
+	if policy.MaxRestarts != nil {
+		for _, pod := range status.SadPods {
+			for _, containers := range [][]corev1.ContainerStatus{pod.InitContainers, pod.Containers} {
+				for _, container := range containers {
+					if container.RestartCount > *policy.MaxRestarts {
+						return fmt.Sprintf(
+							"container %q in pod %q restarted %d times (max %d)",
+							container.Name, pod.Name, container.RestartCount, *policy.MaxRestarts,
+						)
+					}
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+func releaseAchievedTargetStep(rel *shipper.Release) bool {
+	achieved := rel.Status.AchievedStep
+	return achieved != nil && achieved.Step == rel.Spec.TargetStep
+}
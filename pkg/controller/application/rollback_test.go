@@ -0,0 +1,210 @@
+package application
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+	apputil "github.com/bookingcom/shipper/pkg/util/application"
+	"github.com/bookingcom/shipper/pkg/util/conditions"
+)
+
+func buildUnhealthyCapacityStatus(readyPods, sadPods uint32, restarts int32, unhealthyFor time.Duration) shipper.ClusterCapacityStatus {
+	return shipper.ClusterCapacityStatus{
+		Name: "minikube",
+		Conditions: []shipper.ClusterCapacityCondition{
+			{
+				Type:               shipper.ClusterConditionTypeReady,
+				Status:             corev1.ConditionFalse,
+				LastTransitionTime: metav1.NewTime(time.Now().Add(-unhealthyFor)),
+			},
+		},
+		Reports: []shipper.ClusterCapacityReport{
+			{
+				Breakdown: []shipper.ClusterCapacityReportBreakdown{
+					{Type: string(corev1.PodReady), Status: string(corev1.ConditionTrue), Count: readyPods},
+					{Type: string(corev1.PodReady), Status: string(corev1.ConditionFalse), Count: sadPods},
+				},
+			},
+		},
+		SadPods: []shipper.PodStatus{
+			{
+				Name: "sad-pod",
+				Containers: []corev1.ContainerStatus{
+					{Name: "app", RestartCount: restarts},
+				},
+			},
+		},
+	}
+}
+
+func TestContenderUnhealthyReason(t *testing.T) {
+	maxSadPodPercent := int32(50)
+	maxRestarts := int32(3)
+	gracePeriod := metav1.Duration{Duration: 5 * time.Minute}
+	progressDeadline := metav1.Duration{Duration: 30 * time.Minute}
+
+	tests := []struct {
+		name           string
+		policy         shipper.RollbackPolicy
+		status         shipper.ClusterCapacityStatus
+		createdAgo     time.Duration
+		expectedReason string
+		expectRecheck  bool
+	}{
+		{
+			name:   "healthy",
+			policy: shipper.RollbackPolicy{MaxSadPodPercent: &maxSadPodPercent, MaxRestarts: &maxRestarts},
+			status: buildUnhealthyCapacityStatus(9, 1, 1, time.Hour),
+		},
+		{
+			name:           "too many sad pods",
+			policy:         shipper.RollbackPolicy{MaxSadPodPercent: &maxSadPodPercent},
+			status:         buildUnhealthyCapacityStatus(2, 8, 0, time.Hour),
+			expectedReason: "80% of pods are not Ready (max 50%)",
+		},
+		{
+			name:           "too many restarts",
+			policy:         shipper.RollbackPolicy{MaxRestarts: &maxRestarts},
+			status:         buildUnhealthyCapacityStatus(9, 1, 5, time.Hour),
+			expectedReason: `container "app" in pod "sad-pod" restarted 5 times (max 3)`,
+		},
+		{
+			name:          "within grace period",
+			policy:        shipper.RollbackPolicy{MaxRestarts: &maxRestarts, GracePeriod: &gracePeriod},
+			status:        buildUnhealthyCapacityStatus(9, 1, 5, time.Minute),
+			expectRecheck: true,
+		},
+		{
+			name:           "no progress",
+			policy:         shipper.RollbackPolicy{ProgressDeadline: &progressDeadline},
+			createdAgo:     time.Hour,
+			expectedReason: "has not achieved step 1",
+		},
+		{
+			name:          "progress deadline not reached",
+			policy:        shipper.RollbackPolicy{ProgressDeadline: &progressDeadline},
+			createdAgo:    time.Minute,
+			expectRecheck: true,
+		},
+	}
+
+	for _, tt := range tests {
+		contender := newRelease("test-contender", newApplication(testAppName))
+		contender.CreationTimestamp = metav1.NewTime(time.Now().Add(-tt.createdAgo))
+		contender.Spec.TargetStep = 1
+
+		ct := &shipper.CapacityTarget{
+			Status: shipper.CapacityTargetStatus{
+				Clusters: []shipper.ClusterCapacityStatus{tt.status},
+			},
+		}
+
+		reason, recheckAfter := contenderUnhealthyReason(&tt.policy, contender, ct, time.Now())
+		if tt.expectedReason == "" && reason != "" {
+			t.Errorf("%s: expected contender to be healthy, got %q", tt.name, reason)
+		} else if !strings.Contains(reason, tt.expectedReason) {
+			t.Errorf("%s: expected reason to contain %q, got %q", tt.name, tt.expectedReason, reason)
+		}
+		if tt.expectRecheck != (recheckAfter > 0) {
+			t.Errorf("%s: expected recheck to be %t, got %s", tt.name, tt.expectRecheck, recheckAfter)
+		}
+	}
+}
+
+func TestRollBackUnhealthyContender(t *testing.T) {
+	f := newFixture(t)
+
+	maxRestarts := int32(3)
+	app := newApplication(testAppName)
+	app.Annotations[shipper.AppHighestObservedGenerationAnnotation] = "1"
+	app.Spec.RollbackPolicy = &shipper.RollbackPolicy{MaxRestarts: &maxRestarts}
+
+	envHash := hashReleaseEnvironment(app.Spec.Template)
+	incumbentName := fmt.Sprintf("%s-%s-0", testAppName, envHash)
+	contenderName := fmt.Sprintf("%s-%s-1", testAppName, envHash)
+	app.Status.History = []string{incumbentName, contenderName}
+
+	f.objects = append(f.objects, app)
+
+	incumbent := newRelease(incumbentName, app)
+	incumbent.Annotations[shipper.ReleaseGenerationAnnotation] = "0"
+	incumbent.Status.Conditions = []shipper.ReleaseCondition{
+		{Type: shipper.ReleaseConditionTypeComplete, Status: corev1.ConditionTrue},
+	}
+	f.objects = append(f.objects, incumbent)
+
+	contender := newRelease(contenderName, app)
+	contender.Annotations[shipper.ReleaseGenerationAnnotation] = "1"
+	f.objects = append(f.objects, contender)
+
+	ct := &shipper.CapacityTarget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      contenderName,
+			Namespace: contender.Namespace,
+			Labels: map[string]string{
+				shipper.AppLabel:     testAppName,
+				shipper.ReleaseLabel: contenderName,
+			},
+		},
+		Status: shipper.CapacityTargetStatus{
+			Clusters: []shipper.ClusterCapacityStatus{
+				buildUnhealthyCapacityStatus(0, 1, 10, time.Hour),
+			},
+		},
+	}
+	f.objects = append(f.objects, ct)
+
+	msg := fmt.Sprintf(
+		`contender %q is unhealthy in cluster "minikube": container "app" in pod "sad-pod" restarted 10 times (max 3), rolling back to release %q`,
+		contenderName, incumbentName,
+	)
+
+	expectedApp := app.DeepCopy()
+	apputil.UpdateChartNameAnnotation(expectedApp, "simple")
+	apputil.UpdateChartVersionRawAnnotation(expectedApp, "0.0.1")
+	apputil.UpdateChartVersionResolvedAnnotation(expectedApp, "0.0.1")
+	expectedApp.Status.Conditions = []shipper.ApplicationCondition{
+		{
+			Type:    shipper.ApplicationConditionTypeAborting,
+			Status:  corev1.ConditionTrue,
+			Reason:  conditions.ContenderUnhealthy,
+			Message: msg,
+		},
+		{
+			Type:   shipper.ApplicationConditionTypeBlocked,
+			Status: corev1.ConditionFalse,
+		},
+	}
+
+	now := time.Now().Truncate(time.Second)
+	f.now = func() time.Time { return now }
+
+	reason := strings.TrimSuffix(msg, fmt.Sprintf(", rolling back to release %q", incumbentName))
+	expectedIncumbent := incumbent.DeepCopy()
+	expectedIncumbent.Status.History = []shipper.ReleaseHistoryEntry{
+		{
+			Type:     shipper.ReleaseHistoryAborted,
+			Time:     metav1.NewTime(now),
+			Step:     contender.Spec.TargetStep,
+			StepName: contender.Spec.Environment.Strategy.Steps[contender.Spec.TargetStep].Name,
+			Message:  fmt.Sprintf("release %q was aborted: %s", contenderName, reason),
+		},
+	}
+
+	f.expectReleaseUpdate(expectedIncumbent)
+	f.expectReleaseDelete(contender)
+	f.expectApplicationUpdate(expectedApp)
+
+	f.expectedEvents = []string{
+		fmt.Sprintf("Warning ContenderUnhealthy %s", msg),
+		fmt.Sprintf("Normal ApplicationConditionChanged [] -> [Blocked False], [] -> [Aborting True ContenderUnhealthy %s]", msg),
+	}
+
+	f.run()
+}
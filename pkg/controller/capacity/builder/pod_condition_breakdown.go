@@ -25,8 +25,13 @@ type PodConditionBreakdown struct {
 	podConditionReason string
 
 	containerStateBreakdownBuilders containerStateBreakdownBuilders
+	eventBreakdowns                 []*shipper.ClusterCapacityReportEventBreakdown
 }
 
+// EventBreakdownLimit is how many of the most common reasons pods were
+// warned about are kept in a breakdown.
+const EventBreakdownLimit = 5
+
 func NewPodConditionBreakdown(
 	initialPodCount uint32,
 	podConditionType string,
@@ -63,6 +68,44 @@ func (p *PodConditionBreakdown) AddOrIncrementContainerState(
 	return p
 }
 
+// AddPodEvents counts a pod once for each of the reasons it was warned
+// about. The first pod warned about a reason is kept as its example.
+func (p *PodConditionBreakdown) AddPodEvents(podExampleName string, events []shipper.PodEvent) *PodConditionBreakdown {
+	seen := map[string]struct{}{}
+	for _, event := range events {
+		if _, ok := seen[event.Reason]; ok {
+			continue
+		}
+		seen[event.Reason] = struct{}{}
+
+		p.addOrIncrementEvent(podExampleName, event)
+	}
+	return p
+}
+
+func (p *PodConditionBreakdown) addOrIncrementEvent(podExampleName string, event shipper.PodEvent) {
+	for _, b := range p.eventBreakdowns {
+		if b.Reason == event.Reason {
+			b.Count += 1
+			return
+		}
+	}
+
+	var m *string
+	if len(event.Message) > 0 {
+		m = &event.Message
+	}
+
+	p.eventBreakdowns = append(p.eventBreakdowns, &shipper.ClusterCapacityReportEventBreakdown{
+		Count:  1,
+		Reason: event.Reason,
+		Example: shipper.ClusterCapacityReportContainerBreakdownExample{
+			Pod:     podExampleName,
+			Message: m,
+		},
+	})
+}
+
 func (p *PodConditionBreakdown) IncrementCount() *PodConditionBreakdown {
 	p.podCount += 1
 	return p
@@ -80,11 +123,28 @@ func (p *PodConditionBreakdown) Build() shipper.ClusterCapacityReportBreakdown {
 		return orderedContainers[i].Name < orderedContainers[j].Name
 	})
 
+	var orderedEvents []shipper.ClusterCapacityReportEventBreakdown
+	for _, v := range p.eventBreakdowns {
+		orderedEvents = append(orderedEvents, *v)
+	}
+
+	sort.SliceStable(orderedEvents, func(i, j int) bool {
+		if orderedEvents[i].Count == orderedEvents[j].Count {
+			return orderedEvents[i].Reason < orderedEvents[j].Reason
+		}
+		return orderedEvents[i].Count > orderedEvents[j].Count
+	})
+
+	if len(orderedEvents) > EventBreakdownLimit {
+		orderedEvents = orderedEvents[:EventBreakdownLimit]
+	}
+
 	return shipper.ClusterCapacityReportBreakdown{
 		Type:       p.podConditionType,
 		Status:     p.podConditionStatus,
 		Count:      p.podCount,
 		Reason:     p.podConditionReason,
 		Containers: orderedContainers,
+		Events:     orderedEvents,
 	}
 }
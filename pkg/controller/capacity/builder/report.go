@@ -34,12 +34,19 @@ func NewReport(ownerName string) *Report {
 	}
 }
 
-func (r *Report) AddPod(pod *core_v1.Pod) {
+// AddPod adds a pod to the breakdowns of each of its conditions. Warnings
+// Kubernetes recorded about the pod are only added to the breakdowns of
+// conditions that aren't True, as those are the ones they might explain.
+func (r *Report) AddPod(pod *core_v1.Pod, events ...shipper.PodEvent) {
 	for _, cond := range pod.Status.Conditions {
 		b := r.podConditionBreakdownBuilders.
 			Get(string(cond.Type), string(cond.Status), string(cond.Reason)).
 			IncrementCount()
 
+		if cond.Status != core_v1.ConditionTrue {
+			b.AddPodEvents(pod.Name, events)
+		}
+
 		for _, containerStatus := range pod.Status.ContainerStatuses {
 			b.AddOrIncrementContainerState(
 				containerStatus.Name,
@@ -308,3 +308,59 @@ func TestReportTwoContainersTwoPodsTwoConditions(t *testing.T) {
 		t.Errorf("expected is different from actual:\n%s", text)
 	}
 }
+
+func TestReportTwoPodsOneConditionWithEvents(t *testing.T) {
+	ownerName := "owner"
+	unhealthy := "Readiness probe failed"
+	failedMount := "MountVolume.SetUp failed"
+	actual := NewReport(ownerName).
+		AddPodConditionBreakdownBuilder(
+			NewPodConditionBreakdown(2, "Ready", "False", "").
+				AddPodEvents("pod-a", []shipper.PodEvent{
+					{Reason: "Unhealthy", Message: unhealthy},
+					{Reason: "Unhealthy", Message: "Liveness probe failed"},
+				}).
+				AddPodEvents("pod-b", []shipper.PodEvent{
+					{Reason: "FailedMount", Message: failedMount},
+					{Reason: "Unhealthy", Message: "Liveness probe failed"},
+				})).
+		Build()
+
+	expected := &shipper.ClusterCapacityReport{
+		Owner: shipper.ClusterCapacityReportOwner{Name: ownerName},
+		Breakdown: []shipper.ClusterCapacityReportBreakdown{
+			{
+				Type:       "Ready",
+				Status:     "False",
+				Count:      2,
+				Containers: []shipper.ClusterCapacityReportContainerBreakdown{},
+				Events: []shipper.ClusterCapacityReportEventBreakdown{
+					{
+						Count:  2,
+						Reason: "Unhealthy",
+						Example: shipper.ClusterCapacityReportContainerBreakdownExample{
+							Pod:     "pod-a",
+							Message: &unhealthy,
+						},
+					},
+					{
+						Count:  1,
+						Reason: "FailedMount",
+						Example: shipper.ClusterCapacityReportContainerBreakdownExample{
+							Pod:     "pod-b",
+							Message: &failedMount,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	text, err := shippertesting.YamlDiff(expected, actual)
+	if err != nil {
+		t.Errorf("an error occurred: %s", err)
+	}
+	if len(text) > 0 {
+		t.Errorf("expected is different from actual:\n%s", text)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
+	"strings"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -45,6 +46,12 @@ const (
 	InternalError    = "InternalError"
 	PodsNotReady     = "PodsNotReady"
 	DeploymentStuck  = "DeploymentStuck"
+	ScalingDown      = "ScalingDown"
+
+	// ScaleDownInterval is how often capacity targets scaling down
+	// workloads a few replicas at a time are looked at again to take the
+	// next step, in case nothing else brings them up sooner.
+	ScaleDownInterval = 10 * time.Second
 
 	CapacityTargetConditionChanged  = "CapacityTargetConditionChanged"
 	ClusterCapacityConditionChanged = "ClusterCapacityConditionChanged"
@@ -187,16 +194,18 @@ func (c *Controller) processCapacityTargetOnCluster(
 
 	var (
 		availableReplicas int32
+		achievedPercent   int32
 		sadPods           []shipper.PodStatus
 		reports           []shipper.ClusterCapacityReport
+		workloadStatuses  []shipper.WorkloadCapacityStatus
 	)
 
 	defer func() {
 		status.SadPods = sadPods
 		status.Reports = reports
 		status.AvailableReplicas = availableReplicas
-		status.AchievedPercent = c.calculatePercentageFromAmount(
-			spec.TotalReplicaCount, availableReplicas)
+		status.AchievedPercent = achievedPercent
+		status.Workloads = workloadStatuses
 
 		diff.Append(capacityutil.SetClusterCapacityCondition(status, *operationalCond))
 		diff.Append(capacityutil.SetClusterCapacityCondition(status, *readyCond))
@@ -205,7 +214,7 @@ func (c *Controller) processCapacityTargetOnCluster(
 
 	appName := ct.Labels[shipper.AppLabel]
 	release := ct.Labels[shipper.ReleaseLabel]
-	deployment, pods, err := c.getClusterObjects(spec.Name, ct.Namespace, appName, release)
+	workloads, err := c.getClusterObjects(spec, ct.Namespace, appName, release)
 	if err != nil {
 		operationalCond = capacityutil.NewClusterCapacityCondition(
 			shipper.ClusterConditionTypeOperational,
@@ -222,16 +231,30 @@ func (c *Controller) processCapacityTargetOnCluster(
 		"",
 		"")
 
-	report := buildReport(deployment.Name, pods)
-
-	// availableReplicas and reports will be used by the defer at the top
-	// of this func
-	availableReplicas = deployment.Status.AvailableReplicas
-	reports = []shipper.ClusterCapacityReport{*report}
+	// Every workload is brought to the same percentage of its own
+	// replicas. The cluster is only as far along as the workload
+	// furthest behind, and only Ready once all of them are.
+	var notReady *workloadCapacity
+	for i, w := range workloads {
+		capacity, err := c.processWorkloadOnCluster(ct, spec.Name, spec.Percent, spec.Replicas, w)
+
+		// availableReplicas, achievedPercent, reports and
+		// workloadStatuses will be used by the defer at the top of
+		// this func
+		availableReplicas += capacity.availableReplicas
+		if i == 0 || capacity.achievedPercent < achievedPercent {
+			achievedPercent = capacity.achievedPercent
+		}
+		reports = append(reports, *capacity.report)
+		if len(spec.Workloads) > 0 {
+			workloadStatuses = append(workloadStatuses, shipper.WorkloadCapacityStatus{
+				Kind:              w.kind(),
+				Name:              w.object().GetName(),
+				AvailableReplicas: capacity.availableReplicas,
+				AchievedPercent:   capacity.achievedPercent,
+			})
+		}
 
-	desiredReplicas := int32(replicas.CalculateDesiredReplicaCount(uint(spec.TotalReplicaCount), float64(spec.Percent)))
-	if deployment.Spec.Replicas == nil || desiredReplicas != *deployment.Spec.Replicas {
-		_, err = c.patchDeploymentWithReplicaCount(deployment, spec.Name, desiredReplicas)
 		if err != nil {
 			readyCond = capacityutil.NewClusterCapacityCondition(
 				shipper.ClusterConditionTypeReady,
@@ -239,35 +262,21 @@ func (c *Controller) processCapacityTargetOnCluster(
 				InternalError,
 				err.Error(),
 			)
+
 			return err
-		} else {
-			readyCond = capacityutil.NewClusterCapacityCondition(
-				shipper.ClusterConditionTypeReady,
-				corev1.ConditionFalse,
-				InProgress,
-				"",
-			)
-			return nil
 		}
-	}
 
-	// Deployment was successfully updated, but the update hasn't been
-	// observed by the deployment controller yet, so our change is still in
-	// flight, and we can't trust the status yet.
-	if deployment.Generation > deployment.Status.ObservedGeneration {
-		readyCond = capacityutil.NewClusterCapacityCondition(
-			shipper.ClusterConditionTypeReady,
-			corev1.ConditionFalse,
-			InProgress,
-			"",
-		)
+		sadPods = append(sadPods, capacity.sadPods...)
+		if !capacity.ready && notReady == nil {
+			notReady = capacity
+		}
+	}
 
-		return nil
+	if len(sadPods) > SadPodLimit {
+		sadPods = sadPods[:SadPodLimit]
 	}
 
-	// If the number of available replicas matches what we want, the
-	// CapacityTarget is Ready and there's nothing left to check.
-	if replicas.AchievedDesiredReplicaPercentage(spec.TotalReplicaCount, availableReplicas, spec.Percent) {
+	if notReady == nil {
 		readyCond = capacityutil.NewClusterCapacityCondition(
 			shipper.ClusterConditionTypeReady,
 			corev1.ConditionTrue,
@@ -278,57 +287,164 @@ func (c *Controller) processCapacityTargetOnCluster(
 		return nil
 	}
 
-	// Not all pods are availble, so we know for sure this cluster isn't
-	// ready. From here on out we just try to figure out why to give users
-	// a good place to start looking.
+	msg := notReady.message
+	if len(workloads) > 1 && msg != "" {
+		msg = fmt.Sprintf("%s %q: %s", notReady.kind, notReady.name, msg)
+	}
 
-	// sadPods will be used by the defer at the top of this func
-	sadPods = c.getSadPods(pods)
-	if len(sadPods) > SadPodLimit {
-		sadPods = sadPods[:SadPodLimit]
+	readyCond = capacityutil.NewClusterCapacityCondition(
+		shipper.ClusterConditionTypeReady,
+		corev1.ConditionFalse,
+		notReady.reason,
+		msg,
+	)
+
+	return nil
+}
+
+// workloadCapacity is how far along a single workload in a cluster is
+// towards the capacity it was asked for, and if it isn't there yet, why.
+type workloadCapacity struct {
+	kind              string
+	name              string
+	availableReplicas int32
+	achievedPercent   int32
+	report            *shipper.ClusterCapacityReport
+	sadPods           []shipper.PodStatus
+	ready             bool
+	reason            string
+	message           string
+}
+
+// processWorkloadOnCluster scales a workload to the given percentage of
+// its replicas, or to the given number of them when that's set, and tells
+// how far along it is.
+func (c *Controller) processWorkloadOnCluster(
+	ct *shipper.CapacityTarget,
+	clusterName string,
+	percent int32,
+	replicaCount *int32,
+	w clusterWorkload,
+) (*workloadCapacity, error) {
+	availableReplicas := w.availableReplicas()
+	capacity := &workloadCapacity{
+		kind:              w.kind(),
+		name:              w.object().GetName(),
+		availableReplicas: availableReplicas,
+		achievedPercent:   c.calculatePercentageFromAmount(w.totalReplicaCount, availableReplicas),
+		report:            buildReport(w.object().GetName(), w.pods, w.podEvents),
+		reason:            InProgress,
+	}
+
+	// Workloads scaled by an HPA get their capacity through the HPA,
+	// and are at capacity once they have as many replicas as the HPA
+	// currently allows. HPA bounds only scale by percentage, so steps
+	// asking for a number of replicas get the smallest percentage of the
+	// workload's replicas that gets to that many.
+	var (
+		desiredReplicas int32
+		changed         bool
+		scaleDown       string
+		err             error
+	)
+	if w.hpa != nil {
+		if replicaCount != nil {
+			percent = replicas.CalculateReplicasPercentage(w.totalReplicaCount, *replicaCount)
+		}
+
+		desiredReplicas, changed, err = c.scaleWithHorizontalPodAutoscaler(clusterName, percent, w.workload, w.hpa)
+		if err == nil && desiredReplicas > 0 {
+			capacity.achievedPercent = hpaAchievedPercent(percent, desiredReplicas, availableReplicas)
+		}
+	} else {
+		desiredReplicas = int32(replicas.CalculateDesiredReplicaCount(uint(w.totalReplicaCount), float64(percent)))
+		if replicaCount != nil {
+			desiredReplicas = replicas.CalculateDesiredReplicaCountFromReplicas(w.totalReplicaCount, *replicaCount)
+		}
+
+		// Scaling down might have to go a few replicas at a time, in
+		// which case scaleDown tells why it isn't all the way down yet.
+		switch current := w.replicas(); {
+		case current != nil && desiredReplicas < *current:
+			var nextReplicas int32
+			nextReplicas, scaleDown, err = c.scaleDownStep(ct, clusterName, w, *current, desiredReplicas)
+			if err == nil && nextReplicas != *current {
+				err = c.patchWorkloadWithReplicaCount(w.workload, clusterName, nextReplicas)
+				changed = true
+			}
+		case current == nil || desiredReplicas != *current:
+			err = c.patchWorkloadWithReplicaCount(w.workload, clusterName, desiredReplicas)
+			changed = true
+		}
+	}
+
+	if err != nil {
+		return capacity, err
+	}
+
+	// A workload scaling down a few replicas at a time isn't ready until
+	// it gets all the way down.
+	if scaleDown != "" {
+		capacity.reason = ScalingDown
+		capacity.message = scaleDown
+		return capacity, nil
+	}
+
+	// Workload was successfully updated, but the update hasn't been
+	// observed by its controller yet, so our change is still in flight,
+	// and we can't trust the status yet.
+	if changed || w.inProgress() {
+		return capacity, nil
 	}
 
-	replicaFailureCond := getDeploymentCondition(deployment.Status, appsv1.DeploymentReplicaFailure)
-	progressingCond := getDeploymentCondition(deployment.Status, appsv1.DeploymentProgressing)
+	// If the number of available replicas matches what we want, the
+	// workload is ready and there's nothing left to check.
+	if availableReplicas == desiredReplicas {
+		capacity.ready = true
+		return capacity, nil
+	}
+
+	// Not all pods are availble, so we know for sure this workload isn't
+	// ready. From here on out we just try to figure out why to give users
+	// a good place to start looking.
+	capacity.sadPods = c.getSadPods(w.pods, w.podEvents)
 
-	var msg, reason string
+	// StatefulSets don't have conditions telling why they're stuck, so
+	// only their pods can tell.
+	var replicaFailureCond, progressingCond *appsv1.DeploymentCondition
+	if deployment := w.deployment; deployment != nil {
+		replicaFailureCond = getDeploymentCondition(deployment.Status, appsv1.DeploymentReplicaFailure)
+		progressingCond = getDeploymentCondition(deployment.Status, appsv1.DeploymentProgressing)
+	}
 
 	if replicaFailureCond != nil && replicaFailureCond.Status == corev1.ConditionTrue {
 		// It is common for a Deployment to get stuck because of exceeded
 		// quotas. Looking at the ReplicaFailure condition exposes that
 		// condition, and potentially others too.
-		reason = DeploymentStuck
-		msg = replicaFailureCond.Message
+		capacity.reason = DeploymentStuck
+		capacity.message = replicaFailureCond.Message
 	} else if progressingCond != nil && progressingCond.Status == corev1.ConditionFalse {
 		// If the Deployment has a timeout defined, and exceeds it,
 		// Progressing becomes False. Note that True doesn't *actually*
 		// mean the rollout is still progressing, for our definition of
 		// progressing.
-		reason = DeploymentStuck
-		msg = progressingCond.Message
-	} else if l := len(sadPods); l > 0 {
+		capacity.reason = DeploymentStuck
+		capacity.message = progressingCond.Message
+	} else if l := len(capacity.sadPods); l > 0 {
 		// We ran out of conditions to look at, but we have pods that
 		// aren't Ready, so that's one reason to be concerned.
-		reason = PodsNotReady
-		msg = fmt.Sprintf(
+		capacity.reason = PodsNotReady
+		capacity.message = fmt.Sprintf(
 			"%d out of %d pods are not Ready. this might require intervention, check SadPods in this object for more information",
 			l, desiredReplicas,
 		)
-	} else {
-		// None of the existing pods are non-Ready, and we presumably
-		// didn't hit quota yet, so we're most likely still in
-		// progress.
-		reason = InProgress
 	}
 
-	readyCond = capacityutil.NewClusterCapacityCondition(
-		shipper.ClusterConditionTypeReady,
-		corev1.ConditionFalse,
-		reason,
-		msg,
-	)
+	// Otherwise, none of the existing pods are non-Ready, and we
+	// presumably didn't hit quota yet, so we're most likely still in
+	// progress.
 
-	return nil
+	return capacity, nil
 }
 
 func (c *Controller) capacityTargetSyncHandler(key string) error {
@@ -359,6 +475,10 @@ func (c *Controller) capacityTargetSyncHandler(key string) error {
 		}
 	}
 
+	if scalingDown(ct) {
+		c.workqueue.AddAfter(key, ScaleDownInterval)
+	}
+
 	return err
 }
 
@@ -418,6 +538,20 @@ func (c *Controller) processCapacityTarget(ct *shipper.CapacityTarget) (*shipper
 	return ct, clusterErrors.Flatten()
 }
 
+// scalingDown tells whether any cluster of a capacity target is waiting to
+// take the next step scaling down its workloads.
+func scalingDown(ct *shipper.CapacityTarget) bool {
+	for _, status := range ct.Status.Clusters {
+		for _, cond := range status.Conditions {
+			if cond.Type == shipper.ClusterConditionTypeReady && cond.Reason == ScalingDown {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 func (c *Controller) enqueueCapacityTarget(obj interface{}) {
 	key, err := cache.MetaNamespaceKeyFunc(obj)
 	if err != nil {
@@ -440,17 +574,58 @@ func (c *Controller) registerDeploymentEventHandlers(informerFactory kubeinforme
 		},
 	}
 	informerFactory.Apps().V1().Deployments().Informer().AddEventHandler(handler)
+
+	statefulSetHandler := cache.FilteringResourceEventHandler{
+		FilterFunc: filters.BelongsToRelease,
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc:    c.enqueueCapacityTargetFromStatefulSet,
+			DeleteFunc: c.enqueueCapacityTargetFromStatefulSet,
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				c.enqueueCapacityTargetFromStatefulSet(newObj)
+			},
+		},
+	}
+	informerFactory.Apps().V1().StatefulSets().Informer().AddEventHandler(statefulSetHandler)
+
+	hpaHandler := cache.FilteringResourceEventHandler{
+		FilterFunc: filters.BelongsToRelease,
+		Handler: cache.ResourceEventHandlerFuncs{
+			AddFunc: c.enqueueCapacityTargetFromHorizontalPodAutoscaler,
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				c.enqueueCapacityTargetFromHorizontalPodAutoscaler(newObj)
+			},
+		},
+	}
+	informerFactory.Autoscaling().V1().HorizontalPodAutoscalers().Informer().AddEventHandler(hpaHandler)
+
+	podEventHandler := cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			c.enqueueCapacityTargetFromPodEvent(informerFactory, obj)
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			c.enqueueCapacityTargetFromPodEvent(informerFactory, newObj)
+		},
+	}
+	podEventInformer(informerFactory).AddEventHandler(podEventHandler)
 }
 
 func (c *Controller) subscribeToDeployments(informerFactory kubeinformers.SharedInformerFactory) {
 	informerFactory.Apps().V1().Deployments().Informer()
+	informerFactory.Apps().V1().StatefulSets().Informer()
+	informerFactory.Autoscaling().V1().HorizontalPodAutoscalers().Informer()
 	informerFactory.Core().V1().Pods().Informer()
+	informerFactory.Policy().V1beta1().PodDisruptionBudgets().Informer()
+	podEventInformer(informerFactory)
 }
 
-func (c Controller) getClusterObjects(cluster, ns, appName, release string) (*appsv1.Deployment, []*corev1.Pod, error) {
-	informerFactory, err := c.clusterClientStore.GetInformerFactory(cluster)
+// getClusterObjects finds the workloads a capacity target's cluster spec
+// asks for, along with their pods, the warnings about those, HPAs and
+// PodDisruptionBudgets. Workloads that opted out of capacity management are
+// never scaled, so they aren't looked at either.
+func (c Controller) getClusterObjects(spec *shipper.ClusterCapacityTarget, ns, appName, release string) ([]clusterWorkload, error) {
+	informerFactory, err := c.clusterClientStore.GetInformerFactory(spec.Name)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	deploymentSelector := labels.Set{
@@ -461,31 +636,101 @@ func (c Controller) getClusterObjects(cluster, ns, appName, release string) (*ap
 	deployments, err := informerFactory.Apps().V1().Deployments().
 		Lister().Deployments(ns).List(deploymentSelector)
 	if err != nil {
-		return nil, nil, shippererrors.NewKubeclientListError(
+		return nil, shippererrors.NewKubeclientListError(
 			deploymentGVK, ns, deploymentSelector, err)
 	}
 
-	if l := len(deployments); l != 1 {
-		return nil, nil, shippererrors.NewUnexpectedObjectCountFromSelectorError(
-			deploymentSelector, deploymentGVK, 1, l)
+	statefulSetGVK := appsv1.SchemeGroupVersion.WithKind("StatefulSet")
+	statefulSets, err := informerFactory.Apps().V1().StatefulSets().
+		Lister().StatefulSets(ns).List(deploymentSelector)
+	if err != nil {
+		return nil, shippererrors.NewKubeclientListError(
+			statefulSetGVK, ns, deploymentSelector, err)
+	}
+
+	found := []workload{}
+	for _, deployment := range deployments {
+		if !capacityutil.OptedOut(deployment) {
+			found = append(found, workload{deployment: deployment})
+		}
+	}
+	for _, statefulSet := range statefulSets {
+		if !capacityutil.OptedOut(statefulSet) {
+			found = append(found, workload{statefulSet: statefulSet})
+		}
 	}
 
-	deployment := deployments[0]
+	var workloads []clusterWorkload
+	if len(spec.Workloads) == 0 {
+		// Charts with a single workload don't list it, and all of the
+		// cluster's replicas are its own.
+		if len(found) != 1 {
+			return nil, shippererrors.NewUnexpectedObjectCountFromSelectorError(
+				deploymentSelector, deploymentGVK, 1, len(found))
+		}
 
-	podSelector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
-	if err != nil {
-		return nil, nil, shippererrors.NewUnrecoverableError(fmt.Errorf("failed to transform label selector %v into a selector: %s", deployment.Spec.Selector, err))
+		workloads = []clusterWorkload{
+			{workload: found[0], totalReplicaCount: spec.TotalReplicaCount},
+		}
+	} else {
+		workloads = make([]clusterWorkload, 0, len(spec.Workloads))
+		for _, workloadSpec := range spec.Workloads {
+			w, ok := findWorkload(found, workloadSpec.Kind, workloadSpec.Name)
+			if !ok {
+				gvk := appsv1.SchemeGroupVersion.WithKind(workloadSpec.Kind)
+				gr := appsv1.Resource(strings.ToLower(workloadSpec.Kind) + "s")
+				return nil, shippererrors.NewKubeclientGetError(ns, workloadSpec.Name,
+					kerrors.NewNotFound(gr, workloadSpec.Name)).WithKind(gvk)
+			}
+
+			workloads = append(workloads, clusterWorkload{
+				workload:          w,
+				totalReplicaCount: workloadSpec.TotalReplicaCount,
+			})
+		}
 	}
 
-	pods, err := informerFactory.Core().V1().Pods().Lister().
-		Pods(deployment.Namespace).List(podSelector)
-	if err != nil {
-		return nil, nil, shippererrors.NewKubeclientListError(
-			corev1.SchemeGroupVersion.WithKind("Pod"),
-			deployment.Namespace, podSelector, err)
+	for i := range workloads {
+		w := &workloads[i]
+
+		w.hpa, err = c.getHorizontalPodAutoscaler(spec.Name, w.workload)
+		if err != nil {
+			return nil, err
+		}
+
+		w.pdbs, err = c.getPodDisruptionBudgets(spec.Name, w.workload)
+		if err != nil {
+			return nil, err
+		}
+
+		selector := w.selector()
+		podSelector, err := metav1.LabelSelectorAsSelector(selector)
+		if err != nil {
+			return nil, shippererrors.NewUnrecoverableError(fmt.Errorf("failed to transform label selector %v into a selector: %s", selector, err))
+		}
+
+		w.pods, err = informerFactory.Core().V1().Pods().Lister().
+			Pods(ns).List(podSelector)
+		if err != nil {
+			return nil, shippererrors.NewKubeclientListError(
+				corev1.SchemeGroupVersion.WithKind("Pod"),
+				ns, podSelector, err)
+		}
+
+		w.podEvents = make(map[string][]shipper.PodEvent, len(w.pods))
+		for _, pod := range w.pods {
+			events, err := getPodEvents(podEventInformer(informerFactory), pod)
+			if err != nil {
+				return nil, shippererrors.NewUnrecoverableError(fmt.Errorf("failed to get events for pod %q: %s", pod.Name, err))
+			}
+
+			if len(events) > 0 {
+				w.podEvents[pod.Name] = events
+			}
+		}
 	}
 
-	return deployment, pods, nil
+	return workloads, nil
 }
 
 func (c *Controller) patchDeploymentWithReplicaCount(deployment *appsv1.Deployment, clusterName string, replicaCount int32) (*appsv1.Deployment, error) {
@@ -512,15 +757,15 @@ func (c *Controller) reportConditionChange(ct *shipper.CapacityTarget, reason st
 	}
 }
 
-func buildReport(ownerName string, podsList []*corev1.Pod) *shipper.ClusterCapacityReport {
+func buildReport(ownerName string, podsList []*corev1.Pod, podEvents map[string][]shipper.PodEvent) *shipper.ClusterCapacityReport {
 	sort.Slice(podsList, func(i, j int) bool {
-		return podsList[i].Name < podsList[j].Name
+		return podNameLess(podsList[i].Name, podsList[j].Name)
 	})
 
 	reportBuilder := builder.NewReport(ownerName)
 
 	for _, pod := range podsList {
-		reportBuilder.AddPod(pod)
+		reportBuilder.AddPod(pod, podEvents[pod.Name]...)
 	}
 
 	return reportBuilder.Build()
@@ -7,7 +7,9 @@ import (
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 
 	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
@@ -252,11 +254,796 @@ func TestCapacityShiftingSadPods(t *testing.T) {
 	)
 }
 
+// TestCapacitySadPodEvents verifies that the most recent warnings
+// Kubernetes recorded about sad pods are reported along with them, once
+// each, and leaving out warnings about earlier pods with the same name.
+func TestCapacitySadPodEvents(t *testing.T) {
+	totalReplicaCount := int32(10)
+	availableReplicaCount := int32(5)
+	ct := buildCapacityTarget(shippertesting.TestApp, ctName, []shipper.ClusterCapacityTarget{
+		{
+			Name:              clusterA,
+			Percent:           100,
+			TotalReplicaCount: totalReplicaCount,
+		},
+	})
+
+	deployment := buildDeployment(shippertesting.TestApp, ctName, totalReplicaCount, availableReplicaCount)
+	sadPod := buildSadPodForDeployment(deployment)
+	sadPod.UID = "sad-pod"
+
+	unhealthy := "Readiness probe failed: connection refused"
+	failedMount := "MountVolume.SetUp failed for volume \"config\""
+	now := time.Date(2020, 3, 2, 10, 0, 0, 0, time.UTC)
+
+	earlierPod := sadPod.DeepCopy()
+	earlierPod.UID = "earlier-pod"
+
+	objects := []runtime.Object{
+		deployment,
+		sadPod,
+		buildPodEvent(sadPod, "a", corev1.EventTypeWarning, "FailedMount", failedMount, now.Add(-2*time.Minute)),
+		buildPodEvent(sadPod, "b", corev1.EventTypeWarning, "Unhealthy", unhealthy, now.Add(-time.Minute)),
+		buildPodEvent(sadPod, "c", corev1.EventTypeWarning, "Unhealthy", unhealthy, now.Add(-3*time.Minute)),
+		buildPodEvent(sadPod, "d", corev1.EventTypeNormal, "Pulled", "Container image already present on machine", now),
+		buildPodEvent(earlierPod, "e", corev1.EventTypeWarning, "FailedScheduling", "0/3 nodes are available", now),
+	}
+
+	reports := []shipper.ClusterCapacityReport{
+		{
+			Owner: shipper.ClusterCapacityReportOwner{Name: ctName},
+			Breakdown: []shipper.ClusterCapacityReportBreakdown{
+				{
+					Type:       "Ready",
+					Status:     string(corev1.ConditionFalse),
+					Reason:     "ExpectedFail",
+					Count:      1,
+					Containers: []shipper.ClusterCapacityReportContainerBreakdown{},
+					Events: []shipper.ClusterCapacityReportEventBreakdown{
+						{
+							Count:  1,
+							Reason: "FailedMount",
+							Example: shipper.ClusterCapacityReportContainerBreakdownExample{
+								Pod:     sadPod.Name,
+								Message: &failedMount,
+							},
+						},
+						{
+							Count:  1,
+							Reason: "Unhealthy",
+							Example: shipper.ClusterCapacityReportContainerBreakdownExample{
+								Pod:     sadPod.Name,
+								Message: &unhealthy,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	status := shipper.CapacityTargetStatus{
+		Clusters: []shipper.ClusterCapacityStatus{
+			{
+				Name:              clusterA,
+				AchievedPercent:   50,
+				AvailableReplicas: availableReplicaCount,
+				Conditions: []shipper.ClusterCapacityCondition{
+					ClusterCapacityOperational,
+					{
+						Type:    shipper.ClusterConditionTypeReady,
+						Status:  corev1.ConditionFalse,
+						Reason:  PodsNotReady,
+						Message: "1 out of 10 pods are not Ready. this might require intervention, check SadPods in this object for more information",
+					},
+				},
+				SadPods: []shipper.PodStatus{
+					{
+						Name:      sadPod.Name,
+						Condition: sadPod.Status.Conditions[0],
+						Events: []shipper.PodEvent{
+							{Reason: "Unhealthy", Message: unhealthy},
+							{Reason: "FailedMount", Message: failedMount},
+						},
+					},
+				},
+				Reports: reports,
+			},
+		},
+		Conditions: []shipper.TargetCondition{
+			TargetConditionOperational,
+			{
+				Type:    shipper.TargetConditionTypeReady,
+				Status:  corev1.ConditionFalse,
+				Reason:  ClustersNotReady,
+				Message: fmt.Sprintf("%v", []string{clusterA}),
+			},
+		},
+	}
+
+	runCapacityControllerTest(t,
+		map[string][]runtime.Object{
+			clusterA: objects,
+		},
+		[]capacityTargetTestExpectation{
+			{
+				capacityTarget: ct,
+				status:         status,
+				replicasByCluster: map[string]int32{
+					clusterA: totalReplicaCount,
+				},
+			},
+		},
+	)
+}
+
+// TestCapacityWithHPA verifies that deployments scaled by an HPA get their
+// capacity through the HPA's bounds, which are scaled from the ones the HPA
+// was installed with, and that the deployment is only brought into those
+// bounds.
+func TestCapacityWithHPA(t *testing.T) {
+	ct := buildCapacityTarget(shippertesting.TestApp, ctName, []shipper.ClusterCapacityTarget{
+		{
+			Name:              clusterA,
+			Percent:           50,
+			TotalReplicaCount: 4,
+		},
+	})
+
+	deployment := buildDeployment(shippertesting.TestApp, ctName, 8, 8)
+	hpa := buildHorizontalPodAutoscaler(shippertesting.TestApp, ctName, 2, 10)
+
+	status := shipper.CapacityTargetStatus{
+		Clusters: []shipper.ClusterCapacityStatus{
+			{
+				Name:              clusterA,
+				AchievedPercent:   80,
+				AvailableReplicas: 8,
+				Conditions: []shipper.ClusterCapacityCondition{
+					ClusterCapacityOperational,
+					{
+						Type:   shipper.ClusterConditionTypeReady,
+						Status: corev1.ConditionFalse,
+						Reason: InProgress,
+					},
+				},
+				Reports: []shipper.ClusterCapacityReport{
+					{
+						Owner:     shipper.ClusterCapacityReportOwner{Name: ctName},
+						Breakdown: []shipper.ClusterCapacityReportBreakdown{},
+					},
+				},
+			},
+		},
+		Conditions: []shipper.TargetCondition{
+			TargetConditionOperational,
+			{
+				Type:    shipper.TargetConditionTypeReady,
+				Status:  corev1.ConditionFalse,
+				Reason:  ClustersNotReady,
+				Message: fmt.Sprintf("%v", []string{clusterA}),
+			},
+		},
+	}
+
+	f := runCapacityControllerTest(t,
+		map[string][]runtime.Object{
+			clusterA: []runtime.Object{deployment, hpa},
+		},
+		[]capacityTargetTestExpectation{
+			{
+				capacityTarget: ct,
+				status:         status,
+				replicasByCluster: map[string]int32{
+					clusterA: 5,
+				},
+			},
+		},
+	)
+
+	assertHPABounds(t, f.Clusters[clusterA], ctName, 1, 5)
+}
+
+// TestCapacityWithHPAReplicas verifies that clusters asking for an absolute
+// number of replicas get HPA bounds scaled by the smallest percentage that
+// gets to that many, rather than by the percentage they're also at.
+func TestCapacityWithHPAReplicas(t *testing.T) {
+	replicas := int32(1)
+	ct := buildCapacityTarget(shippertesting.TestApp, ctName, []shipper.ClusterCapacityTarget{
+		{
+			Name:              clusterA,
+			Percent:           100,
+			Replicas:          &replicas,
+			TotalReplicaCount: 4,
+		},
+	})
+
+	deployment := buildDeployment(shippertesting.TestApp, ctName, 1, 1)
+	hpa := buildHorizontalPodAutoscaler(shippertesting.TestApp, ctName, 2, 10)
+
+	status := buildSuccessStatus(ctName, ct.Spec.Clusters)
+	status.Clusters[0].AchievedPercent = 1
+	status.Clusters[0].AvailableReplicas = 1
+
+	f := runCapacityControllerTest(t,
+		map[string][]runtime.Object{
+			clusterA: []runtime.Object{deployment, hpa},
+		},
+		[]capacityTargetTestExpectation{
+			{
+				capacityTarget: ct,
+				status:         status,
+				replicasByCluster: map[string]int32{
+					clusterA: 1,
+				},
+			},
+		},
+	)
+
+	assertHPABounds(t, f.Clusters[clusterA], ctName, 1, 1)
+}
+
+// TestCapacityWithHPAReady verifies that deployments scaled by an HPA are at
+// capacity once they have as many replicas as the HPA allows, even if that's
+// not a percentage of the release's total replica count.
+func TestCapacityWithHPAReady(t *testing.T) {
+	ct := buildCapacityTarget(shippertesting.TestApp, ctName, []shipper.ClusterCapacityTarget{
+		{
+			Name:              clusterA,
+			Percent:           50,
+			TotalReplicaCount: 4,
+		},
+	})
+
+	deployment := buildDeployment(shippertesting.TestApp, ctName, 3, 3)
+	hpa := buildHorizontalPodAutoscaler(shippertesting.TestApp, ctName, 1, 5)
+	hpa.Annotations = map[string]string{
+		shipper.HPAMinReplicasAnnotation: "2",
+		shipper.HPAMaxReplicasAnnotation: "10",
+	}
+
+	status := buildSuccessStatus(ctName, ct.Spec.Clusters)
+	status.Clusters[0].AvailableReplicas = 3
+
+	f := runCapacityControllerTest(t,
+		map[string][]runtime.Object{
+			clusterA: []runtime.Object{deployment, hpa},
+		},
+		[]capacityTargetTestExpectation{
+			{
+				capacityTarget: ct,
+				status:         status,
+				replicasByCluster: map[string]int32{
+					clusterA: 3,
+				},
+			},
+		},
+	)
+
+	assertHPABounds(t, f.Clusters[clusterA], ctName, 1, 5)
+}
+
+// TestCapacityWithHPAScaledToZero verifies that deployments scaled by an HPA
+// are scaled down to zero directly, as HPAs can't go that low.
+func TestCapacityWithHPAScaledToZero(t *testing.T) {
+	ct := buildCapacityTarget(shippertesting.TestApp, ctName, []shipper.ClusterCapacityTarget{
+		{
+			Name:              clusterA,
+			Percent:           0,
+			TotalReplicaCount: 4,
+		},
+	})
+
+	deployment := buildDeployment(shippertesting.TestApp, ctName, 0, 0)
+	hpa := buildHorizontalPodAutoscaler(shippertesting.TestApp, ctName, 2, 10)
+
+	f := runCapacityControllerTest(t,
+		map[string][]runtime.Object{
+			clusterA: []runtime.Object{deployment, hpa},
+		},
+		[]capacityTargetTestExpectation{
+			{
+				capacityTarget: ct,
+				status:         buildSuccessStatus(ctName, ct.Spec.Clusters),
+				replicasByCluster: map[string]int32{
+					clusterA: 0,
+				},
+			},
+		},
+	)
+
+	assertHPABounds(t, f.Clusters[clusterA], ctName, 2, 10)
+}
+
+// TestStatefulSet verifies that StatefulSets are scaled just like
+// Deployments, with their ready pods counting as available.
+func TestStatefulSet(t *testing.T) {
+	ct := buildCapacityTarget(shippertesting.TestApp, ctName, []shipper.ClusterCapacityTarget{
+		{
+			Name:              clusterA,
+			Percent:           50,
+			TotalReplicaCount: 10,
+		},
+	})
+
+	runCapacityControllerTest(t,
+		map[string][]runtime.Object{
+			clusterA: []runtime.Object{buildStatefulSet(shippertesting.TestApp, ctName, 5, 5)},
+		},
+		[]capacityTargetTestExpectation{
+			{
+				capacityTarget: ct,
+				status:         buildSuccessStatus(ctName, ct.Spec.Clusters),
+				replicasByCluster: map[string]int32{
+					clusterA: 5,
+				},
+			},
+		},
+	)
+}
+
+// TestStatefulSetPartitionedUpdate verifies that a StatefulSet isn't at
+// capacity while it's still updating the pods from its partition up, and
+// that its sad pods are reported in the order of their ordinals.
+func TestStatefulSetPartitionedUpdate(t *testing.T) {
+	ct := buildCapacityTarget(shippertesting.TestApp, ctName, []shipper.ClusterCapacityTarget{
+		{
+			Name:              clusterA,
+			Percent:           100,
+			TotalReplicaCount: 12,
+		},
+	})
+
+	partition := int32(8)
+	statefulSet := buildStatefulSet(shippertesting.TestApp, ctName, 12, 10)
+	statefulSet.Spec.UpdateStrategy = appsv1.StatefulSetUpdateStrategy{
+		Type: appsv1.RollingUpdateStatefulSetStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{
+			Partition: &partition,
+		},
+	}
+	statefulSet.Status.CurrentRevision = "rev-1"
+	statefulSet.Status.UpdateRevision = "rev-2"
+	statefulSet.Status.UpdatedReplicas = 3
+
+	sadPods := []*corev1.Pod{
+		buildSadPodForStatefulSet(statefulSet, 10),
+		buildSadPodForStatefulSet(statefulSet, 9),
+	}
+
+	status := shipper.CapacityTargetStatus{
+		Clusters: []shipper.ClusterCapacityStatus{
+			{
+				Name:              clusterA,
+				AchievedPercent:   84,
+				AvailableReplicas: 10,
+				Conditions: []shipper.ClusterCapacityCondition{
+					ClusterCapacityOperational,
+					{
+						Type:   shipper.ClusterConditionTypeReady,
+						Status: corev1.ConditionFalse,
+						Reason: InProgress,
+					},
+				},
+				Reports: []shipper.ClusterCapacityReport{
+					{
+						Owner: shipper.ClusterCapacityReportOwner{Name: ctName},
+						Breakdown: []shipper.ClusterCapacityReportBreakdown{
+							{
+								Type:       "Ready",
+								Status:     string(corev1.ConditionFalse),
+								Reason:     "ExpectedFail",
+								Count:      2,
+								Containers: []shipper.ClusterCapacityReportContainerBreakdown{},
+							},
+						},
+					},
+				},
+			},
+		},
+		Conditions: []shipper.TargetCondition{
+			TargetConditionOperational,
+			{
+				Type:    shipper.TargetConditionTypeReady,
+				Status:  corev1.ConditionFalse,
+				Reason:  ClustersNotReady,
+				Message: fmt.Sprintf("%v", []string{clusterA}),
+			},
+		},
+	}
+
+	runCapacityControllerTest(t,
+		map[string][]runtime.Object{
+			clusterA: []runtime.Object{statefulSet, sadPods[0], sadPods[1]},
+		},
+		[]capacityTargetTestExpectation{
+			{
+				capacityTarget: ct,
+				status:         status,
+				replicasByCluster: map[string]int32{
+					clusterA: 12,
+				},
+			},
+		},
+	)
+
+	statefulSet.Status.UpdatedReplicas = 4
+	status.Clusters[0].Conditions[1] = shipper.ClusterCapacityCondition{
+		Type:    shipper.ClusterConditionTypeReady,
+		Status:  corev1.ConditionFalse,
+		Reason:  PodsNotReady,
+		Message: "2 out of 12 pods are not Ready. this might require intervention, check SadPods in this object for more information",
+	}
+	status.Clusters[0].SadPods = []shipper.PodStatus{
+		{Name: sadPods[1].Name, Condition: sadPods[1].Status.Conditions[0]},
+		{Name: sadPods[0].Name, Condition: sadPods[0].Status.Conditions[0]},
+	}
+
+	runCapacityControllerTest(t,
+		map[string][]runtime.Object{
+			clusterA: []runtime.Object{statefulSet, sadPods[0], sadPods[1]},
+		},
+		[]capacityTargetTestExpectation{
+			{
+				capacityTarget: ct.DeepCopy(),
+				status:         status,
+				replicasByCluster: map[string]int32{
+					clusterA: 12,
+				},
+			},
+		},
+	)
+}
+
+// TestCapacityReplicas verifies that clusters asking for an absolute number
+// of replicas get exactly that many, rather than the percentage they're
+// also at.
+func TestCapacityReplicas(t *testing.T) {
+	replicas := int32(1)
+	ct := buildCapacityTarget(shippertesting.TestApp, ctName, []shipper.ClusterCapacityTarget{
+		{
+			Name:              clusterA,
+			Percent:           1,
+			Replicas:          &replicas,
+			TotalReplicaCount: 400,
+		},
+	})
+
+	status := buildSuccessStatus(ctName, ct.Spec.Clusters)
+	status.Clusters[0].AvailableReplicas = 1
+
+	runCapacityControllerTest(t,
+		map[string][]runtime.Object{
+			clusterA: []runtime.Object{buildDeployment(shippertesting.TestApp, ctName, 1, 1)},
+		},
+		[]capacityTargetTestExpectation{
+			{
+				capacityTarget: ct,
+				status:         status,
+				replicasByCluster: map[string]int32{
+					clusterA: 1,
+				},
+			},
+		},
+	)
+}
+
+// TestCapacityScaleDownPodDisruptionBudget verifies that workloads covered
+// by a PodDisruptionBudget are only scaled down by as many replicas at a
+// time as the budget allows to be disrupted, or one at a time when it
+// allows none, and that the next step waits for the pods the last one took
+// away to be gone.
+func TestCapacityScaleDownPodDisruptionBudget(t *testing.T) {
+	tests := []struct {
+		name               string
+		disruptionsAllowed int32
+		expectedReplicas   int32
+		message            string
+	}{
+		{
+			name:               "some disruptions allowed",
+			disruptionsAllowed: 2,
+			expectedReplicas:   8,
+			message:            fmt.Sprintf("waiting for Deployment %q to scale down to 8 replicas before scaling it down further to 5", ctName),
+		},
+		{
+			name:               "no disruptions allowed",
+			disruptionsAllowed: 0,
+			expectedReplicas:   9,
+			message:            fmt.Sprintf("waiting for Deployment %q to scale down to 9 replicas before scaling it down further to 5", ctName),
+		},
+		{
+			name:               "enough disruptions allowed",
+			disruptionsAllowed: 5,
+			expectedReplicas:   5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			totalReplicaCount := int32(10)
+			ct := buildCapacityTarget(shippertesting.TestApp, ctName, []shipper.ClusterCapacityTarget{
+				{
+					Name:              clusterA,
+					Percent:           50,
+					TotalReplicaCount: totalReplicaCount,
+				},
+			})
+
+			// Until the deployment controller catches up with the
+			// new replica count, the cluster is in progress.
+			status := buildSuccessStatus(ctName, ct.Spec.Clusters)
+			status.Clusters[0].AchievedPercent = 100
+			status.Clusters[0].AvailableReplicas = totalReplicaCount
+			status.Clusters[0].Conditions[1] = shipper.ClusterCapacityCondition{
+				Type:   shipper.ClusterConditionTypeReady,
+				Status: corev1.ConditionFalse,
+				Reason: InProgress,
+			}
+			if tt.message != "" {
+				status.Clusters[0].Conditions[1].Reason = ScalingDown
+				status.Clusters[0].Conditions[1].Message = tt.message
+			}
+			status.Conditions[1] = shipper.TargetCondition{
+				Type:    shipper.TargetConditionTypeReady,
+				Status:  corev1.ConditionFalse,
+				Reason:  ClustersNotReady,
+				Message: fmt.Sprintf("%v", []string{clusterA}),
+			}
+
+			deployment := buildDeployment(shippertesting.TestApp, ctName, totalReplicaCount, totalReplicaCount)
+			objects := []runtime.Object{
+				deployment,
+				buildPodDisruptionBudget(shippertesting.TestApp, tt.disruptionsAllowed),
+			}
+			for i := int32(0); i < totalReplicaCount; i++ {
+				objects = append(objects, &corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: deployment.Namespace,
+						Name:      fmt.Sprintf("%s-%d", deployment.Name, i),
+						Labels:    deployment.Spec.Template.Labels,
+					},
+					Status: corev1.PodStatus{Phase: corev1.PodRunning},
+				})
+			}
+
+			runCapacityControllerTest(t,
+				map[string][]runtime.Object{
+					clusterA: objects,
+				},
+				[]capacityTargetTestExpectation{
+					{
+						capacityTarget: ct,
+						status:         status,
+						replicasByCluster: map[string]int32{
+							clusterA: tt.expectedReplicas,
+						},
+					},
+				},
+			)
+		})
+	}
+}
+
+// TestCapacityScaleDownPodDisruptionBudgetToZero verifies that a budget
+// keeping a minimum of the release's own pods around, which allows no
+// disruptions once the release is down to its last pod, doesn't stop it
+// from being scaled all the way down.
+func TestCapacityScaleDownPodDisruptionBudgetToZero(t *testing.T) {
+	ct := buildCapacityTarget(shippertesting.TestApp, ctName, []shipper.ClusterCapacityTarget{
+		{Name: clusterA, Percent: 0, TotalReplicaCount: 10},
+	})
+
+	deployment := buildDeployment(shippertesting.TestApp, ctName, 1, 1)
+	f := shippertesting.NewControllerTestFixture()
+	f.AddNamedCluster(clusterA).AddMany([]runtime.Object{
+		deployment,
+		buildPodDisruptionBudget(shippertesting.TestApp, 0),
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: deployment.Namespace,
+				Name:      fmt.Sprintf("%s-0", deployment.Name),
+				Labels:    deployment.Spec.Template.Labels,
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		},
+	})
+	f.ShipperClient.Tracker().Add(ct)
+
+	runController(f)
+
+	ctGVR := shipper.SchemeGroupVersion.WithResource("capacitytargets")
+	object, err := f.ShipperClient.Tracker().Get(ctGVR, ct.Namespace, ct.Name)
+	if err != nil {
+		t.Fatalf("could not Get CapacityTarget %q: %s", ct.Name, err)
+	}
+
+	assertWorkloadReplicas(t, object.(*shipper.CapacityTarget), f.Clusters[clusterA], 0)
+}
+
+// TestCapacityScaleDownWaitsForContender verifies that an incumbent covered
+// by a PodDisruptionBudget isn't scaled down while its contender doesn't
+// have all the capacity it asked for yet.
+func TestCapacityScaleDownWaitsForContender(t *testing.T) {
+	contenderName := fmt.Sprintf("%s-contender", ctName)
+	totalReplicaCount := int32(10)
+
+	incumbentCT := buildCapacityTarget(shippertesting.TestApp, ctName, []shipper.ClusterCapacityTarget{
+		{Name: clusterA, Percent: 50, TotalReplicaCount: totalReplicaCount},
+	})
+	contenderCT := buildCapacityTarget(shippertesting.TestApp, contenderName, []shipper.ClusterCapacityTarget{
+		{Name: clusterA, Percent: 50, TotalReplicaCount: totalReplicaCount},
+	})
+
+	f := shippertesting.NewControllerTestFixture()
+	f.AddNamedCluster(clusterA).AddMany([]runtime.Object{
+		buildDeployment(shippertesting.TestApp, ctName, totalReplicaCount, totalReplicaCount),
+		buildDeployment(shippertesting.TestApp, contenderName, 5, 0),
+		buildPodDisruptionBudget(shippertesting.TestApp, 2),
+	})
+
+	for _, obj := range []runtime.Object{
+		buildRelease(shippertesting.TestApp, ctName, 1),
+		buildRelease(shippertesting.TestApp, contenderName, 2),
+		incumbentCT,
+		contenderCT,
+	} {
+		f.ShipperClient.Tracker().Add(obj)
+	}
+
+	runController(f)
+
+	ctGVR := shipper.SchemeGroupVersion.WithResource("capacitytargets")
+	object, err := f.ShipperClient.Tracker().Get(ctGVR, incumbentCT.Namespace, incumbentCT.Name)
+	if err != nil {
+		t.Fatalf("could not Get CapacityTarget %q: %s", incumbentCT.Name, err)
+	}
+
+	ct := object.(*shipper.CapacityTarget)
+	assertWorkloadReplicas(t, ct, f.Clusters[clusterA], totalReplicaCount)
+
+	expected := shipper.ClusterCapacityCondition{
+		Type:    shipper.ClusterConditionTypeReady,
+		Status:  corev1.ConditionFalse,
+		Reason:  ScalingDown,
+		Message: fmt.Sprintf("waiting for contender %q to be ready before scaling down from 10 to 5 replicas", contenderName),
+	}
+	if len(ct.Status.Clusters) != 1 || len(ct.Status.Clusters[0].Conditions) != 2 {
+		t.Fatalf("expected a single cluster with two conditions, got %+v", ct.Status.Clusters)
+	}
+	if eq, diff := shippertesting.DeepEqualDiff(expected, ct.Status.Clusters[0].Conditions[1]); !eq {
+		t.Errorf("incumbent has a Ready condition different from expected:\n%s", diff)
+	}
+}
+
+// TestMultipleWorkloads verifies that every workload listed in a cluster's
+// spec is scaled to the same percentage of its own replicas, that the
+// cluster is only as far along as the workload furthest behind, and that
+// workloads that opted out of capacity management are left alone.
+func TestMultipleWorkloads(t *testing.T) {
+	workerName := fmt.Sprintf("%s-worker", ctName)
+	cronName := fmt.Sprintf("%s-cron", ctName)
+
+	ct := buildCapacityTarget(shippertesting.TestApp, ctName, []shipper.ClusterCapacityTarget{
+		{
+			Name:              clusterA,
+			Percent:           50,
+			TotalReplicaCount: 14,
+			Workloads: []shipper.WorkloadCapacityTarget{
+				{Kind: "Deployment", Name: workerName, TotalReplicaCount: 4},
+				{Kind: "Deployment", Name: ctName, TotalReplicaCount: 10},
+			},
+		},
+	})
+
+	web := buildDeployment(shippertesting.TestApp, ctName, 0, 0)
+	web.Spec.Selector.MatchLabels["component"] = "web"
+
+	worker := buildDeployment(shippertesting.TestApp, ctName, 2, 1)
+	worker.Name = workerName
+	worker.Spec.Selector.MatchLabels = map[string]string{
+		shipper.AppLabel:     shippertesting.TestApp,
+		shipper.ReleaseLabel: ctName,
+		"component":          "worker",
+	}
+	sadPod := buildSadPodForDeployment(worker)
+
+	cron := buildDeployment(shippertesting.TestApp, ctName, 1, 1)
+	cron.Name = cronName
+	cron.Annotations = map[string]string{shipper.CapacityOptOutAnnotation: shipper.True}
+
+	status := shipper.CapacityTargetStatus{
+		Clusters: []shipper.ClusterCapacityStatus{
+			{
+				Name:              clusterA,
+				AchievedPercent:   0,
+				AvailableReplicas: 1,
+				Conditions: []shipper.ClusterCapacityCondition{
+					ClusterCapacityOperational,
+					{
+						Type:    shipper.ClusterConditionTypeReady,
+						Status:  corev1.ConditionFalse,
+						Reason:  PodsNotReady,
+						Message: fmt.Sprintf(`Deployment %q: 1 out of 2 pods are not Ready. this might require intervention, check SadPods in this object for more information`, workerName),
+					},
+				},
+				SadPods: []shipper.PodStatus{
+					{
+						Name:      sadPod.Name,
+						Condition: sadPod.Status.Conditions[0],
+					},
+				},
+				Reports: []shipper.ClusterCapacityReport{
+					{
+						Owner: shipper.ClusterCapacityReportOwner{Name: workerName},
+						Breakdown: []shipper.ClusterCapacityReportBreakdown{
+							{
+								Type:       "Ready",
+								Status:     string(corev1.ConditionFalse),
+								Reason:     "ExpectedFail",
+								Count:      1,
+								Containers: []shipper.ClusterCapacityReportContainerBreakdown{},
+							},
+						},
+					},
+					{
+						Owner:     shipper.ClusterCapacityReportOwner{Name: ctName},
+						Breakdown: []shipper.ClusterCapacityReportBreakdown{},
+					},
+				},
+				Workloads: []shipper.WorkloadCapacityStatus{
+					{Kind: "Deployment", Name: workerName, AvailableReplicas: 1, AchievedPercent: 25},
+					{Kind: "Deployment", Name: ctName, AvailableReplicas: 0, AchievedPercent: 0},
+				},
+			},
+		},
+		Conditions: []shipper.TargetCondition{
+			TargetConditionOperational,
+			{
+				Type:    shipper.TargetConditionTypeReady,
+				Status:  corev1.ConditionFalse,
+				Reason:  ClustersNotReady,
+				Message: fmt.Sprintf("%v", []string{clusterA}),
+			},
+		},
+	}
+
+	f := runCapacityControllerTest(t,
+		map[string][]runtime.Object{
+			clusterA: []runtime.Object{web, worker, sadPod, cron},
+		},
+		[]capacityTargetTestExpectation{
+			{
+				capacityTarget: ct,
+				status:         status,
+				replicasByCluster: map[string]int32{
+					clusterA: 5,
+				},
+			},
+		},
+	)
+
+	deploymentGVR := appsv1.SchemeGroupVersion.WithResource("deployments")
+	expected := map[string]int32{
+		workerName: 2,
+		cronName:   1,
+	}
+	for name, replicas := range expected {
+		object, err := f.Clusters[clusterA].Client.Tracker().Get(deploymentGVR, shippertesting.TestNamespace, name)
+		if err != nil {
+			t.Fatalf("could not Get Deployment %q: %s", name, err)
+		}
+
+		if actual := *object.(*appsv1.Deployment).Spec.Replicas; actual != replicas {
+			t.Errorf("expected Deployment %q to have %d replicas, got %d", name, replicas, actual)
+		}
+	}
+}
+
 func runCapacityControllerTest(
 	t *testing.T,
 	objectsByCluster map[string][]runtime.Object,
 	expectations []capacityTargetTestExpectation,
-) {
+) *shippertesting.ControllerTestFixture {
 	f := shippertesting.NewControllerTestFixture()
 
 	clusterNames := []string{}
@@ -297,31 +1084,66 @@ func runCapacityControllerTest(
 
 		for _, clusterName := range clusterNames {
 			expectedReplicas := expectation.replicasByCluster[clusterName]
-			assertDeploymentReplicas(t, ct, f.Clusters[clusterName], expectedReplicas)
+			assertWorkloadReplicas(t, ct, f.Clusters[clusterName], expectedReplicas)
 		}
 	}
+
+	return f
 }
 
-func assertDeploymentReplicas(
+func assertWorkloadReplicas(
 	t *testing.T,
 	ct *shipper.CapacityTarget,
 	cluster *shippertesting.FakeCluster,
 	expectedReplicas int32,
 ) {
-	deploymentGVR := appsv1.SchemeGroupVersion.WithResource("deployments")
 	ctKey := fmt.Sprintf("%s/%s", ct.Namespace, ct.Name)
+
+	kind := "Deployment"
+	var replicas *int32
+
+	deploymentGVR := appsv1.SchemeGroupVersion.WithResource("deployments")
 	object, err := cluster.Client.Tracker().Get(deploymentGVR, ct.Namespace, ct.Name)
+	if err == nil {
+		replicas = object.(*appsv1.Deployment).Spec.Replicas
+	} else {
+		kind = "StatefulSet"
+		statefulSetGVR := appsv1.SchemeGroupVersion.WithResource("statefulsets")
+		object, err = cluster.Client.Tracker().Get(statefulSetGVR, ct.Namespace, ct.Name)
+		if err != nil {
+			t.Errorf(`could not Get Deployment or StatefulSet %q: %s`, ctKey, err)
+			return
+		}
+
+		replicas = object.(*appsv1.StatefulSet).Spec.Replicas
+	}
+
+	if *replicas != expectedReplicas {
+		t.Errorf(
+			"CapacityTarget %q expected %s in cluster %q to have %d Replicas in its spec, got %d instead",
+			ctKey, kind, cluster.Name, expectedReplicas, *replicas,
+		)
+	}
+}
+
+func assertHPABounds(
+	t *testing.T,
+	cluster *shippertesting.FakeCluster,
+	name string,
+	expectedMin, expectedMax int32,
+) {
+	hpaGVR := autoscalingv1.SchemeGroupVersion.WithResource("horizontalpodautoscalers")
+	object, err := cluster.Client.Tracker().Get(hpaGVR, shippertesting.TestNamespace, name)
 	if err != nil {
-		t.Errorf(`could not Get Deployment %q: %s`, ctKey, err)
+		t.Errorf("could not Get HorizontalPodAutoscaler %q: %s", name, err)
 		return
 	}
 
-	deployment := object.(*appsv1.Deployment)
-
-	if *deployment.Spec.Replicas != expectedReplicas {
+	hpa := object.(*autoscalingv1.HorizontalPodAutoscaler)
+	if *hpa.Spec.MinReplicas != expectedMin || hpa.Spec.MaxReplicas != expectedMax {
 		t.Errorf(
-			"CapacityTarget %q expected Deployment in cluster %q to have %d Replicas in its spec, got %d instead",
-			ctKey, cluster.Name, expectedReplicas, *deployment.Spec.Replicas,
+			"expected HorizontalPodAutoscaler in cluster %q to allow %d to %d replicas, got %d to %d instead",
+			cluster.Name, expectedMin, expectedMax, *hpa.Spec.MinReplicas, hpa.Spec.MaxReplicas,
 		)
 	}
 }
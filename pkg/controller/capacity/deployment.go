@@ -37,6 +37,23 @@ func (c *Controller) enqueueCapacityTargetFromDeployment(obj interface{}) {
 	c.enqueueCapacityTarget(ct)
 }
 
+func (c *Controller) enqueueCapacityTargetFromStatefulSet(obj interface{}) {
+	statefulSet, ok := obj.(*appsv1.StatefulSet)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("not a StatefulSet: %#v", obj))
+		return
+	}
+
+	rel := statefulSet.GetLabels()[shipper.ReleaseLabel]
+	ct, err := c.getCapacityTargetForReleaseAndNamespace(rel, statefulSet.GetNamespace())
+	if err != nil {
+		runtime.HandleError(fmt.Errorf("cannot get capacity target for release '%s/%s': %#v", rel, statefulSet.GetNamespace(), err))
+		return
+	}
+
+	c.enqueueCapacityTarget(ct)
+}
+
 func (c Controller) getCapacityTargetForReleaseAndNamespace(release, namespace string) (*shipper.CapacityTarget, error) {
 	selector := labels.Set{shipper.ReleaseLabel: release}.AsSelector()
 	gvk := shipper.SchemeGroupVersion.WithKind("CapacityTarget")
@@ -55,7 +72,7 @@ func (c Controller) getCapacityTargetForReleaseAndNamespace(release, namespace s
 	return capacityTargets[0], nil
 }
 
-func (c Controller) getSadPods(pods []*corev1.Pod) []shipper.PodStatus {
+func (c Controller) getSadPods(pods []*corev1.Pod, podEvents map[string][]shipper.PodEvent) []shipper.PodStatus {
 	var sadPods []shipper.PodStatus
 	for _, pod := range pods {
 		if condition, ok := c.getFalsePodCondition(pod); ok {
@@ -64,6 +81,7 @@ func (c Controller) getSadPods(pods []*corev1.Pod) []shipper.PodStatus {
 				Condition:      *condition,
 				InitContainers: pod.Status.InitContainerStatuses,
 				Containers:     pod.Status.ContainerStatuses,
+				Events:         podEvents[pod.Name],
 			}
 
 			sadPods = append(sadPods, sadPod)
@@ -71,7 +89,7 @@ func (c Controller) getSadPods(pods []*corev1.Pod) []shipper.PodStatus {
 	}
 
 	sort.Slice(sadPods, func(i, j int) bool {
-		return sadPods[i].Name < sadPods[j].Name
+		return podNameLess(sadPods[i].Name, sadPods[j].Name)
 	})
 
 	return sadPods
@@ -0,0 +1,205 @@
+package capacity
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+	shippererrors "github.com/bookingcom/shipper/pkg/errors"
+	clusterstatusutil "github.com/bookingcom/shipper/pkg/util/clusterstatus"
+	releaseutil "github.com/bookingcom/shipper/pkg/util/release"
+)
+
+// getPodDisruptionBudgets returns the PodDisruptionBudgets covering the
+// pods of the given workload.
+func (c Controller) getPodDisruptionBudgets(cluster string, w workload) ([]*policyv1beta1.PodDisruptionBudget, error) {
+	informerFactory, err := c.clusterClientStore.GetInformerFactory(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	obj := w.object()
+	selector := labels.Everything()
+	pdbs, err := informerFactory.Policy().V1beta1().PodDisruptionBudgets().
+		Lister().PodDisruptionBudgets(obj.GetNamespace()).List(selector)
+	if err != nil {
+		return nil, shippererrors.NewKubeclientListError(
+			policyv1beta1.SchemeGroupVersion.WithKind("PodDisruptionBudget"),
+			obj.GetNamespace(), selector, err)
+	}
+
+	podLabels := labels.Set(w.podLabels())
+
+	var matching []*policyv1beta1.PodDisruptionBudget
+	for _, pdb := range pdbs {
+		pdbSelector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil || pdbSelector.Empty() {
+			// Kubernetes itself doesn't let budgets with invalid
+			// or empty selectors stop any evictions either.
+			continue
+		}
+
+		if pdbSelector.Matches(podLabels) {
+			matching = append(matching, pdb)
+		}
+	}
+
+	return matching, nil
+}
+
+// scaleDownStep works out how many replicas a workload scaling down to the
+// desired ones can be brought down to right now and, if that's not all the
+// way down, why not. The pods of workloads covered by a PodDisruptionBudget
+// are only taken away as many at a time as the budget allows to be
+// disrupted, but at least one, and only while the release's contender has
+// all the capacity it asked for, so that an incumbent doesn't lose more
+// pods than its budget promises while the contender is still warming up.
+func (c *Controller) scaleDownStep(
+	ct *shipper.CapacityTarget,
+	clusterName string,
+	w clusterWorkload,
+	current, desired int32,
+) (int32, string, error) {
+	if len(w.pdbs) == 0 {
+		return desired, "", nil
+	}
+
+	// The budget only takes pods being deleted into account once
+	// they're gone, so the last step has to be all the way done before
+	// the budget can be trusted to take the next one.
+	if w.inProgress() || activePodCount(w.pods) > current {
+		return current, fmt.Sprintf(
+			"waiting for %s %q to scale down to %d replicas before scaling it down further to %d",
+			w.kind(), w.object().GetName(), current, desired), nil
+	}
+
+	contender, ready, err := c.contenderReady(ct, clusterName)
+	if err != nil {
+		return current, "", err
+	}
+
+	if !ready {
+		return current, fmt.Sprintf(
+			"waiting for contender %q to be ready before scaling down from %d to %d replicas",
+			contender, current, desired), nil
+	}
+
+	// More than one budget covering the same pods makes evictions
+	// fail, but we'd rather go by the strictest of them than not scale
+	// down at all.
+	var pdb *policyv1beta1.PodDisruptionBudget
+	for _, candidate := range w.pdbs {
+		if pdb == nil || candidate.Status.PodDisruptionsAllowed < pdb.Status.PodDisruptionsAllowed {
+			pdb = candidate
+		}
+	}
+
+	if pdb.Status.ObservedGeneration < pdb.Generation {
+		return current, fmt.Sprintf(
+			"waiting for PodDisruptionBudget %q to be brought up to date before scaling down from %d to %d replicas",
+			pdb.Name, current, desired), nil
+	}
+
+	// A budget covering the release's own pods never allows any
+	// disruptions once it's down to the pods it promises to keep, but
+	// with the contender ready those are the ones we mean to get rid
+	// of. The budget only sets the pace then, and it can't hold the
+	// incumbent back for good.
+	allowed := pdb.Status.PodDisruptionsAllowed
+	if allowed < 1 {
+		allowed = 1
+	}
+
+	next := current - allowed
+	if next <= desired {
+		return desired, "", nil
+	}
+
+	return next, fmt.Sprintf(
+		"scaling down from %d to %d replicas, %d at a time as allowed by PodDisruptionBudget %q",
+		current, desired, allowed, pdb.Name), nil
+}
+
+// contenderReady tells whether the release succeeding the one a capacity
+// target belongs to, if there is any, has all the capacity it asked for in
+// the given cluster, along with the name of that release.
+func (c *Controller) contenderReady(ct *shipper.CapacityTarget, clusterName string) (string, bool, error) {
+	appName := ct.Labels[shipper.AppLabel]
+	releaseName := ct.Labels[shipper.ReleaseLabel]
+
+	rel, err := c.releasesLister.Releases(ct.Namespace).Get(releaseName)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return "", true, nil
+		}
+
+		return "", false, shippererrors.NewKubeclientGetError(ct.Namespace, releaseName, err).
+			WithShipperKind("Release")
+	}
+
+	selector := labels.Set{shipper.AppLabel: appName}.AsSelector()
+	appReleases, err := c.releasesLister.Releases(ct.Namespace).List(selector)
+	if err != nil {
+		return "", false, shippererrors.NewKubeclientListError(
+			shipper.SchemeGroupVersion.WithKind("Release"),
+			ct.Namespace, selector, err)
+	}
+
+	_, contender, err := releaseutil.GetSiblingReleases(rel, appReleases)
+	if err != nil {
+		return "", false, shippererrors.NewUnrecoverableError(err)
+	}
+
+	if contender == nil {
+		return "", true, nil
+	}
+
+	contenderCT, err := c.getCapacityTargetForReleaseAndNamespace(contender.Name, ct.Namespace)
+	if err != nil {
+		return contender.Name, false, err
+	}
+
+	inSpec := false
+	for _, spec := range contenderCT.Spec.Clusters {
+		if spec.Name == clusterName {
+			inSpec = true
+			break
+		}
+	}
+
+	// A contender that isn't going to have any capacity in the cluster
+	// has nothing to warm up there.
+	if !inSpec {
+		return contender.Name, true, nil
+	}
+
+	if contenderCT.Status.ObservedGeneration < contenderCT.Generation {
+		return contender.Name, false, nil
+	}
+
+	for _, status := range contenderCT.Status.Clusters {
+		if status.Name == clusterName {
+			return contender.Name, clusterstatusutil.IsClusterCapacityReady(status.Conditions), nil
+		}
+	}
+
+	return contender.Name, false, nil
+}
+
+// activePodCount returns how many of the given pods haven't run to
+// completion, including those still being deleted.
+func activePodCount(pods []*corev1.Pod) int32 {
+	var count int32
+	for _, pod := range pods {
+		if pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed {
+			count++
+		}
+	}
+
+	return count
+}
@@ -0,0 +1,177 @@
+package capacity
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	kubeinformers "k8s.io/client-go/informers"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+)
+
+const (
+	// PodEventLimit is how many of the most recent warnings about a pod
+	// are reported along with it.
+	PodEventLimit = 3
+
+	podEventIndex = "involvedPod"
+)
+
+// podEventInformer returns the informer for the warnings Kubernetes
+// records about pods in a cluster. Clusters have lots of events, and only
+// these are of any use to tell why pods aren't ready, so the informer
+// doesn't ask for any others.
+func podEventInformer(informerFactory kubeinformers.SharedInformerFactory) cache.SharedIndexInformer {
+	return informerFactory.InformerFor(&corev1.Event{}, newPodEventInformer)
+}
+
+func newPodEventInformer(client kubernetes.Interface, resyncPeriod time.Duration) cache.SharedIndexInformer {
+	selector := fields.AndSelectors(
+		fields.OneTermEqualSelector("involvedObject.kind", "Pod"),
+		fields.OneTermEqualSelector("type", corev1.EventTypeWarning),
+	).String()
+
+	return coreinformers.NewFilteredEventInformer(
+		client,
+		metav1.NamespaceAll,
+		resyncPeriod,
+		cache.Indexers{
+			cache.NamespaceIndex: cache.MetaNamespaceIndexFunc,
+			podEventIndex:        podEventIndexFunc,
+		},
+		func(options *metav1.ListOptions) {
+			options.FieldSelector = selector
+		},
+	)
+}
+
+// podEventIndexFunc indexes pod warnings by the namespace and name of the
+// pod they are about.
+func podEventIndexFunc(obj interface{}) ([]string, error) {
+	event, ok := obj.(*corev1.Event)
+	if !ok || !isPodWarning(event) {
+		return nil, nil
+	}
+
+	return []string{podEventKey(event.InvolvedObject.Namespace, event.InvolvedObject.Name)}, nil
+}
+
+func podEventKey(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+func isPodWarning(event *corev1.Event) bool {
+	return event.InvolvedObject.Kind == "Pod" && event.Type == corev1.EventTypeWarning
+}
+
+// getPodEvents returns the most recent warnings about a pod, newest first.
+// Warnings with the same reason and message are only reported once, and
+// warnings about an earlier pod with the same name are left out.
+func getPodEvents(informer cache.SharedIndexInformer, pod *corev1.Pod) ([]shipper.PodEvent, error) {
+	objs, err := informer.GetIndexer().ByIndex(podEventIndex, podEventKey(pod.Namespace, pod.Name))
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*corev1.Event, 0, len(objs))
+	for _, obj := range objs {
+		event, ok := obj.(*corev1.Event)
+		if !ok {
+			continue
+		}
+
+		if event.InvolvedObject.UID != "" && event.InvolvedObject.UID != pod.UID {
+			continue
+		}
+
+		events = append(events, event)
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return eventTime(events[j]).Before(eventTime(events[i]))
+	})
+
+	podEvents := []shipper.PodEvent{}
+	seen := map[shipper.PodEvent]struct{}{}
+	for _, event := range events {
+		podEvent := shipper.PodEvent{
+			Reason:  event.Reason,
+			Message: event.Message,
+		}
+
+		if _, ok := seen[podEvent]; ok {
+			continue
+		}
+		seen[podEvent] = struct{}{}
+
+		podEvents = append(podEvents, podEvent)
+		if len(podEvents) == PodEventLimit {
+			break
+		}
+	}
+
+	if len(podEvents) == 0 {
+		return nil, nil
+	}
+
+	return podEvents, nil
+}
+
+// eventTime is the last time an event was seen. Events recorded through
+// the events.k8s.io API don't set LastTimestamp.
+func eventTime(event *corev1.Event) time.Time {
+	switch {
+	case event.Series != nil:
+		return event.Series.LastObservedTime.Time
+	case !event.LastTimestamp.IsZero():
+		return event.LastTimestamp.Time
+	case !event.EventTime.IsZero():
+		return event.EventTime.Time
+	default:
+		return event.CreationTimestamp.Time
+	}
+}
+
+// enqueueCapacityTargetFromPodEvent enqueues the capacity target of the
+// release a warned about pod belongs to. Events don't carry the labels of
+// the objects they are about, so the pod has to be looked up for them.
+func (c *Controller) enqueueCapacityTargetFromPodEvent(informerFactory kubeinformers.SharedInformerFactory, obj interface{}) {
+	event, ok := obj.(*corev1.Event)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("not an Event: %#v", obj))
+		return
+	}
+
+	if !isPodWarning(event) {
+		return
+	}
+
+	pod, err := informerFactory.Core().V1().Pods().Lister().
+		Pods(event.InvolvedObject.Namespace).Get(event.InvolvedObject.Name)
+	if err != nil {
+		// The pod is already gone, or it was never cached because
+		// it has nothing to do with us.
+		return
+	}
+
+	rel, ok := pod.GetLabels()[shipper.ReleaseLabel]
+	if !ok {
+		return
+	}
+
+	ct, err := c.getCapacityTargetForReleaseAndNamespace(rel, pod.GetNamespace())
+	if err != nil {
+		runtime.HandleError(fmt.Errorf("cannot get capacity target for release '%s/%s': %#v", rel, pod.GetNamespace(), err))
+		return
+	}
+
+	c.enqueueCapacityTarget(ct)
+}
@@ -0,0 +1,198 @@
+package capacity
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/runtime"
+
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+	shippererrors "github.com/bookingcom/shipper/pkg/errors"
+	"github.com/bookingcom/shipper/pkg/util/replicas"
+)
+
+func (c *Controller) enqueueCapacityTargetFromHorizontalPodAutoscaler(obj interface{}) {
+	hpa, ok := obj.(*autoscalingv1.HorizontalPodAutoscaler)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("not a HorizontalPodAutoscaler: %#v", obj))
+		return
+	}
+
+	rel := hpa.GetLabels()[shipper.ReleaseLabel]
+	ct, err := c.getCapacityTargetForReleaseAndNamespace(rel, hpa.GetNamespace())
+	if err != nil {
+		runtime.HandleError(fmt.Errorf("cannot get capacity target for release '%s/%s': %#v", rel, hpa.GetNamespace(), err))
+		return
+	}
+
+	c.enqueueCapacityTarget(ct)
+}
+
+// getHorizontalPodAutoscaler returns the HorizontalPodAutoscaler that scales
+// the given workload, or nil if there is none.
+func (c Controller) getHorizontalPodAutoscaler(cluster string, w workload) (*autoscalingv1.HorizontalPodAutoscaler, error) {
+	informerFactory, err := c.clusterClientStore.GetInformerFactory(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	obj := w.object()
+	selector := labels.Everything()
+	hpas, err := informerFactory.Autoscaling().V1().HorizontalPodAutoscalers().
+		Lister().HorizontalPodAutoscalers(obj.GetNamespace()).List(selector)
+	if err != nil {
+		return nil, shippererrors.NewKubeclientListError(
+			autoscalingv1.SchemeGroupVersion.WithKind("HorizontalPodAutoscaler"),
+			obj.GetNamespace(), selector, err)
+	}
+
+	for _, hpa := range hpas {
+		ref := hpa.Spec.ScaleTargetRef
+		if ref.Kind == w.kind() && ref.Name == obj.GetName() {
+			return hpa, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// scaleWithHorizontalPodAutoscaler brings a workload scaled by an HPA to the
+// given capacity. Instead of setting the workload's replicas, which would
+// fight the HPA, it scales the HPA's bounds to a percentage of the ones it
+// was installed with. The workload itself is only touched to bring it into
+// those bounds, which is what the HPA would do anyway, or to scale it down to
+// zero, since HPAs can't go that low but stay away from workloads scaled
+// down to zero.
+//
+// It returns how many replicas the HPA currently allows, and whether it had
+// to change anything to get there.
+func (c *Controller) scaleWithHorizontalPodAutoscaler(
+	clusterName string,
+	percent int32,
+	w workload,
+	hpa *autoscalingv1.HorizontalPodAutoscaler,
+) (int32, bool, error) {
+	current := int32(1)
+	if replicas := w.replicas(); replicas != nil {
+		current = *replicas
+	}
+
+	if percent == 0 {
+		if current == 0 {
+			return 0, false, nil
+		}
+
+		err := c.patchWorkloadWithReplicaCount(w, clusterName, 0)
+		return 0, true, err
+	}
+
+	installedMin, installedMax := installedReplicaBounds(hpa)
+	minReplicas := int32(replicas.CalculateDesiredReplicaCount(uint(installedMin), float64(percent)))
+	maxReplicas := int32(replicas.CalculateDesiredReplicaCount(uint(installedMax), float64(percent)))
+	if minReplicas < 1 {
+		minReplicas = 1
+	}
+	if maxReplicas < minReplicas {
+		maxReplicas = minReplicas
+	}
+
+	changed := false
+
+	if hpa.Spec.MinReplicas == nil || *hpa.Spec.MinReplicas != minReplicas ||
+		hpa.Spec.MaxReplicas != maxReplicas ||
+		hpa.Annotations[shipper.HPAMinReplicasAnnotation] != strconv.Itoa(int(installedMin)) ||
+		hpa.Annotations[shipper.HPAMaxReplicasAnnotation] != strconv.Itoa(int(installedMax)) {
+		err := c.patchHorizontalPodAutoscalerWithBounds(hpa, clusterName, installedMin, installedMax, minReplicas, maxReplicas)
+		if err != nil {
+			return 0, false, err
+		}
+
+		changed = true
+	}
+
+	allowed := current
+	if allowed < minReplicas {
+		allowed = minReplicas
+	} else if allowed > maxReplicas {
+		allowed = maxReplicas
+	}
+
+	if allowed != current {
+		err := c.patchWorkloadWithReplicaCount(w, clusterName, allowed)
+		if err != nil {
+			return 0, false, err
+		}
+
+		changed = true
+	}
+
+	return allowed, changed, nil
+}
+
+// installedReplicaBounds returns the replica bounds an HPA was installed
+// with, which the capacity controller keeps in its annotations before it
+// changes them for the first time.
+func installedReplicaBounds(hpa *autoscalingv1.HorizontalPodAutoscaler) (int32, int32) {
+	minReplicas := int32(1)
+	if hpa.Spec.MinReplicas != nil {
+		minReplicas = *hpa.Spec.MinReplicas
+	}
+	maxReplicas := hpa.Spec.MaxReplicas
+
+	if v, err := strconv.ParseInt(hpa.Annotations[shipper.HPAMinReplicasAnnotation], 10, 32); err == nil {
+		minReplicas = int32(v)
+	}
+	if v, err := strconv.ParseInt(hpa.Annotations[shipper.HPAMaxReplicasAnnotation], 10, 32); err == nil {
+		maxReplicas = int32(v)
+	}
+
+	return minReplicas, maxReplicas
+}
+
+func (c *Controller) patchHorizontalPodAutoscalerWithBounds(
+	hpa *autoscalingv1.HorizontalPodAutoscaler,
+	clusterName string,
+	installedMin, installedMax, minReplicas, maxReplicas int32,
+) error {
+	targetClusterClient, err := c.clusterClientStore.GetClient(clusterName, AgentName)
+	if err != nil {
+		return err
+	}
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"annotations": map[string]string{
+				shipper.HPAMinReplicasAnnotation: strconv.Itoa(int(installedMin)),
+				shipper.HPAMaxReplicasAnnotation: strconv.Itoa(int(installedMax)),
+			},
+		},
+		"spec": map[string]interface{}{
+			"minReplicas": minReplicas,
+			"maxReplicas": maxReplicas,
+		},
+	})
+	if err != nil {
+		return shippererrors.NewUnrecoverableError(err)
+	}
+
+	_, err = targetClusterClient.AutoscalingV1().
+		HorizontalPodAutoscalers(hpa.Namespace).
+		Patch(hpa.Name, types.StrategicMergePatchType, patch)
+	if err != nil {
+		return shippererrors.NewKubeclientUpdateError(hpa, err)
+	}
+
+	return nil
+}
+
+// hpaAchievedPercent works out how much of the given capacity percentage a
+// deployment scaled by an HPA achieved, relative to the number of replicas
+// the HPA currently allows rather than the release's total replica count.
+func hpaAchievedPercent(percent, allowed, available int32) int32 {
+	return int32(math.Ceil(float64(available) / float64(allowed) * float64(percent)))
+}
@@ -1,6 +1,9 @@
 package capacity
 
 import (
+	"strconv"
+	"strings"
+
 	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
 )
 
@@ -17,3 +20,31 @@ func (c byClusterName) Swap(i, j int) {
 func (c byClusterName) Less(i, j int) bool {
 	return c[i].Name < c[j].Name
 }
+
+// podNameLess orders pods by name, except that pods of the same StatefulSet
+// are ordered by their ordinals, so that pod-2 comes before pod-10. That's
+// the order a StatefulSet brings its pods up in, so the first sad pod is the
+// one holding the others back.
+func podNameLess(a, b string) bool {
+	aPrefix, aOrdinal, aOK := splitOrdinal(a)
+	bPrefix, bOrdinal, bOK := splitOrdinal(b)
+	if aOK && bOK && aPrefix == bPrefix {
+		return aOrdinal < bOrdinal
+	}
+
+	return a < b
+}
+
+func splitOrdinal(name string) (string, int, bool) {
+	i := strings.LastIndex(name, "-")
+	if i == -1 {
+		return "", 0, false
+	}
+
+	ordinal, err := strconv.Atoi(name[i+1:])
+	if err != nil {
+		return "", 0, false
+	}
+
+	return name[:i], ordinal, true
+}
@@ -3,9 +3,13 @@ package capacity
 import (
 	"fmt"
 	"sort"
+	"strconv"
+	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
 	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
@@ -100,6 +104,14 @@ func buildDeployment(app, release string, replicas int32, availableReplicas int3
 					shipper.ReleaseLabel: release,
 				},
 			},
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						shipper.AppLabel:     app,
+						shipper.ReleaseLabel: release,
+					},
+				},
+			},
 		},
 		Status: appsv1.DeploymentStatus{
 			AvailableReplicas: availableReplicas,
@@ -127,3 +139,115 @@ func buildSadPodForDeployment(deployment *appsv1.Deployment) *corev1.Pod {
 		},
 	}
 }
+
+func buildPodEvent(pod *corev1.Pod, name, typ, reason, message string, lastTimestamp time.Time) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: pod.Namespace,
+			Name:      fmt.Sprintf("%s.%s", pod.Name, name),
+		},
+		InvolvedObject: corev1.ObjectReference{
+			Kind:      "Pod",
+			Namespace: pod.Namespace,
+			Name:      pod.Name,
+			UID:       pod.UID,
+		},
+		Type:          typ,
+		Reason:        reason,
+		Message:       message,
+		LastTimestamp: metav1.NewTime(lastTimestamp),
+	}
+}
+
+func buildPodDisruptionBudget(app string, disruptionsAllowed int32) *policyv1beta1.PodDisruptionBudget {
+	return &policyv1beta1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      app,
+			Namespace: shippertesting.TestNamespace,
+		},
+		Spec: policyv1beta1.PodDisruptionBudgetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					shipper.AppLabel: app,
+				},
+			},
+		},
+		Status: policyv1beta1.PodDisruptionBudgetStatus{
+			PodDisruptionsAllowed: disruptionsAllowed,
+		},
+	}
+}
+
+func buildRelease(app, name string, generation int) *shipper.Release {
+	return &shipper.Release{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: shippertesting.TestNamespace,
+			Labels: map[string]string{
+				shipper.AppLabel: app,
+			},
+			Annotations: map[string]string{
+				shipper.ReleaseGenerationAnnotation: strconv.Itoa(generation),
+			},
+		},
+	}
+}
+
+func buildHorizontalPodAutoscaler(app, release string, minReplicas, maxReplicas int32) *autoscalingv1.HorizontalPodAutoscaler {
+	return &autoscalingv1.HorizontalPodAutoscaler{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      release,
+			Namespace: shippertesting.TestNamespace,
+			Labels: map[string]string{
+				shipper.AppLabel:     app,
+				shipper.ReleaseLabel: release,
+			},
+		},
+		Spec: autoscalingv1.HorizontalPodAutoscalerSpec{
+			ScaleTargetRef: autoscalingv1.CrossVersionObjectReference{
+				APIVersion: "apps/v1",
+				Kind:       "Deployment",
+				Name:       release,
+			},
+			MinReplicas: &minReplicas,
+			MaxReplicas: maxReplicas,
+		},
+	}
+}
+
+func buildStatefulSet(app, release string, replicas int32, readyReplicas int32) *appsv1.StatefulSet {
+	return &appsv1.StatefulSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      release,
+			Namespace: shippertesting.TestNamespace,
+			Labels: map[string]string{
+				shipper.AppLabel:     app,
+				shipper.ReleaseLabel: release,
+			},
+		},
+		Spec: appsv1.StatefulSetSpec{
+			Replicas: &replicas,
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					shipper.AppLabel:     app,
+					shipper.ReleaseLabel: release,
+				},
+			},
+		},
+		Status: appsv1.StatefulSetStatus{
+			ReadyReplicas: readyReplicas,
+		},
+	}
+}
+
+func buildSadPodForStatefulSet(statefulSet *appsv1.StatefulSet, ordinal int) *corev1.Pod {
+	pod := buildSadPodForDeployment(&appsv1.Deployment{
+		ObjectMeta: statefulSet.ObjectMeta,
+		Spec: appsv1.DeploymentSpec{
+			Selector: statefulSet.Spec.Selector,
+		},
+	})
+	pod.Name = fmt.Sprintf("%s-%d", statefulSet.Name, ordinal)
+
+	return pod
+}
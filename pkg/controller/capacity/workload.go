@@ -0,0 +1,162 @@
+package capacity
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	corev1 "k8s.io/api/core/v1"
+	policyv1beta1 "k8s.io/api/policy/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+	shippererrors "github.com/bookingcom/shipper/pkg/errors"
+)
+
+// workload is the object a release's capacity is scaled through in a
+// cluster, which is either a Deployment or a StatefulSet. Exactly one of
+// them is set.
+type workload struct {
+	deployment  *appsv1.Deployment
+	statefulSet *appsv1.StatefulSet
+}
+
+// clusterWorkload is a workload found in a cluster, along with everything
+// the capacity controller needs to know to scale it.
+type clusterWorkload struct {
+	workload
+	hpa               *autoscalingv1.HorizontalPodAutoscaler
+	pdbs              []*policyv1beta1.PodDisruptionBudget
+	pods              []*corev1.Pod
+	totalReplicaCount int32
+	// podEvents are the most recent warnings about each of the pods,
+	// by pod name.
+	podEvents map[string][]shipper.PodEvent
+}
+
+// findWorkload returns the workload of the given kind and name.
+func findWorkload(workloads []workload, kind, name string) (workload, bool) {
+	for _, w := range workloads {
+		if w.kind() == kind && w.object().GetName() == name {
+			return w, true
+		}
+	}
+
+	return workload{}, false
+}
+
+func (w workload) object() interface {
+	metav1.Object
+	runtime.Object
+} {
+	if w.statefulSet != nil {
+		return w.statefulSet
+	}
+
+	return w.deployment
+}
+
+func (w workload) kind() string {
+	if w.statefulSet != nil {
+		return "StatefulSet"
+	}
+
+	return "Deployment"
+}
+
+func (w workload) selector() *metav1.LabelSelector {
+	if w.statefulSet != nil {
+		return w.statefulSet.Spec.Selector
+	}
+
+	return w.deployment.Spec.Selector
+}
+
+// podLabels returns the labels the workload's pods are created with.
+func (w workload) podLabels() map[string]string {
+	if w.statefulSet != nil {
+		return w.statefulSet.Spec.Template.Labels
+	}
+
+	return w.deployment.Spec.Template.Labels
+}
+
+func (w workload) replicas() *int32 {
+	if w.statefulSet != nil {
+		return w.statefulSet.Spec.Replicas
+	}
+
+	return w.deployment.Spec.Replicas
+}
+
+// availableReplicas returns how many of the workload's pods can take
+// traffic. StatefulSets don't report available replicas, so their ready
+// replicas count instead.
+func (w workload) availableReplicas() int32 {
+	if w.statefulSet != nil {
+		return w.statefulSet.Status.ReadyReplicas
+	}
+
+	return w.deployment.Status.AvailableReplicas
+}
+
+// inProgress tells whether the workload's controller hasn't caught up with
+// its spec yet. For StatefulSets, that includes rolling out a new revision
+// to the pods from the partition up, the same way "kubectl rollout status"
+// does: pods below the partition are meant to stay on the revision they
+// have.
+func (w workload) inProgress() bool {
+	if w.statefulSet == nil {
+		return w.deployment.Generation > w.deployment.Status.ObservedGeneration
+	}
+
+	s := w.statefulSet
+	if s.Generation > s.Status.ObservedGeneration {
+		return true
+	}
+
+	if s.Spec.UpdateStrategy.Type != appsv1.RollingUpdateStatefulSetStrategyType ||
+		s.Status.UpdateRevision == "" || s.Status.UpdateRevision == s.Status.CurrentRevision {
+		return false
+	}
+
+	replicas := int32(1)
+	if s.Spec.Replicas != nil {
+		replicas = *s.Spec.Replicas
+	}
+
+	partition := int32(0)
+	if s.Spec.UpdateStrategy.RollingUpdate != nil && s.Spec.UpdateStrategy.RollingUpdate.Partition != nil {
+		partition = *s.Spec.UpdateStrategy.RollingUpdate.Partition
+	}
+
+	return s.Status.UpdatedReplicas < replicas-partition
+}
+
+func (c *Controller) patchWorkloadWithReplicaCount(w workload, clusterName string, replicaCount int32) error {
+	if w.statefulSet == nil {
+		_, err := c.patchDeploymentWithReplicaCount(w.deployment, clusterName, replicaCount)
+		return err
+	}
+
+	targetClusterClient, err := c.clusterClientStore.GetClient(clusterName, AgentName)
+	if err != nil {
+		return err
+	}
+
+	// Only the number of replicas changes, so the StatefulSet controller
+	// adds and removes pods in order of their ordinals, and a partitioned
+	// update strategy is left alone.
+	patch := []byte(fmt.Sprintf(`{"spec": {"replicas": %d}}`, replicaCount))
+
+	_, err = targetClusterClient.AppsV1().
+		StatefulSets(w.statefulSet.Namespace).
+		Patch(w.statefulSet.Name, types.StrategicMergePatchType, patch)
+	if err != nil {
+		return shippererrors.NewKubeclientUpdateError(w.statefulSet, err)
+	}
+
+	return nil
+}
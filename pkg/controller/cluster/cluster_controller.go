@@ -0,0 +1,238 @@
+package cluster
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/apimachinery/pkg/util/wait"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"k8s.io/klog"
+
+	clientset "github.com/bookingcom/shipper/pkg/client/clientset/versioned"
+	informers "github.com/bookingcom/shipper/pkg/client/informers/externalversions"
+	listers "github.com/bookingcom/shipper/pkg/client/listers/shipper/v1alpha1"
+	"github.com/bookingcom/shipper/pkg/clusterclientstore"
+	shippererrors "github.com/bookingcom/shipper/pkg/errors"
+	"github.com/bookingcom/shipper/pkg/util/headroom"
+	shipperworkqueue "github.com/bookingcom/shipper/pkg/workqueue"
+)
+
+const (
+	AgentName = "cluster-controller"
+
+	// HeadroomInterval is how often, at most, the headroom of a cluster
+	// is worked out again. Nodes and pods change all the time, and the
+	// scheduler doesn't need to know about every single one of those
+	// changes.
+	HeadroomInterval = 30 * time.Second
+)
+
+// Controller keeps the status of Cluster objects up to date with how much
+// room their nodes have left for new pods.
+type Controller struct {
+	shipperclientset   clientset.Interface
+	clusterClientStore clusterclientstore.Interface
+	clustersLister     listers.ClusterLister
+	clustersSynced     cache.InformerSynced
+	workqueue          workqueue.RateLimitingInterface
+	recorder           record.EventRecorder
+	now                func() time.Time
+}
+
+// NewController returns a new Cluster controller.
+func NewController(
+	shipperclientset clientset.Interface,
+	shipperInformerFactory informers.SharedInformerFactory,
+	store clusterclientstore.Interface,
+	recorder record.EventRecorder,
+) *Controller {
+	clusterInformer := shipperInformerFactory.Shipper().V1alpha1().Clusters()
+
+	controller := &Controller{
+		shipperclientset:   shipperclientset,
+		clusterClientStore: store,
+		clustersLister:     clusterInformer.Lister(),
+		clustersSynced:     clusterInformer.Informer().HasSynced,
+		workqueue:          workqueue.NewNamedRateLimitingQueue(shipperworkqueue.NewDefaultControllerRateLimiter(), "cluster_controller_clusters"),
+		recorder:           recorder,
+		now:                time.Now,
+	}
+
+	klog.Info("Setting up event handlers")
+	clusterInformer.Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.enqueueCluster,
+	})
+
+	store.AddSubscriptionCallback(controller.subscribeToNodesAndPods)
+	store.AddEventHandlerCallback(controller.registerNodeAndPodEventHandlers)
+
+	return controller
+}
+
+// Run will set up the event handlers for types we are interested in, as well
+// as syncing informer caches and starting workers. It will block until stopCh
+// is closed, at which point it will shutdown the workqueue and wait for
+// workers to finish processing their current work items.
+func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) {
+	defer runtime.HandleCrash()
+	defer c.workqueue.ShutDown()
+
+	klog.V(2).Info("Starting Cluster controller")
+	defer klog.V(2).Info("Shutting down Cluster controller")
+
+	if !cache.WaitForCacheSync(stopCh, c.clustersSynced) {
+		runtime.HandleError(fmt.Errorf("failed to wait for caches to sync"))
+		return
+	}
+
+	for i := 0; i < threadiness; i++ {
+		go wait.Until(c.runWorker, time.Second, stopCh)
+	}
+
+	klog.V(4).Info("Started Cluster controller")
+
+	<-stopCh
+}
+
+func (c *Controller) runWorker() {
+	for c.processNextWorkItem() {
+	}
+}
+
+func (c *Controller) processNextWorkItem() bool {
+	obj, shutdown := c.workqueue.Get()
+	if shutdown {
+		return false
+	}
+
+	defer c.workqueue.Done(obj)
+
+	var (
+		key string
+		ok  bool
+	)
+
+	if key, ok = obj.(string); !ok {
+		c.workqueue.Forget(obj)
+		runtime.HandleError(fmt.Errorf("invalid object key (will retry: false): %#v", obj))
+		return true
+	}
+
+	shouldRetry := false
+	err := c.syncClusterHeadroom(key)
+
+	if err != nil {
+		shouldRetry = shippererrors.ShouldRetry(err)
+		runtime.HandleError(fmt.Errorf("error syncing Cluster %q (will retry: %t): %s", key, shouldRetry, err.Error()))
+	}
+
+	if shouldRetry {
+		c.workqueue.AddRateLimited(key)
+
+		return true
+	}
+
+	klog.V(4).Infof("Successfully synced Cluster %q", key)
+	c.workqueue.Forget(obj)
+
+	return true
+}
+
+// syncClusterHeadroom works out how much room a cluster has left for new
+// pods, and records it in the cluster's status if that changed.
+func (c *Controller) syncClusterHeadroom(name string) error {
+	cluster, err := c.clustersLister.Get(name)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			klog.V(3).Infof("Cluster %q has been deleted", name)
+			return nil
+		}
+
+		return shippererrors.NewKubeclientGetError("", name, err).
+			WithShipperKind("Cluster")
+	}
+
+	informerFactory, err := c.clusterClientStore.GetInformerFactory(name)
+	if err != nil {
+		return err
+	}
+
+	selector := labels.Everything()
+	nodes, err := informerFactory.Core().V1().Nodes().Lister().List(selector)
+	if err != nil {
+		return shippererrors.NewKubeclientListError(
+			corev1.SchemeGroupVersion.WithKind("Node"), "", selector, err)
+	}
+
+	pods, err := informerFactory.Core().V1().Pods().Lister().List(selector)
+	if err != nil {
+		return shippererrors.NewKubeclientListError(
+			corev1.SchemeGroupVersion.WithKind("Pod"), "", selector, err)
+	}
+
+	current := headroom.Compute(nodes, pods)
+	if previous := cluster.Status.Headroom; previous != nil &&
+		equality.Semantic.DeepEqual(previous.Allocatable, current.Allocatable) &&
+		equality.Semantic.DeepEqual(previous.Requested, current.Requested) {
+		return nil
+	}
+
+	current.LastUpdateTime = metav1.NewTime(c.now())
+
+	cluster = cluster.DeepCopy()
+	cluster.Status.Headroom = current
+
+	_, err = c.shipperclientset.ShipperV1alpha1().Clusters().Update(cluster)
+	if err != nil {
+		return shippererrors.NewKubeclientUpdateError(cluster, err).
+			WithShipperKind("Cluster")
+	}
+
+	klog.V(4).Infof("Cluster %q has %s left", name, headroom.String(current.Available))
+
+	return nil
+}
+
+func (c *Controller) enqueueCluster(obj interface{}) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		runtime.HandleError(err)
+		return
+	}
+
+	c.workqueue.Add(key)
+}
+
+func (c *Controller) subscribeToNodesAndPods(informerFactory kubeinformers.SharedInformerFactory) {
+	informerFactory.Core().V1().Nodes().Informer()
+	informerFactory.Core().V1().Pods().Informer()
+}
+
+// registerNodeAndPodEventHandlers makes any change to the nodes or pods of a
+// cluster bring its headroom up to date, though not before HeadroomInterval
+// passes.
+func (c *Controller) registerNodeAndPodEventHandlers(informerFactory kubeinformers.SharedInformerFactory, clusterName string) {
+	enqueue := func(interface{}) {
+		c.workqueue.AddAfter(clusterName, HeadroomInterval)
+	}
+
+	handler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    enqueue,
+		DeleteFunc: enqueue,
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			enqueue(newObj)
+		},
+	}
+
+	informerFactory.Core().V1().Nodes().Informer().AddEventHandler(handler)
+	informerFactory.Core().V1().Pods().Informer().AddEventHandler(handler)
+}
@@ -0,0 +1,122 @@
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+	shippertesting "github.com/bookingcom/shipper/pkg/testing"
+)
+
+const clusterName = "cluster-a"
+
+func buildNode(name, cpu, memory string) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(memory),
+			},
+		},
+	}
+}
+
+func buildPod(name, node, cpu, memory string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: shippertesting.TestNamespace,
+		},
+		Spec: corev1.PodSpec{
+			NodeName: node,
+			Containers: []corev1.Container{
+				{
+					Name: "app",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse(cpu),
+							corev1.ResourceMemory: resource.MustParse(memory),
+						},
+					},
+				},
+			},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func TestClusterHeadroomIsRecorded(t *testing.T) {
+	f := shippertesting.NewControllerTestFixture()
+
+	cluster := &shipper.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: clusterName},
+		Spec:       shipper.ClusterSpec{Region: shippertesting.TestRegion},
+	}
+	f.ShipperClient.Tracker().Add(cluster)
+
+	f.AddNamedCluster(clusterName).AddMany([]runtime.Object{
+		buildNode("node-a", "4", "8Gi"),
+		buildNode("node-b", "4", "8Gi"),
+		buildPod("pod-a", "node-a", "1500m", "3Gi"),
+		buildPod("pod-b", "node-b", "500m", "1Gi"),
+	})
+
+	now := time.Date(2020, 3, 2, 10, 0, 0, 0, time.UTC)
+	runController(f, func() time.Time { return now })
+
+	clusterGVR := shipper.SchemeGroupVersion.WithResource("clusters")
+	object, err := f.ShipperClient.Tracker().Get(clusterGVR, "", clusterName)
+	if err != nil {
+		t.Fatalf("could not Get Cluster %q: %s", clusterName, err)
+	}
+
+	headroom := object.(*shipper.Cluster).Status.Headroom
+	if headroom == nil {
+		t.Fatalf("expected Cluster %q to have its headroom recorded", clusterName)
+	}
+
+	expected := map[corev1.ResourceName]string{
+		corev1.ResourceCPU:    "6",
+		corev1.ResourceMemory: "12Gi",
+	}
+	for name, value := range expected {
+		q := headroom.Available[name]
+		if q.Cmp(resource.MustParse(value)) != 0 {
+			t.Errorf("expected %s headroom to be %s, got %s", name, value, q.String())
+		}
+	}
+
+	if !headroom.LastUpdateTime.Time.Equal(now) {
+		t.Errorf("expected headroom to be updated at %s, got %s", now, headroom.LastUpdateTime)
+	}
+}
+
+func runController(f *shippertesting.ControllerTestFixture, now func() time.Time) {
+	controller := NewController(
+		f.ShipperClient,
+		f.ShipperInformerFactory,
+		f.ClusterClientStore,
+		f.Recorder,
+	)
+	controller.now = now
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	f.Run(stopCh)
+
+	for controller.processNextWorkItem() {
+		if controller.workqueue.Len() == 0 {
+			time.Sleep(20 * time.Millisecond)
+		}
+		if controller.workqueue.Len() == 0 {
+			return
+		}
+	}
+}
@@ -132,11 +132,13 @@ func (c *Controller) registerAppClusterEventHandlers(informerFactory kubeinforme
 		},
 	}
 	informerFactory.Apps().V1().Deployments().Informer().AddEventHandler(handler)
+	informerFactory.Apps().V1().StatefulSets().Informer().AddEventHandler(handler)
 	informerFactory.Core().V1().Services().Informer().AddEventHandler(handler)
 }
 
 func (c *Controller) subscribeToAppClusterEvents(informerFactory kubeinformers.SharedInformerFactory) {
 	informerFactory.Apps().V1().Deployments().Informer()
+	informerFactory.Apps().V1().StatefulSets().Informer()
 	informerFactory.Core().V1().Services().Informer()
 }
 
@@ -212,6 +212,17 @@ func (i *Installer) install(
 
 				unstructured.SetNestedField(newUnstructuredObj, clusterIP, "spec", "clusterIP")
 			}
+
+			// Blue/green strategies point the Service at a single
+			// release. Overwriting that would suddenly send
+			// traffic to every release, so we keep it.
+			if release, ok, err := unstructured.NestedString(existingUnstructuredObj, "spec", "selector", shipper.ReleaseLabel); ok {
+				if err != nil {
+					return err
+				}
+
+				unstructured.SetNestedField(newUnstructuredObj, release, "spec", "selector", shipper.ReleaseLabel)
+			}
 		}
 
 		unstructured.SetNestedField(existingUnstructuredObj, newUnstructuredObj["spec"], "spec")
@@ -555,3 +555,136 @@ func TestInstallerNoOverride(t *testing.T) {
 	shippertesting.ShallowCheckActions(expectedActions, fakeCluster.Client.Actions(), t)
 	shippertesting.ShallowCheckActions(expectedDynamicActions, fakeCluster.DynamicClient.Actions(), t)
 }
+
+const statefulSetManifest = `
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: reviews-api-db
+  labels:
+    app: reviews-api-db
+spec:
+  replicas: 3
+  serviceName: reviews-api
+  selector:
+    matchLabels:
+      app: reviews-api-db
+  template:
+    metadata:
+      labels:
+        app: reviews-api-db
+    spec:
+      containers:
+      - name: db
+        image: redis:stable
+`
+
+const serviceManifest = `
+apiVersion: v1
+kind: Service
+metadata:
+  name: reviews-api
+  labels:
+    app: reviews-api
+spec:
+  selector:
+    app: reviews-api
+  ports:
+  - port: 8080
+`
+
+// TestPrepareObjectsStatefulSet verifies that StatefulSets are prepared for
+// installation just like Deployments are: they start with no replicas, and
+// they and their pods carry the release's labels.
+func TestPrepareObjectsStatefulSet(t *testing.T) {
+	chart := buildChart("reviews-api", "0.0.1", repoUrl)
+	it := buildInstallationTarget("reviews-api", "reviews-api", []string{"minikube-a"}, &chart)
+
+	objects, err := prepareObjects(it, []string{statefulSetManifest, serviceManifest})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var statefulSet *appsv1.StatefulSet
+	for _, obj := range objects {
+		if s, ok := obj.(*appsv1.StatefulSet); ok {
+			statefulSet = s
+		}
+	}
+	if statefulSet == nil {
+		t.Fatalf("expected a StatefulSet to be prepared, got %v", objects)
+	}
+
+	if *statefulSet.Spec.Replicas != 0 {
+		t.Errorf("expected StatefulSet to start with 0 replicas, got %d", *statefulSet.Spec.Replicas)
+	}
+
+	for _, labels := range []map[string]string{
+		statefulSet.Labels,
+		statefulSet.Spec.Selector.MatchLabels,
+		statefulSet.Spec.Template.Labels,
+	} {
+		if labels[shipper.InstallationTargetOwnerLabel] != it.Name || labels["app"] != "reviews-api-db" {
+			t.Errorf("expected labels to keep the chart's and include the release's, got %v", labels)
+		}
+	}
+}
+
+// TestPrepareObjectsStatefulSetInvalidName verifies that StatefulSets need
+// to be named after the release, just like Deployments do.
+func TestPrepareObjectsStatefulSetInvalidName(t *testing.T) {
+	chart := buildChart("reviews-api", "0.0.1", repoUrl)
+	it := buildInstallationTarget("reviews-api", "reviews-api-deadbeef-0", []string{"minikube-a"}, &chart)
+
+	_, err := prepareObjects(it, []string{statefulSetManifest, serviceManifest})
+	if _, ok := err.(shippererrors.InvalidChartError); !ok {
+		t.Fatalf("expected an InvalidChartError, got %v instead", err)
+	}
+}
+
+const optedOutDeploymentManifest = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: reviews-api-cron
+  annotations:
+    shipper.booking.com/capacity.optOut: "true"
+spec:
+  replicas: 2
+  selector:
+    matchLabels:
+      app: reviews-api-cron
+  template:
+    metadata:
+      labels:
+        app: reviews-api-cron
+    spec:
+      containers:
+      - name: cron
+        image: busybox
+`
+
+// TestPrepareObjectsOptedOutDeployment verifies that Deployments that opted
+// out of capacity management keep the replicas their chart gives them.
+func TestPrepareObjectsOptedOutDeployment(t *testing.T) {
+	chart := buildChart("reviews-api", "0.0.1", repoUrl)
+	it := buildInstallationTarget("reviews-api", "reviews-api", []string{"minikube-a"}, &chart)
+
+	objects, err := prepareObjects(it, []string{statefulSetManifest, optedOutDeploymentManifest, serviceManifest})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	for _, obj := range objects {
+		switch o := obj.(type) {
+		case *appsv1.Deployment:
+			if *o.Spec.Replicas != 2 {
+				t.Errorf("expected opted out Deployment to keep its 2 replicas, got %d", *o.Spec.Replicas)
+			}
+		case *appsv1.StatefulSet:
+			if *o.Spec.Replicas != 0 {
+				t.Errorf("expected StatefulSet to start with 0 replicas, got %d", *o.Spec.Replicas)
+			}
+		}
+	}
+}
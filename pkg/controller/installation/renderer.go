@@ -8,6 +8,7 @@ import (
 	shipperchart "github.com/bookingcom/shipper/pkg/chart"
 	shipperrepo "github.com/bookingcom/shipper/pkg/chart/repo"
 	shippererrors "github.com/bookingcom/shipper/pkg/errors"
+	capacityutil "github.com/bookingcom/shipper/pkg/util/capacity"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -74,18 +75,19 @@ func prepareObjects(it *shipper.InstallationTarget, manifests []string) ([]runti
 			// otherwise, we try to overwrite a previous
 			// Deployment, and that fails with a "field is
 			// immutable" error.
-			deploymentName := obj.Name
-			expectedName := it.Name
-			if !strings.Contains(deploymentName, expectedName) {
-				return nil, shippererrors.NewInvalidChartError(
-					fmt.Sprintf("Deployment %q has invalid name."+
-						" The name of the Deployment should be"+
-						" templated with {{.Release.Name}}.",
-						deploymentName),
-				)
+			if err := validateWorkloadName(it, "Deployment", obj.Name); err != nil {
+				return nil, err
 			}
 
 			decodedObj = patchDeployment(obj, shipperLabels)
+		case *appsv1.StatefulSet:
+			// StatefulSets are scaled just like Deployments, so
+			// the same goes for their names.
+			if err := validateWorkloadName(it, "StatefulSet", obj.Name); err != nil {
+				return nil, err
+			}
+
+			decodedObj = patchStatefulSet(obj, shipperLabels)
 		case *corev1.Service:
 			allServices = append(allServices, obj)
 
@@ -128,31 +130,76 @@ func prepareObjects(it *shipper.InstallationTarget, manifests []string) ([]runti
 	return preparedObjects, nil
 }
 
+func validateWorkloadName(it *shipper.InstallationTarget, kind, name string) error {
+	if !strings.Contains(name, it.Name) {
+		return shippererrors.NewInvalidChartError(
+			fmt.Sprintf("%s %q has invalid name."+
+				" The name of the %s should be"+
+				" templated with {{.Release.Name}}.",
+				kind, name, kind),
+		)
+	}
+
+	return nil
+}
+
 func patchDeployment(d *appsv1.Deployment, labelsToInject map[string]string) runtime.Object {
-	replicas := int32(0)
-	d.Spec.Replicas = &replicas
+	// Deployments that opted out of capacity management keep the replicas
+	// their chart gives them, since the capacity controller won't ever
+	// scale them up.
+	if !capacityutil.OptedOut(d) {
+		replicas := int32(0)
+		d.Spec.Replicas = &replicas
+	}
+	d.Spec.Selector = injectSelectorLabels(d.Spec.Selector, labelsToInject)
+	injectPodTemplateLabels(&d.Spec.Template, labelsToInject)
+
+	return d
+}
+
+func patchStatefulSet(s *appsv1.StatefulSet, labelsToInject map[string]string) runtime.Object {
+	// StatefulSets that opted out of capacity management keep the replicas
+	// their chart gives them, since the capacity controller won't ever
+	// scale them up.
+	if !capacityutil.OptedOut(s) {
+		replicas := int32(0)
+		s.Spec.Replicas = &replicas
+	}
+	s.Spec.Selector = injectSelectorLabels(s.Spec.Selector, labelsToInject)
+	injectPodTemplateLabels(&s.Spec.Template, labelsToInject)
+
+	return s
+}
 
+func injectSelectorLabels(selector *metav1.LabelSelector, labelsToInject map[string]string) *metav1.LabelSelector {
 	var newSelector *metav1.LabelSelector
-	if d.Spec.Selector != nil {
-		newSelector = d.Spec.Selector.DeepCopy()
+	if selector != nil {
+		newSelector = selector.DeepCopy()
 	} else {
-		newSelector = &metav1.LabelSelector{
-			MatchLabels: map[string]string{},
-		}
+		newSelector = &metav1.LabelSelector{}
+	}
+
+	if newSelector.MatchLabels == nil {
+		newSelector.MatchLabels = map[string]string{}
 	}
 
 	for k, v := range labelsToInject {
 		newSelector.MatchLabels[k] = v
 	}
-	d.Spec.Selector = newSelector
 
-	podTemplateLabels := d.Spec.Template.Labels
+	return newSelector
+}
+
+func injectPodTemplateLabels(template *corev1.PodTemplateSpec, labelsToInject map[string]string) {
+	podTemplateLabels := template.Labels
+	if podTemplateLabels == nil {
+		podTemplateLabels = map[string]string{}
+	}
+
 	for k, v := range labelsToInject {
 		podTemplateLabels[k] = v
 	}
-	d.Spec.Template.SetLabels(podTemplateLabels)
-
-	return d
+	template.SetLabels(podTemplateLabels)
 }
 
 func patchService(it *shipper.InstallationTarget, s *corev1.Service) error {
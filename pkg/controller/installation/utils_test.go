@@ -107,12 +107,13 @@ func buildDeployment() *appsv1.Deployment {
 
 // buildCluster returns a cluster.
 func buildCluster(name string) *shipper.Cluster {
+	inService := true
 	return &shipper.Cluster{
 		ObjectMeta: v1.ObjectMeta{
 			Name: name,
 		},
 		Status: shipper.ClusterStatus{
-			InService: true,
+			InService: &inService,
 		},
 	}
 }
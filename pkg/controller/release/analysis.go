@@ -0,0 +1,271 @@
+package release
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+	"github.com/bookingcom/shipper/pkg/util/conditions"
+	releaseutil "github.com/bookingcom/shipper/pkg/util/release"
+)
+
+const (
+	AnalysisFailed = "AnalysisFailed"
+	AnalysisError  = "AnalysisError"
+
+	// analysisInterval is how often a release waiting on a step analysis
+	// gets its metrics queried again.
+	analysisInterval = time.Minute
+)
+
+var analysisClient = &http.Client{Timeout: 10 * time.Second}
+
+type analysisQueryParams struct {
+	Release     string
+	Namespace   string
+	Application string
+}
+
+// prometheusResponse is the subset of the Prometheus HTTP API response for
+// instant queries that analyses care about.
+type prometheusResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	} `json:"data"`
+}
+
+// genAnalysisEnforcer runs the target step's analysis, unless the release
+// has already passed it or achieved the step. Metrics keep moving after a
+// step is done, and a release that got there is not to be held back, let
+// alone aborted, because of them.
+func genAnalysisEnforcer(curr, prev *releaseInfo) PipelineStep {
+	return func(e *StrategyExecutor, cond conditions.StrategyConditionsMap) (PipelineContinuation, []StrategyPatch, []ReleaseStrategyStateTransition) {
+		strategy := curr.release.Spec.Environment.Strategy
+		targetStep := curr.release.Spec.TargetStep
+		isLastStep := int(targetStep) == len(strategy.Steps)-1
+		analysis := strategy.Steps[targetStep].Analysis
+
+		if cond.IsTrue(targetStep, shipper.StrategyConditionContenderPassedAnalysis) {
+			return PipelineContinue, nil, nil
+		}
+
+		if achieved := curr.release.Status.AchievedStep; achieved != nil && achieved.Step == targetStep {
+			cond.SetTrue(
+				shipper.StrategyConditionContenderPassedAnalysis,
+				conditions.StrategyConditionsUpdate{
+					Step:               targetStep,
+					LastTransitionTime: time.Now(),
+				},
+			)
+			return PipelineContinue, nil, nil
+		}
+
+		var incumbent *shipper.Release
+		if prev != nil {
+			incumbent = prev.release
+		}
+
+		passed, reason, msg := runAnalysis(analysis, curr.release, incumbent)
+		if !passed {
+			e.info("release hasn't passed analysis yet: %s", msg)
+
+			cond.SetFalse(
+				shipper.StrategyConditionContenderPassedAnalysis,
+				conditions.StrategyConditionsUpdate{
+					Reason:             reason,
+					Message:            msg,
+					Step:               targetStep,
+					LastTransitionTime: time.Now(),
+				},
+			)
+
+			patches := make([]StrategyPatch, 0, 2)
+			relPatch := buildContenderStrategyConditionsPatch(
+				e.curr.release.Name,
+				cond,
+				targetStep,
+				isLastStep,
+				e.hasIncumbent,
+				e.buildClusterStrategyStatus(),
+				e.pluginConditionTypes()...,
+			)
+			if relPatch.Alters(e.curr.release) {
+				patches = append(patches, relPatch)
+			}
+
+			// Aborting means going back to the incumbent, so there
+			// has to be one.
+			if reason == AnalysisFailed && analysis.AbortOnFailure && incumbent != nil {
+				patches = append(patches, &ReleaseAbortPatch{
+					Name:   curr.release.Name,
+					Reason: msg,
+				})
+			}
+
+			return PipelineBreak, patches, nil
+		}
+
+		e.info("release has passed analysis")
+
+		cond.SetTrue(
+			shipper.StrategyConditionContenderPassedAnalysis,
+			conditions.StrategyConditionsUpdate{
+				Step:               targetStep,
+				LastTransitionTime: time.Now(),
+			},
+		)
+
+		return PipelineContinue, nil, nil
+	}
+}
+
+// runAnalysis evaluates all the metric checks of an analysis against the
+// contender and, if there is one, the incumbent. It returns whether all
+// checks passed and, if they did not, a reason and a message explaining
+// why. The reason is AnalysisFailed if a threshold was crossed, and
+// AnalysisError if the checks could not be evaluated at all.
+func runAnalysis(analysis *shipper.RolloutStrategyStepAnalysis, contender, incumbent *shipper.Release) (bool, string, string) {
+	for _, metric := range analysis.Metrics {
+		value, err := queryMetric(analysis.URL, metric.Query, contender)
+		if err != nil {
+			return false, AnalysisError, fmt.Sprintf("metric %q: %s", metric.Name, err)
+		}
+
+		if metric.Min != "" {
+			min, err := strconv.ParseFloat(metric.Min, 64)
+			if err != nil {
+				return false, AnalysisError, fmt.Sprintf("metric %q: invalid min %q", metric.Name, metric.Min)
+			}
+			if value < min {
+				return false, AnalysisFailed, fmt.Sprintf("metric %q: contender value %g is below min %g", metric.Name, value, min)
+			}
+		}
+
+		if metric.Max != "" {
+			max, err := strconv.ParseFloat(metric.Max, 64)
+			if err != nil {
+				return false, AnalysisError, fmt.Sprintf("metric %q: invalid max %q", metric.Name, metric.Max)
+			}
+			if value > max {
+				return false, AnalysisFailed, fmt.Sprintf("metric %q: contender value %g is above max %g", metric.Name, value, max)
+			}
+		}
+
+		if metric.MaxIncumbentRatio != "" && incumbent != nil {
+			ratio, err := strconv.ParseFloat(metric.MaxIncumbentRatio, 64)
+			if err != nil {
+				return false, AnalysisError, fmt.Sprintf("metric %q: invalid maxIncumbentRatio %q", metric.Name, metric.MaxIncumbentRatio)
+			}
+			incumbentValue, err := queryMetric(analysis.URL, metric.Query, incumbent)
+			if err != nil {
+				return false, AnalysisError, fmt.Sprintf("metric %q: %s", metric.Name, err)
+			}
+			if value > incumbentValue*ratio {
+				return false, AnalysisFailed, fmt.Sprintf("metric %q: contender value %g is more than %g times the incumbent's %g", metric.Name, value, ratio, incumbentValue)
+			}
+		}
+	}
+
+	return true, "", ""
+}
+
+// queryMetric runs an instant query for a release and returns its single
+// resulting value.
+func queryMetric(baseURL, queryTemplate string, rel *shipper.Release) (float64, error) {
+	query, err := renderAnalysisQuery(queryTemplate, rel)
+	if err != nil {
+		return 0, err
+	}
+
+	endpoint := strings.TrimSuffix(baseURL, "/") + "/api/v1/query?" + url.Values{"query": {query}}.Encode()
+	resp, err := analysisClient.Get(endpoint)
+	if err != nil {
+		return 0, fmt.Errorf("query failed: %s", err)
+	}
+	defer resp.Body.Close()
+
+	var promResp prometheusResponse
+	if err := json.NewDecoder(resp.Body).Decode(&promResp); err != nil {
+		return 0, fmt.Errorf("could not decode response with status %d: %s", resp.StatusCode, err)
+	}
+
+	if promResp.Status != "success" {
+		return 0, fmt.Errorf("query failed: %s", promResp.Error)
+	}
+
+	var sample []interface{}
+	switch promResp.Data.ResultType {
+	case "scalar":
+		if err := json.Unmarshal(promResp.Data.Result, &sample); err != nil {
+			return 0, fmt.Errorf("malformed scalar result: %s", err)
+		}
+	case "vector":
+		var vector []struct {
+			Value []interface{} `json:"value"`
+		}
+		if err := json.Unmarshal(promResp.Data.Result, &vector); err != nil {
+			return 0, fmt.Errorf("malformed vector result: %s", err)
+		}
+		if len(vector) != 1 {
+			return 0, fmt.Errorf("expected query %q to return 1 sample, got %d", query, len(vector))
+		}
+		sample = vector[0].Value
+	default:
+		return 0, fmt.Errorf("unsupported result type %q", promResp.Data.ResultType)
+	}
+
+	if len(sample) != 2 {
+		return 0, fmt.Errorf("malformed sample %v", sample)
+	}
+	str, ok := sample[1].(string)
+	if !ok {
+		return 0, fmt.Errorf("malformed sample value %v", sample[1])
+	}
+
+	return strconv.ParseFloat(str, 64)
+}
+
+func renderAnalysisQuery(queryTemplate string, rel *shipper.Release) (string, error) {
+	tmpl, err := template.New("query").Parse(queryTemplate)
+	if err != nil {
+		return "", fmt.Errorf("invalid query template: %s", err)
+	}
+
+	appName, err := releaseutil.ApplicationNameForRelease(rel)
+	if err != nil {
+		return "", err
+	}
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, analysisQueryParams{
+		Release:     rel.Name,
+		Namespace:   rel.Namespace,
+		Application: appName,
+	})
+	if err != nil {
+		return "", fmt.Errorf("invalid query template: %s", err)
+	}
+
+	return buf.String(), nil
+}
+
+// stepHasAnalysis returns true if the step the release is targeting is gated
+// by an analysis.
+func stepHasAnalysis(rel *shipper.Release) bool {
+	strategy := rel.Spec.Environment.Strategy
+	targetStep := rel.Spec.TargetStep
+	if strategy == nil || int(targetStep) >= len(strategy.Steps) {
+		return false
+	}
+	return strategy.Steps[targetStep].Analysis != nil
+}
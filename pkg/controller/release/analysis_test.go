@@ -0,0 +1,244 @@
+package release
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+	"github.com/bookingcom/shipper/pkg/util/conditions"
+)
+
+// newFakePrometheus returns a server answering instant queries with the
+// error rate of whichever release the query mentions.
+func newFakePrometheus(errorRates map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("query")
+		for rel, value := range errorRates {
+			if strings.Contains(query, fmt.Sprintf("%q", rel)) {
+				fmt.Fprintf(w, `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1571000000,%q]}]}}`, value)
+				return
+			}
+		}
+		fmt.Fprint(w, `{"status":"success","data":{"resultType":"vector","result":[]}}`)
+	}))
+}
+
+func TestAnalysisGatesStep(t *testing.T) {
+	tests := []struct {
+		name           string
+		metric         shipper.RolloutStrategyStepMetric
+		abortOnFailure bool
+		errorRates     map[string]string
+		expectedReason string
+		expectAbort    bool
+	}{
+		{
+			name:       "below max",
+			metric:     shipper.RolloutStrategyStepMetric{Max: "0.05"},
+			errorRates: map[string]string{"test-contender": "0.01"},
+		},
+		{
+			name:           "above max",
+			metric:         shipper.RolloutStrategyStepMetric{Max: "0.05"},
+			errorRates:     map[string]string{"test-contender": "0.1"},
+			expectedReason: AnalysisFailed,
+		},
+		{
+			name:           "above max aborts",
+			metric:         shipper.RolloutStrategyStepMetric{Max: "0.05"},
+			abortOnFailure: true,
+			errorRates:     map[string]string{"test-contender": "0.1"},
+			expectedReason: AnalysisFailed,
+			expectAbort:    true,
+		},
+		{
+			name:   "within incumbent ratio",
+			metric: shipper.RolloutStrategyStepMetric{MaxIncumbentRatio: "1.5"},
+			errorRates: map[string]string{
+				"test-contender": "0.02",
+				"test-incumbent": "0.015",
+			},
+		},
+		{
+			name:   "worse than incumbent",
+			metric: shipper.RolloutStrategyStepMetric{MaxIncumbentRatio: "1.5"},
+			errorRates: map[string]string{
+				"test-contender": "0.04",
+				"test-incumbent": "0.015",
+			},
+			expectedReason: AnalysisFailed,
+		},
+		{
+			name:           "no data never aborts",
+			metric:         shipper.RolloutStrategyStepMetric{Max: "0.05"},
+			abortOnFailure: true,
+			errorRates:     map[string]string{},
+			expectedReason: AnalysisError,
+		},
+	}
+
+	for _, tt := range tests {
+		server := newFakePrometheus(tt.errorRates)
+
+		namespace := "test-namespace"
+		app := buildApplication(namespace, "test-app")
+		cluster := buildCluster("minikube")
+		f := newFixture(t, app.DeepCopy(), cluster.DeepCopy())
+
+		totalReplicaCount := int32(10)
+		incumbent := f.buildIncumbent(namespace, "test-incumbent", totalReplicaCount)
+		contender := f.buildContender(namespace, "test-contender", totalReplicaCount)
+		contender.capacityTarget.Spec.Clusters[0].Percent = 1
+		incumbent.capacityTarget.Spec.Clusters[0].Percent = 100
+
+		metric := tt.metric
+		metric.Name = "error rate"
+		metric.Query = `errors{release="{{.Release}}"}`
+
+		strategy := vanguard.DeepCopy()
+		strategy.Steps[0].Analysis = &shipper.RolloutStrategyStepAnalysis{
+			URL:            server.URL,
+			Metrics:        []shipper.RolloutStrategyStepMetric{metric},
+			AbortOnFailure: tt.abortOnFailure,
+		}
+		contender.release.Spec.Environment.Strategy = strategy
+
+		executor := NewStrategyExecutor(contender, incumbent, nil, nil, true)
+		complete, patches, _, err := executor.Execute()
+		server.Close()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", tt.name, err)
+		}
+
+		var statusPatch *ReleaseStrategyStatusPatch
+		var abortPatch *ReleaseAbortPatch
+		for _, patch := range patches {
+			switch p := patch.(type) {
+			case *ReleaseStrategyStatusPatch:
+				statusPatch = p
+			case *ReleaseAbortPatch:
+				abortPatch = p
+			}
+		}
+
+		if tt.expectedReason == "" {
+			if !complete {
+				t.Errorf("%s: expected step to be complete", tt.name)
+			}
+			if abortPatch != nil {
+				t.Errorf("%s: expected release not to be aborted", tt.name)
+			}
+			continue
+		}
+
+		if complete {
+			t.Errorf("%s: expected step to be incomplete", tt.name)
+			continue
+		}
+		if statusPatch == nil {
+			t.Errorf("%s: expected a release strategy status patch", tt.name)
+			continue
+		}
+
+		cond := conditions.NewStrategyConditions(statusPatch.NewStrategyStatus.Conditions...)
+		c, ok := cond.GetCondition(shipper.StrategyConditionContenderPassedAnalysis)
+		if !ok || !cond.IsFalse(0, shipper.StrategyConditionContenderPassedAnalysis) {
+			t.Errorf("%s: expected condition %q to be false", tt.name, shipper.StrategyConditionContenderPassedAnalysis)
+		} else if c.Reason != tt.expectedReason {
+			t.Errorf("%s: expected reason %q, got %q", tt.name, tt.expectedReason, c.Reason)
+		}
+
+		if statusPatch.NewStrategyStatus.State.WaitingForCommand == shipper.StrategyStateTrue {
+			t.Errorf("%s: expected release not to be waiting for command", tt.name)
+		}
+
+		if tt.expectAbort != (abortPatch != nil) {
+			t.Errorf("%s: expected abort to be %t, got %t", tt.name, tt.expectAbort, abortPatch != nil)
+		}
+	}
+}
+
+// TestAnalysisOnlyRunsUntilPassed verifies that a release that passed its
+// step's analysis, or achieved the step, doesn't get its metrics queried
+// again, and so can't be held back or aborted by them.
+func TestAnalysisOnlyRunsUntilPassed(t *testing.T) {
+	tests := []struct {
+		name    string
+		prepare func(*shipper.Release)
+	}{
+		{
+			name: "passed analysis",
+			prepare: func(rel *shipper.Release) {
+				rel.Status.Strategy = &shipper.ReleaseStrategyStatus{
+					Conditions: []shipper.ReleaseStrategyCondition{
+						{
+							Type:   shipper.StrategyConditionContenderPassedAnalysis,
+							Status: corev1.ConditionTrue,
+							Step:   0,
+						},
+					},
+				}
+			},
+		},
+		{
+			name: "achieved step",
+			prepare: func(rel *shipper.Release) {
+				rel.Status.AchievedStep = &shipper.AchievedStep{Step: 0}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		queries := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			queries++
+			fmt.Fprint(w, `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1571000000,"0.1"]}]}}`)
+		}))
+
+		namespace := "test-namespace"
+		app := buildApplication(namespace, "test-app")
+		cluster := buildCluster("minikube")
+		f := newFixture(t, app.DeepCopy(), cluster.DeepCopy())
+
+		totalReplicaCount := int32(10)
+		incumbent := f.buildIncumbent(namespace, "test-incumbent", totalReplicaCount)
+		contender := f.buildContender(namespace, "test-contender", totalReplicaCount)
+		contender.capacityTarget.Spec.Clusters[0].Percent = 1
+		incumbent.capacityTarget.Spec.Clusters[0].Percent = 100
+
+		strategy := vanguard.DeepCopy()
+		strategy.Steps[0].Analysis = &shipper.RolloutStrategyStepAnalysis{
+			URL: server.URL,
+			Metrics: []shipper.RolloutStrategyStepMetric{
+				{Name: "error rate", Query: `errors{release="{{.Release}}"}`, Max: "0.05"},
+			},
+			AbortOnFailure: true,
+		}
+		contender.release.Spec.Environment.Strategy = strategy
+		tt.prepare(contender.release)
+
+		executor := NewStrategyExecutor(contender, incumbent, nil, nil, true)
+		complete, patches, _, err := executor.Execute()
+		server.Close()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", tt.name, err)
+		}
+
+		if !complete {
+			t.Errorf("%s: expected step to be complete", tt.name)
+		}
+		if queries != 0 {
+			t.Errorf("%s: expected no metrics to be queried, got %d queries", tt.name, queries)
+		}
+		for _, patch := range patches {
+			if _, ok := patch.(*ReleaseAbortPatch); ok {
+				t.Errorf("%s: expected release not to be aborted", tt.name)
+			}
+		}
+	}
+}
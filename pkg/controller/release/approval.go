@@ -0,0 +1,77 @@
+package release
+
+import (
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/runtime"
+
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+	"github.com/bookingcom/shipper/pkg/util/conditions"
+)
+
+const (
+	ApprovalPending = "ApprovalPending"
+)
+
+// genApprovalEnforcer keeps a release from working on a step that requires
+// approval until it gets one. Shipper only moves releases on to such steps
+// once they are approved, so this only holds back releases that were moved
+// on by hand.
+func genApprovalEnforcer(curr *releaseInfo, approved bool) PipelineStep {
+	return func(e *StrategyExecutor, cond conditions.StrategyConditionsMap) (PipelineContinuation, []StrategyPatch, []ReleaseStrategyStateTransition) {
+		targetStep := curr.release.Spec.TargetStep
+
+		if !approved {
+			msg := fmt.Sprintf("step [%d] requires a StepApproval for release %q", targetStep, curr.release.Name)
+			e.info(msg)
+
+			cond.SetFalse(
+				shipper.StrategyConditionContenderApproved,
+				conditions.StrategyConditionsUpdate{
+					Reason:             ApprovalPending,
+					Message:            msg,
+					Step:               targetStep,
+					LastTransitionTime: time.Now(),
+				},
+			)
+
+			patches := make([]StrategyPatch, 0, 1)
+			relPatch := e.StrategyStatusPatch(cond)
+			if relPatch.Alters(e.curr.release) {
+				patches = append(patches, relPatch)
+			}
+
+			return PipelineBreak, patches, nil
+		}
+
+		cond.SetTrue(
+			shipper.StrategyConditionContenderApproved,
+			conditions.StrategyConditionsUpdate{
+				Step:               targetStep,
+				LastTransitionTime: time.Now(),
+			},
+		)
+
+		return PipelineContinue, nil, nil
+	}
+}
+
+func (c *Controller) enqueueReleaseFromStepApproval(obj interface{}) {
+	approval, ok := obj.(*shipper.StepApproval)
+	if !ok {
+		runtime.HandleError(fmt.Errorf("not a shipper.StepApproval: %#v", obj))
+		return
+	}
+
+	rel, err := c.releaseLister.Releases(approval.Namespace).Get(approval.Spec.Release)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			runtime.HandleError(err)
+		}
+		return
+	}
+
+	c.enqueueRelease(rel)
+}
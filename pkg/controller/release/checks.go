@@ -5,6 +5,7 @@ import (
 	"sort"
 
 	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+	"github.com/bookingcom/shipper/pkg/util/replicas"
 	targetutil "github.com/bookingcom/shipper/pkg/util/target"
 )
 
@@ -12,9 +13,14 @@ func checkInstallation(it *shipper.InstallationTarget) (bool, string) {
 	return targetutil.IsReady(it.Status.Conditions)
 }
 
+// checkCapacity compares a capacity target's spec against the capacity a
+// step asks for in each cluster: clusterReplicas holds absolute numbers of
+// replicas for the clusters at steps asking for them, and clusterCapacity
+// percentages for the rest.
 func checkCapacity(
 	ct *shipper.CapacityTarget,
-	stepCapacity int32,
+	clusterCapacity map[string]int32,
+	clusterReplicas map[string]int32,
 ) (
 	bool,
 	*shipper.CapacityTargetSpec,
@@ -24,19 +30,38 @@ func checkCapacity(
 	newSpec := &shipper.CapacityTargetSpec{}
 	reason := ""
 
+	// The new spec carries every cluster, not only the ones that need
+	// adjusting: a merge patch replaces the cluster list as a whole, and
+	// clusters can sit at different steps.
 	clustersNotReadyMap := make(map[string]struct{})
 	for _, spec := range ct.Spec.Clusters {
-		if spec.Percent != stepCapacity {
-			t := shipper.ClusterCapacityTarget{
-				Name:              spec.Name,
-				Percent:           stepCapacity,
-				TotalReplicaCount: spec.TotalReplicaCount,
+		t := shipper.ClusterCapacityTarget{
+			Name:              spec.Name,
+			Percent:           spec.Percent,
+			Replicas:          spec.Replicas,
+			TotalReplicaCount: spec.TotalReplicaCount,
+			Workloads:         spec.Workloads,
+		}
+
+		if stepReplicas, ok := clusterReplicas[spec.Name]; ok {
+			// Percent is kept close to the number of replicas
+			// asked for, for anything that only looks at it.
+			if !capacityMatchesStep(spec, 0, &stepReplicas) {
+				t.Replicas = &stepReplicas
+				t.Percent = replicas.CalculateReplicasPercentage(spec.TotalReplicaCount, stepReplicas)
+
+				clustersNotReadyMap[spec.Name] = struct{}{}
+				canProceed = false
 			}
-			newSpec.Clusters = append(newSpec.Clusters, t)
+		} else if stepCapacity, ok := clusterCapacity[spec.Name]; ok && !capacityMatchesStep(spec, stepCapacity, nil) {
+			t.Percent = stepCapacity
+			t.Replicas = nil
 
 			clustersNotReadyMap[spec.Name] = struct{}{}
 			canProceed = false
 		}
+
+		newSpec.Clusters = append(newSpec.Clusters, t)
 	}
 
 	if canProceed {
@@ -69,7 +94,7 @@ func checkCapacity(
 		reason = fmt.Sprintf("%v", clustersNotReady)
 	}
 
-	if len(newSpec.Clusters) > 0 {
+	if len(clustersNotReadyMap) > 0 {
 		return canProceed, newSpec, reason
 	} else {
 		return canProceed, nil, reason
@@ -78,7 +103,7 @@ func checkCapacity(
 
 func checkTraffic(
 	tt *shipper.TrafficTarget,
-	stepTrafficWeight uint32,
+	clusterTrafficWeight map[string]uint32,
 ) (
 	bool,
 	*shipper.TrafficTargetSpec,
@@ -90,16 +115,19 @@ func checkTraffic(
 
 	clustersNotReadyMap := make(map[string]struct{})
 	for _, spec := range tt.Spec.Clusters {
-		if spec.Weight != stepTrafficWeight {
-			t := shipper.ClusterTrafficTarget{
-				Name:   spec.Name,
-				Weight: stepTrafficWeight,
-			}
-			newSpec.Clusters = append(newSpec.Clusters, t)
+		t := shipper.ClusterTrafficTarget{
+			Name:   spec.Name,
+			Weight: spec.Weight,
+		}
+
+		if stepTrafficWeight, ok := clusterTrafficWeight[spec.Name]; ok && spec.Weight != stepTrafficWeight {
+			t.Weight = stepTrafficWeight
 
 			clustersNotReadyMap[spec.Name] = struct{}{}
 			canProceed = false
 		}
+
+		newSpec.Clusters = append(newSpec.Clusters, t)
 	}
 
 	if canProceed {
@@ -132,7 +160,7 @@ func checkTraffic(
 		reason = fmt.Sprintf("%v", clustersNotReady)
 	}
 
-	if len(newSpec.Clusters) > 0 {
+	if len(clustersNotReadyMap) > 0 {
 		return canProceed, newSpec, reason
 	} else {
 		return canProceed, nil, reason
@@ -0,0 +1,100 @@
+package release
+
+import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+	shippererrors "github.com/bookingcom/shipper/pkg/errors"
+	releaseutil "github.com/bookingcom/shipper/pkg/util/release"
+)
+
+// recordTargetStepChange adds a TargetStepChanged entry to the release's
+// history if its target step is not the one the history last saw it move
+// to. Releases start out targeting their first step, so that one is only
+// recorded when we know who created the release.
+func recordTargetStepChange(rel *shipper.Release, user, message string, now time.Time) {
+	targetStep := rel.Spec.TargetStep
+
+	last := releaseutil.LastHistoryEntry(&rel.Status, shipper.ReleaseHistoryTargetStepChanged)
+	if last == nil && targetStep == 0 && user == "" {
+		return
+	} else if last != nil && last.Step == targetStep {
+		return
+	}
+
+	releaseutil.AppendHistory(&rel.Status, shipper.ReleaseHistoryEntry{
+		Type:     shipper.ReleaseHistoryTargetStepChanged,
+		Time:     metav1.NewTime(now),
+		Step:     targetStep,
+		StepName: releaseutil.StepName(rel, targetStep),
+		User:     user,
+		Message:  message,
+	})
+}
+
+// recordStepAchieved adds a StepAchieved entry to the release's history,
+// along with how long each part of the step took since it was targeted,
+// going by when the strategy conditions for it last turned True.
+func recordStepAchieved(rel *shipper.Release, strategyConditions []shipper.ReleaseStrategyCondition, now time.Time) {
+	targetStep := rel.Spec.TargetStep
+
+	targetedAt := rel.CreationTimestamp.Time
+	if last := releaseutil.LastHistoryEntry(&rel.Status, shipper.ReleaseHistoryTargetStepChanged); last != nil && last.Step == targetStep {
+		targetedAt = last.Time.Time
+	}
+
+	durationOf := func(condType shipper.StrategyConditionType) *metav1.Duration {
+		for _, cond := range strategyConditions {
+			if cond.Type != condType || cond.Step != targetStep || cond.Status != corev1.ConditionTrue {
+				continue
+			}
+
+			d := cond.LastTransitionTime.Sub(targetedAt)
+			if d < 0 {
+				d = 0
+			}
+			return &metav1.Duration{Duration: d}
+		}
+		return nil
+	}
+
+	releaseutil.AppendHistory(&rel.Status, shipper.ReleaseHistoryEntry{
+		Type:                 shipper.ReleaseHistoryStepAchieved,
+		Time:                 metav1.NewTime(now),
+		Step:                 targetStep,
+		StepName:             releaseutil.StepName(rel, targetStep),
+		InstallationDuration: durationOf(shipper.StrategyConditionContenderAchievedInstallation),
+		CapacityDuration:     durationOf(shipper.StrategyConditionContenderAchievedCapacity),
+		TrafficDuration:      durationOf(shipper.StrategyConditionContenderAchievedTraffic),
+	})
+}
+
+// recordAbort adds an Aborted entry to the history of the release before
+// the aborted one, as the aborted release is deleted right after.
+func (c *Controller) recordAbort(rel *shipper.Release, reason string, now time.Time) error {
+	releases, err := c.applicationReleases(rel)
+	if err != nil {
+		return err
+	}
+
+	pred, _, err := releaseutil.GetSiblingReleases(rel, releases)
+	if err != nil || pred == nil {
+		return err
+	}
+
+	pred = pred.DeepCopy()
+	if !releaseutil.AppendAbortedHistory(&pred.Status, rel, reason, now) {
+		return nil
+	}
+
+	_, err = c.clientset.ShipperV1alpha1().Releases(pred.Namespace).Update(pred)
+	if err != nil {
+		return shippererrors.NewKubeclientUpdateError(pred, err).
+			WithShipperKind("Release")
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package release
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+)
+
+func TestRecordTargetStepChange(t *testing.T) {
+	now := time.Now()
+	rel := &shipper.Release{
+		Spec: shipper.ReleaseSpec{
+			Environment: shipper.ReleaseEnvironment{Strategy: vanguard.DeepCopy()},
+		},
+	}
+
+	// Releases start out targeting their first step, so there's nothing
+	// to record unless we know who created them.
+	recordTargetStepChange(rel, "", "", now)
+	if len(rel.Status.History) != 0 {
+		t.Fatalf("expected no history for a new release, got %v", rel.Status.History)
+	}
+
+	rel.Spec.TargetStep = 1
+	recordTargetStepChange(rel, "alice", "", now)
+	recordTargetStepChange(rel, "alice", "", now.Add(time.Minute))
+
+	if len(rel.Status.History) != 1 {
+		t.Fatalf("expected a single history entry, got %v", rel.Status.History)
+	}
+
+	entry := rel.Status.History[0]
+	if entry.Type != shipper.ReleaseHistoryTargetStepChanged || entry.Step != 1 ||
+		entry.StepName != "50/50" || entry.User != "alice" || !entry.Time.Time.Equal(now) {
+		t.Errorf("unexpected history entry %+v", entry)
+	}
+}
+
+func TestRecordStepAchieved(t *testing.T) {
+	targetedAt := time.Now().Add(-10 * time.Minute)
+	rel := &shipper.Release{
+		Spec: shipper.ReleaseSpec{
+			TargetStep:  1,
+			Environment: shipper.ReleaseEnvironment{Strategy: vanguard.DeepCopy()},
+		},
+	}
+	recordTargetStepChange(rel, "alice", "", targetedAt)
+
+	strategyConditions := []shipper.ReleaseStrategyCondition{
+		{
+			Type:               shipper.StrategyConditionContenderAchievedInstallation,
+			Status:             corev1.ConditionTrue,
+			Step:               1,
+			LastTransitionTime: metav1.NewTime(targetedAt.Add(-time.Hour)),
+		},
+		{
+			Type:               shipper.StrategyConditionContenderAchievedCapacity,
+			Status:             corev1.ConditionTrue,
+			Step:               1,
+			LastTransitionTime: metav1.NewTime(targetedAt.Add(2 * time.Minute)),
+		},
+		{
+			Type:               shipper.StrategyConditionContenderAchievedTraffic,
+			Status:             corev1.ConditionFalse,
+			Step:               1,
+			LastTransitionTime: metav1.NewTime(targetedAt.Add(time.Minute)),
+		},
+	}
+
+	recordStepAchieved(rel, strategyConditions, time.Now())
+
+	entry := rel.Status.History[len(rel.Status.History)-1]
+	if entry.Type != shipper.ReleaseHistoryStepAchieved || entry.Step != 1 {
+		t.Fatalf("unexpected history entry %+v", entry)
+	}
+
+	// Installation was achieved before the step was targeted, so it
+	// took no time at all for this step.
+	if entry.InstallationDuration == nil || entry.InstallationDuration.Duration != 0 {
+		t.Errorf("expected installation to take no time, got %v", entry.InstallationDuration)
+	}
+
+	if entry.CapacityDuration == nil || entry.CapacityDuration.Duration != 2*time.Minute {
+		t.Errorf("expected capacity to take 2m, got %v", entry.CapacityDuration)
+	}
+
+	if entry.TrafficDuration != nil {
+		t.Errorf("expected no traffic duration for an unachieved condition, got %v", entry.TrafficDuration)
+	}
+}
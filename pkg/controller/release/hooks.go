@@ -0,0 +1,207 @@
+package release
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+	"github.com/bookingcom/shipper/pkg/util/conditions"
+	releaseutil "github.com/bookingcom/shipper/pkg/util/release"
+	strategyutil "github.com/bookingcom/shipper/pkg/util/strategy"
+)
+
+const (
+	HookFailed = "HookFailed"
+	HookError  = "HookError"
+
+	PreStepHook  = "preStep"
+	PostStepHook = "postStep"
+
+	// maxHookMessageLength caps how much of a hook's response ends up in
+	// a strategy condition.
+	maxHookMessageLength = 512
+)
+
+var hookClient = &http.Client{Timeout: 10 * time.Second}
+
+// hookPayload is what step hooks get POSTed.
+type hookPayload struct {
+	Hook        string   `json:"hook"`
+	Release     string   `json:"release"`
+	Namespace   string   `json:"namespace"`
+	Application string   `json:"application"`
+	Step        int32    `json:"step"`
+	StepName    string   `json:"stepName"`
+	Clusters    []string `json:"clusters"`
+}
+
+// hookResponse is the optional body of a hook's answer.
+type hookResponse struct {
+	Proceed *bool  `json:"proceed"`
+	Message string `json:"message"`
+}
+
+// genStepHookEnforcer calls one of the target step's hooks, unless it has
+// already let the release through for this step.
+func genStepHookEnforcer(curr *releaseInfo, hook string) PipelineStep {
+	condType := shipper.StrategyConditionContenderPassedPreStepHook
+	if hook == PostStepHook {
+		condType = shipper.StrategyConditionContenderPassedPostStepHook
+	}
+
+	return func(e *StrategyExecutor, cond conditions.StrategyConditionsMap) (PipelineContinuation, []StrategyPatch, []ReleaseStrategyStateTransition) {
+		strategy := curr.release.Spec.Environment.Strategy
+		targetStep := curr.release.Spec.TargetStep
+		step := strategy.Steps[targetStep]
+
+		if cond.IsTrue(targetStep, condType) {
+			return PipelineContinue, nil, nil
+		}
+
+		url := stepHookURL(step, hook)
+		payload := hookPayload{
+			Hook:      hook,
+			Release:   curr.release.Name,
+			Namespace: curr.release.Namespace,
+			Step:      targetStep,
+			StepName:  step.Name,
+			Clusters:  e.stepClusters(curr, step),
+		}
+		payload.Application, _ = releaseutil.ApplicationNameForRelease(curr.release)
+
+		passed, reason, msg := callStepHook(url, payload)
+		if !passed {
+			e.info("%s hook for step %d hasn't let the release through yet: %s", hook, targetStep, msg)
+
+			cond.SetFalse(
+				condType,
+				conditions.StrategyConditionsUpdate{
+					Reason:             reason,
+					Message:            msg,
+					Step:               targetStep,
+					LastTransitionTime: time.Now(),
+				},
+			)
+
+			patches := make([]StrategyPatch, 0, 1)
+			relPatch := e.StrategyStatusPatch(cond)
+			if relPatch.Alters(e.curr.release) {
+				patches = append(patches, relPatch)
+			}
+
+			return PipelineBreak, patches, nil
+		}
+
+		e.info("%s hook for step %d let the release through", hook, targetStep)
+
+		cond.SetTrue(
+			condType,
+			conditions.StrategyConditionsUpdate{
+				Step:               targetStep,
+				LastTransitionTime: time.Now(),
+			},
+		)
+
+		return PipelineContinue, nil, nil
+	}
+}
+
+// callStepHook POSTs the payload to a hook. It returns whether the hook let
+// the release through and, if it did not, a reason and a message explaining
+// why. The reason is HookFailed if the hook answered, and HookError if it
+// could not be reached at all.
+func callStepHook(url string, payload hookPayload) (bool, string, string) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return false, HookError, fmt.Sprintf("failed to encode %s hook payload: %s", payload.Hook, err)
+	}
+
+	resp, err := hookClient.Post(url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return false, HookError, fmt.Sprintf("failed to call %s hook: %s", payload.Hook, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return false, HookError, fmt.Sprintf("failed to read %s hook response: %s", payload.Hook, err)
+	}
+
+	var hookResp hookResponse
+	message := strings.TrimSpace(string(respBody))
+	if json.Unmarshal(respBody, &hookResp) == nil && hookResp.Message != "" {
+		message = hookResp.Message
+	}
+	if len(message) > maxHookMessageLength {
+		message = message[:maxHookMessageLength]
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return false, HookFailed, fmt.Sprintf("%s hook answered %s: %s", payload.Hook, resp.Status, message)
+	}
+
+	if hookResp.Proceed != nil && !*hookResp.Proceed {
+		return false, HookFailed, fmt.Sprintf("%s hook did not let the release proceed: %s", payload.Hook, message)
+	}
+
+	return true, "", ""
+}
+
+// stepClusters returns the sorted names of the release's clusters the step
+// applies to.
+func (e *StrategyExecutor) stepClusters(relinfo *releaseInfo, step shipper.RolloutStrategyStep) []string {
+	clusters := make([]string, 0, len(relinfo.capacityTarget.Spec.Clusters))
+	for _, spec := range relinfo.capacityTarget.Spec.Clusters {
+		clusters = append(clusters, spec.Name)
+	}
+	sort.Strings(clusters)
+
+	selected := make([]string, 0, len(clusters))
+	for i, cluster := range clusters {
+		if strategyutil.StepSelectsCluster(step, cluster, e.clusterRegions[cluster], i) {
+			selected = append(selected, cluster)
+		}
+	}
+
+	return selected
+}
+
+func stepHookURL(step shipper.RolloutStrategyStep, hook string) string {
+	if step.Hooks == nil {
+		return ""
+	}
+
+	if hook == PreStepHook {
+		return step.Hooks.PreStep
+	}
+
+	return step.Hooks.PostStep
+}
+
+// stepHasHook returns true if the step the release is targeting has the
+// given hook.
+func stepHasHook(rel *shipper.Release, hook string) bool {
+	strategy := rel.Spec.Environment.Strategy
+	targetStep := rel.Spec.TargetStep
+	if strategy == nil || int(targetStep) >= len(strategy.Steps) {
+		return false
+	}
+	return stepHookURL(strategy.Steps[targetStep], hook) != ""
+}
+
+// stepHookFailed tells whether one of the hooks of the step the release is
+// targeting is holding it back. The strategy status is taken from the
+// patches if they change it, and from the release otherwise.
+func stepHookFailed(rel *shipper.Release, patches []StrategyPatch) bool {
+	cond := conditions.NewStrategyConditions(releaseStrategyConditions(rel, patches)...)
+	step := rel.Spec.TargetStep
+
+	return cond.IsFalse(step, shipper.StrategyConditionContenderPassedPreStepHook) ||
+		cond.IsFalse(step, shipper.StrategyConditionContenderPassedPostStepHook)
+}
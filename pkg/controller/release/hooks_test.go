@@ -0,0 +1,140 @@
+package release
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+	"github.com/bookingcom/shipper/pkg/util/conditions"
+)
+
+func newFakeHook(status int, body string, calls *[]hookPayload) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload hookPayload
+		json.NewDecoder(r.Body).Decode(&payload)
+		*calls = append(*calls, payload)
+
+		w.WriteHeader(status)
+		fmt.Fprint(w, body)
+	}))
+}
+
+func TestStepHooksGateStep(t *testing.T) {
+	tests := []struct {
+		name            string
+		hook            string
+		status          int
+		body            string
+		expectedMessage string
+	}{
+		{
+			name:   "pre-step hook lets the release through",
+			hook:   PreStepHook,
+			status: http.StatusOK,
+		},
+		{
+			name:            "pre-step hook fails",
+			hook:            PreStepHook,
+			status:          http.StatusServiceUnavailable,
+			body:            "cache not warm yet",
+			expectedMessage: "cache not warm yet",
+		},
+		{
+			name:   "post-step hook lets the release through",
+			hook:   PostStepHook,
+			status: http.StatusOK,
+			body:   `{"proceed": true}`,
+		},
+		{
+			name:            "post-step hook holds the release back",
+			hook:            PostStepHook,
+			status:          http.StatusOK,
+			body:            `{"proceed": false, "message": "smoke tests failed"}`,
+			expectedMessage: "smoke tests failed",
+		},
+	}
+
+	for _, tt := range tests {
+		var calls []hookPayload
+		server := newFakeHook(tt.status, tt.body, &calls)
+
+		namespace := "test-namespace"
+		app := buildApplication(namespace, "test-app")
+		cluster := buildCluster("minikube")
+		f := newFixture(t, app.DeepCopy(), cluster.DeepCopy())
+
+		totalReplicaCount := int32(10)
+		incumbent := f.buildIncumbent(namespace, "test-incumbent", totalReplicaCount)
+		contender := f.buildContender(namespace, "test-contender", totalReplicaCount)
+		contender.capacityTarget.Spec.Clusters[0].Percent = 1
+		incumbent.capacityTarget.Spec.Clusters[0].Percent = 100
+
+		strategy := vanguard.DeepCopy()
+		hooks := &shipper.RolloutStrategyStepHooks{}
+		condType := shipper.StrategyConditionContenderPassedPreStepHook
+		if tt.hook == PreStepHook {
+			hooks.PreStep = server.URL
+		} else {
+			hooks.PostStep = server.URL
+			condType = shipper.StrategyConditionContenderPassedPostStepHook
+		}
+		strategy.Steps[0].Hooks = hooks
+		contender.release.Spec.Environment.Strategy = strategy
+
+		executor := NewStrategyExecutor(contender, incumbent, nil, nil, true)
+		complete, patches, _, err := executor.Execute()
+		server.Close()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", tt.name, err)
+		}
+
+		if len(calls) != 1 {
+			t.Fatalf("%s: expected hook to be called once, got %d calls", tt.name, len(calls))
+		}
+		payload := calls[0]
+		if payload.Hook != tt.hook || payload.Release != "test-contender" || payload.Application != "test-app" {
+			t.Errorf("%s: unexpected payload %+v", tt.name, payload)
+		}
+		if len(payload.Clusters) != 1 || payload.Clusters[0] != "minikube" {
+			t.Errorf("%s: expected payload to list cluster minikube, got %v", tt.name, payload.Clusters)
+		}
+
+		passed := tt.expectedMessage == ""
+		if complete != passed {
+			t.Errorf("%s: expected step completion to be %t, got %t", tt.name, passed, complete)
+		}
+		if hookFailed := stepHookFailed(contender.release, patches); hookFailed == passed {
+			t.Errorf("%s: expected hook failure to be %t, got %t", tt.name, !passed, hookFailed)
+		}
+		if passed {
+			continue
+		}
+
+		var statusPatch *ReleaseStrategyStatusPatch
+		for _, patch := range patches {
+			if p, ok := patch.(*ReleaseStrategyStatusPatch); ok {
+				statusPatch = p
+			}
+		}
+		if statusPatch == nil {
+			t.Fatalf("%s: expected a release strategy status patch", tt.name)
+		}
+
+		cond := conditions.NewStrategyConditions(statusPatch.NewStrategyStatus.Conditions...)
+		c, ok := cond.GetCondition(condType)
+		if !ok || !cond.IsFalse(0, condType) {
+			t.Fatalf("%s: expected condition %s to be False", tt.name, condType)
+		}
+		if c.Reason != HookFailed || !strings.Contains(c.Message, tt.expectedMessage) {
+			t.Errorf("%s: expected %s condition with message containing %q, got %s: %q",
+				tt.name, HookFailed, tt.expectedMessage, c.Reason, c.Message)
+		}
+		if statusPatch.NewStrategyStatus.State.WaitingForCommand != shipper.StrategyStateFalse {
+			t.Errorf("%s: expected release not to be waiting for command", tt.name)
+		}
+	}
+}
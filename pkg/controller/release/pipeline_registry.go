@@ -0,0 +1,174 @@
+package release
+
+import (
+	"fmt"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog"
+
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+	"github.com/bookingcom/shipper/pkg/controller"
+	"github.com/bookingcom/shipper/pkg/util/conditions"
+)
+
+// Names of the built-in pipeline steps that plugins can be ordered
+// against. They all refer to the steps run for the contender.
+const (
+	PipelineStepInstallation = "installation"
+	PipelineStepCapacity     = "capacity"
+	PipelineStepTraffic      = "traffic"
+	PipelineStepAnalysis     = "analysis"
+)
+
+// PipelineStepPlugin is a step added to the strategy pipeline from outside of
+// Shipper, like a check against a change management system. Plugins are only
+// run for the head release, and always before its strategy state is worked
+// out.
+type PipelineStepPlugin interface {
+	// Name identifies the plugin in logs and errors. It has to be
+	// unique.
+	Name() string
+
+	// After is the name of the built-in step the plugin runs right
+	// after, or an empty string to run before all of them.
+	After() string
+
+	// ConditionTypes are the strategy conditions the plugin sets. While
+	// any of them is False for the target step, the release is not
+	// considered to be waiting for command.
+	ConditionTypes() []shipper.StrategyConditionType
+
+	// PatchKinds are the kinds of objects the plugin's patches apply
+	// to. Patches for any other kind are dropped.
+	PatchKinds() []schema.GroupVersionKind
+
+	// Step works just like the built-in enforcers: returning
+	// PipelineBreak means the target step has not been achieved yet.
+	Step(*StrategyExecutor, conditions.StrategyConditionsMap) (PipelineContinuation, []StrategyPatch, []ReleaseStrategyStateTransition)
+}
+
+var pipelineStepRegistry struct {
+	sync.RWMutex
+	plugins []PipelineStepPlugin
+}
+
+// RegisterPipelineStep adds a plugin to the pipeline of every release
+// controller in this process. It is meant to be called from init functions,
+// before the controller starts.
+func RegisterPipelineStep(plugin PipelineStepPlugin) error {
+	pipelineStepRegistry.Lock()
+	defer pipelineStepRegistry.Unlock()
+
+	if err := validatePipelineStep(plugin, pipelineStepRegistry.plugins); err != nil {
+		return fmt.Errorf("cannot register pipeline step %q: %s", plugin.Name(), err)
+	}
+
+	pipelineStepRegistry.plugins = append(pipelineStepRegistry.plugins, plugin)
+
+	return nil
+}
+
+func registeredPipelineSteps() []PipelineStepPlugin {
+	pipelineStepRegistry.RLock()
+	defer pipelineStepRegistry.RUnlock()
+
+	plugins := make([]PipelineStepPlugin, len(pipelineStepRegistry.plugins))
+	copy(plugins, pipelineStepRegistry.plugins)
+
+	return plugins
+}
+
+func validatePipelineStep(plugin PipelineStepPlugin, registered []PipelineStepPlugin) error {
+	if plugin.Name() == "" {
+		return fmt.Errorf("name must not be empty")
+	}
+
+	switch plugin.After() {
+	case "", PipelineStepInstallation, PipelineStepCapacity, PipelineStepTraffic, PipelineStepAnalysis:
+	default:
+		return fmt.Errorf("unknown built-in step %q", plugin.After())
+	}
+
+	claimed := map[shipper.StrategyConditionType]string{
+		shipper.StrategyConditionContenderAchievedInstallation: "shipper",
+		shipper.StrategyConditionContenderAchievedCapacity:     "shipper",
+		shipper.StrategyConditionContenderAchievedTraffic:      "shipper",
+		shipper.StrategyConditionIncumbentAchievedCapacity:     "shipper",
+		shipper.StrategyConditionIncumbentAchievedTraffic:      "shipper",
+		shipper.StrategyConditionContenderPassedAnalysis:       "shipper",
+		shipper.StrategyConditionContenderPassedPreStepHook:    "shipper",
+		shipper.StrategyConditionContenderPassedPostStepHook:   "shipper",
+		shipper.StrategyConditionContenderApproved:             "shipper",
+	}
+	for _, other := range registered {
+		if other.Name() == plugin.Name() {
+			return fmt.Errorf("a pipeline step with this name is already registered")
+		}
+		for _, condType := range other.ConditionTypes() {
+			claimed[condType] = other.Name()
+		}
+	}
+
+	for _, condType := range plugin.ConditionTypes() {
+		if owner, ok := claimed[condType]; ok {
+			return fmt.Errorf("condition type %q is already set by %s", condType, owner)
+		}
+	}
+
+	for _, gvk := range plugin.PatchKinds() {
+		if !isPatchableKind(gvk) {
+			return fmt.Errorf("patches for %s are not supported", gvk.Kind)
+		}
+	}
+
+	return nil
+}
+
+// isPatchableKind tells whether applyPatch knows how to apply patches for
+// the given kind.
+func isPatchableKind(gvk schema.GroupVersionKind) bool {
+	if gvk.GroupVersion() != shipper.SchemeGroupVersion {
+		return false
+	}
+
+	switch gvk.Kind {
+	case "Release", "InstallationTarget", "CapacityTarget", "TrafficTarget":
+		return true
+	default:
+		return false
+	}
+}
+
+// genPluginEnforcer wraps a plugin so that it can only produce the patches
+// it declared.
+func genPluginEnforcer(plugin PipelineStepPlugin) PipelineStep {
+	return func(e *StrategyExecutor, cond conditions.StrategyConditionsMap) (PipelineContinuation, []StrategyPatch, []ReleaseStrategyStateTransition) {
+		cont, patches, trans := plugin.Step(e, cond)
+
+		allowed := make([]StrategyPatch, 0, len(patches))
+		for _, patch := range patches {
+			name, gvk, _ := patch.PatchSpec()
+			if !pluginDeclaresKind(plugin, gvk) {
+				klog.Errorf(
+					"Release %q: pipeline step %q produced an undeclared patch for %s %q, ignoring it",
+					controller.MetaKey(e.curr.release), plugin.Name(), gvk.Kind, name,
+				)
+				continue
+			}
+			allowed = append(allowed, patch)
+		}
+
+		return cont, allowed, trans
+	}
+}
+
+func pluginDeclaresKind(plugin PipelineStepPlugin, gvk schema.GroupVersionKind) bool {
+	for _, declared := range plugin.PatchKinds() {
+		if declared == gvk {
+			return true
+		}
+	}
+
+	return false
+}
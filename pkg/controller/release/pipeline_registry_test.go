@@ -0,0 +1,202 @@
+package release
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+	"github.com/bookingcom/shipper/pkg/util/conditions"
+)
+
+const ticketApproved shipper.StrategyConditionType = "ContenderTicketApproved"
+
+type fakePipelineStep struct {
+	name       string
+	after      string
+	condTypes  []shipper.StrategyConditionType
+	patchKinds []schema.GroupVersionKind
+	approved   bool
+	extraPatch StrategyPatch
+}
+
+func (p *fakePipelineStep) Name() string                                    { return p.name }
+func (p *fakePipelineStep) After() string                                   { return p.after }
+func (p *fakePipelineStep) ConditionTypes() []shipper.StrategyConditionType { return p.condTypes }
+func (p *fakePipelineStep) PatchKinds() []schema.GroupVersionKind           { return p.patchKinds }
+
+func (p *fakePipelineStep) Step(e *StrategyExecutor, cond conditions.StrategyConditionsMap) (PipelineContinuation, []StrategyPatch, []ReleaseStrategyStateTransition) {
+	update := conditions.StrategyConditionsUpdate{
+		Step:               e.Release().Spec.TargetStep,
+		LastTransitionTime: time.Now(),
+	}
+
+	if p.approved {
+		cond.SetTrue(ticketApproved, update)
+		return PipelineContinue, nil, nil
+	}
+
+	update.Reason = "TicketPending"
+	cond.SetFalse(ticketApproved, update)
+
+	patches := []StrategyPatch{e.StrategyStatusPatch(cond)}
+	if p.extraPatch != nil {
+		patches = append(patches, p.extraPatch)
+	}
+
+	return PipelineBreak, patches, nil
+}
+
+func newFakePipelineStep() *fakePipelineStep {
+	return &fakePipelineStep{
+		name:       "change-management",
+		after:      PipelineStepInstallation,
+		condTypes:  []shipper.StrategyConditionType{ticketApproved},
+		patchKinds: []schema.GroupVersionKind{shipper.SchemeGroupVersion.WithKind("Release")},
+	}
+}
+
+func TestRegisterPipelineStep(t *testing.T) {
+	defer func() { pipelineStepRegistry.plugins = nil }()
+
+	if err := RegisterPipelineStep(newFakePipelineStep()); err != nil {
+		t.Fatalf("unexpected error registering a pipeline step: %s", err)
+	}
+
+	tests := []struct {
+		name          string
+		mutate        func(*fakePipelineStep)
+		expectedError string
+	}{
+		{
+			name:          "duplicate name",
+			mutate:        func(p *fakePipelineStep) { p.condTypes = nil },
+			expectedError: "already registered",
+		},
+		{
+			name: "unknown built-in step",
+			mutate: func(p *fakePipelineStep) {
+				p.name = "other"
+				p.condTypes = nil
+				p.after = "deployment"
+			},
+			expectedError: `unknown built-in step "deployment"`,
+		},
+		{
+			name:          "condition type claimed by another plugin",
+			mutate:        func(p *fakePipelineStep) { p.name = "other" },
+			expectedError: `is already set by change-management`,
+		},
+		{
+			name: "built-in condition type",
+			mutate: func(p *fakePipelineStep) {
+				p.name = "other"
+				p.condTypes = []shipper.StrategyConditionType{shipper.StrategyConditionContenderAchievedCapacity}
+			},
+			expectedError: `is already set by shipper`,
+		},
+		{
+			name: "built-in hook condition type",
+			mutate: func(p *fakePipelineStep) {
+				p.name = "other"
+				p.condTypes = []shipper.StrategyConditionType{shipper.StrategyConditionContenderPassedPostStepHook}
+			},
+			expectedError: `is already set by shipper`,
+		},
+		{
+			name: "built-in approval condition type",
+			mutate: func(p *fakePipelineStep) {
+				p.name = "other"
+				p.condTypes = []shipper.StrategyConditionType{shipper.StrategyConditionContenderApproved}
+			},
+			expectedError: `is already set by shipper`,
+		},
+		{
+			name: "unsupported patch kind",
+			mutate: func(p *fakePipelineStep) {
+				p.name = "other"
+				p.condTypes = nil
+				p.patchKinds = []schema.GroupVersionKind{shipper.SchemeGroupVersion.WithKind("Cluster")}
+			},
+			expectedError: "patches for Cluster are not supported",
+		},
+	}
+
+	for _, tt := range tests {
+		plugin := newFakePipelineStep()
+		tt.mutate(plugin)
+
+		err := RegisterPipelineStep(plugin)
+		if err == nil || !strings.Contains(err.Error(), tt.expectedError) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.expectedError, err)
+		}
+	}
+
+	if n := len(registeredPipelineSteps()); n != 1 {
+		t.Errorf("expected 1 registered pipeline step, got %d", n)
+	}
+}
+
+func TestPipelineStepPluginGatesStep(t *testing.T) {
+	for _, approved := range []bool{true, false} {
+		namespace := "test-namespace"
+		app := buildApplication(namespace, "test-app")
+		cluster := buildCluster("minikube")
+		f := newFixture(t, app.DeepCopy(), cluster.DeepCopy())
+
+		totalReplicaCount := int32(10)
+		incumbent := f.buildIncumbent(namespace, "test-incumbent", totalReplicaCount)
+		contender := f.buildContender(namespace, "test-contender", totalReplicaCount)
+		contender.capacityTarget.Spec.Clusters[0].Percent = 1
+		incumbent.capacityTarget.Spec.Clusters[0].Percent = 100
+
+		plugin := newFakePipelineStep()
+		plugin.approved = approved
+		// Plugins only get to patch the kinds they declared.
+		plugin.extraPatch = &CapacityTargetSpecPatch{
+			Name:    contender.capacityTarget.Name,
+			NewSpec: &shipper.CapacityTargetSpec{},
+		}
+
+		executor := NewStrategyExecutor(contender, incumbent, nil, nil, true)
+		executor.plugins = []PipelineStepPlugin{plugin}
+
+		complete, patches, _, err := executor.Execute()
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if complete != approved {
+			t.Errorf("expected step completion to be %t, got %t", approved, complete)
+		}
+
+		var statusPatch *ReleaseStrategyStatusPatch
+		for _, patch := range patches {
+			switch p := patch.(type) {
+			case *ReleaseStrategyStatusPatch:
+				statusPatch = p
+			case *CapacityTargetSpecPatch:
+				t.Errorf("expected undeclared capacity target patch to be dropped")
+			}
+		}
+
+		if approved {
+			continue
+		}
+
+		if statusPatch == nil {
+			t.Fatalf("expected a release strategy status patch")
+		}
+
+		newStatus := statusPatch.NewStrategyStatus
+		cond := conditions.NewStrategyConditions(newStatus.Conditions...)
+		if !cond.IsFalse(0, ticketApproved) {
+			t.Errorf("expected condition %s to be False", ticketApproved)
+		}
+		if newStatus.State.WaitingForCommand != shipper.StrategyStateFalse {
+			t.Errorf("expected release not to be waiting for command, got %s", newStatus.State.WaitingForCommand)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package release
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+	diffutil "github.com/bookingcom/shipper/pkg/util/diff"
+	releaseutil "github.com/bookingcom/shipper/pkg/util/release"
+)
+
+const (
+	ProgressDeadlineExceeded = "ProgressDeadlineExceeded"
+)
+
+// stepProgressDeadline returns the deadline for achieving the step a release
+// is targeting, if it has one.
+func stepProgressDeadline(rel *shipper.Release) (time.Duration, bool) {
+	strategy := rel.Spec.Environment.Strategy
+	targetStep := rel.Spec.TargetStep
+	if strategy == nil || int(targetStep) >= len(strategy.Steps) {
+		return 0, false
+	}
+
+	seconds := strategy.ProgressDeadlineSeconds
+	if stepSeconds := strategy.Steps[targetStep].ProgressDeadlineSeconds; stepSeconds != nil {
+		seconds = stepSeconds
+	}
+	if seconds == nil {
+		return 0, false
+	}
+
+	return time.Duration(*seconds) * time.Second, true
+}
+
+// checkProgressDeadline sets the Progressing condition of a release with a
+// progress deadline. Releases that don't achieve their target step in time
+// get it set to False, along with a Warning event. While the deadline hasn't
+// passed yet, it returns how long is left, so the caller can look at the
+// release again once it does.
+func (c *Controller) checkProgressDeadline(rel *shipper.Release, stepAchieved bool, diff *diffutil.MultiDiff, now time.Time) time.Duration {
+	deadline, ok := stepProgressDeadline(rel)
+	if !ok {
+		return 0
+	}
+
+	if stepAchieved {
+		cond := releaseutil.NewReleaseCondition(shipper.ReleaseConditionTypeProgressing, corev1.ConditionTrue, "", "")
+		diff.Append(releaseutil.SetReleaseCondition(&rel.Status, *cond))
+		return 0
+	}
+
+	remaining := releaseutil.LastProgressTime(rel).Add(deadline).Sub(now)
+	if remaining > 0 {
+		cond := releaseutil.NewReleaseCondition(shipper.ReleaseConditionTypeProgressing, corev1.ConditionTrue, "", "")
+		diff.Append(releaseutil.SetReleaseCondition(&rel.Status, *cond))
+		return remaining
+	}
+
+	msg := fmt.Sprintf("step [%d] was not achieved within %s", rel.Spec.TargetStep, deadline)
+	prevCond := releaseutil.GetReleaseCondition(rel.Status, shipper.ReleaseConditionTypeProgressing)
+	if prevCond == nil || prevCond.Status != corev1.ConditionFalse {
+		c.recorder.Event(rel, corev1.EventTypeWarning, ProgressDeadlineExceeded, msg)
+	}
+
+	cond := releaseutil.NewReleaseCondition(
+		shipper.ReleaseConditionTypeProgressing,
+		corev1.ConditionFalse,
+		ProgressDeadlineExceeded,
+		msg,
+	)
+	diff.Append(releaseutil.SetReleaseCondition(&rel.Status, *cond))
+
+	return 0
+}
@@ -0,0 +1,104 @@
+package release
+
+import (
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+	diffutil "github.com/bookingcom/shipper/pkg/util/diff"
+	releaseutil "github.com/bookingcom/shipper/pkg/util/release"
+)
+
+func TestCheckProgressDeadline(t *testing.T) {
+	now := time.Now()
+	strategyDeadline := int32(600)
+	stepDeadline := int32(60)
+
+	tests := []struct {
+		name              string
+		stepDeadline      *int32
+		stepAchieved      bool
+		createdAgo        time.Duration
+		expectedStatus    corev1.ConditionStatus
+		expectedRemaining time.Duration
+		expectEvent       bool
+	}{
+		{
+			name:              "within strategy deadline",
+			createdAgo:        4 * time.Minute,
+			expectedStatus:    corev1.ConditionTrue,
+			expectedRemaining: 6 * time.Minute,
+		},
+		{
+			name:           "strategy deadline exceeded",
+			createdAgo:     11 * time.Minute,
+			expectedStatus: corev1.ConditionFalse,
+			expectEvent:    true,
+		},
+		{
+			name:           "step deadline overrides strategy deadline",
+			stepDeadline:   &stepDeadline,
+			createdAgo:     4 * time.Minute,
+			expectedStatus: corev1.ConditionFalse,
+			expectEvent:    true,
+		},
+		{
+			name:           "achieved step is progressing",
+			stepAchieved:   true,
+			createdAgo:     11 * time.Minute,
+			expectedStatus: corev1.ConditionTrue,
+		},
+	}
+
+	for _, tt := range tests {
+		namespace := "test-namespace"
+		app := buildApplication(namespace, "test-app")
+		cluster := buildCluster("minikube")
+
+		f := newFixture(t, app.DeepCopy(), cluster.DeepCopy())
+		contender := f.buildContender(namespace, "test-contender", 10)
+
+		strategy := vanguard.DeepCopy()
+		strategy.ProgressDeadlineSeconds = &strategyDeadline
+		strategy.Steps[0].ProgressDeadlineSeconds = tt.stepDeadline
+
+		rel := contender.release
+		rel.CreationTimestamp = metav1.NewTime(now.Add(-tt.createdAgo))
+		rel.Spec.Environment.Strategy = strategy
+		rel.Status.Strategy = nil
+
+		c := newControllerForObjects(t, f.objects...)
+		recorder := c.recorder.(*record.FakeRecorder)
+
+		diff := diffutil.NewMultiDiff()
+		remaining := c.checkProgressDeadline(rel, tt.stepAchieved, diff, now)
+		if remaining != tt.expectedRemaining {
+			t.Errorf("%s: expected %s until the deadline, got %s", tt.name, tt.expectedRemaining, remaining)
+		}
+
+		cond := releaseutil.GetReleaseCondition(rel.Status, shipper.ReleaseConditionTypeProgressing)
+		if cond == nil || cond.Status != tt.expectedStatus {
+			t.Errorf("%s: expected condition Progressing to be %s, got %v", tt.name, tt.expectedStatus, cond)
+		} else if cond.Status == corev1.ConditionFalse && cond.Reason != ProgressDeadlineExceeded {
+			t.Errorf("%s: expected reason %q, got %q", tt.name, ProgressDeadlineExceeded, cond.Reason)
+		}
+
+		if gotEvent := len(recorder.Events) > 0; gotEvent != tt.expectEvent {
+			t.Errorf("%s: expected event to be %t, got %t", tt.name, tt.expectEvent, gotEvent)
+		}
+
+		// A release that is already known to be stuck doesn't get
+		// reported again.
+		if tt.expectEvent {
+			<-recorder.Events
+			c.checkProgressDeadline(rel, tt.stepAchieved, diffutil.NewMultiDiff(), now)
+			if len(recorder.Events) > 0 {
+				t.Errorf("%s: expected no further events, got %q", tt.name, <-recorder.Events)
+			}
+		}
+	}
+}
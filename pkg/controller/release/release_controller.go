@@ -30,6 +30,7 @@ import (
 	diffutil "github.com/bookingcom/shipper/pkg/util/diff"
 	releaseutil "github.com/bookingcom/shipper/pkg/util/release"
 	rolloutblock "github.com/bookingcom/shipper/pkg/util/rolloutblock"
+	strategyutil "github.com/bookingcom/shipper/pkg/util/strategy"
 	shipperworkqueue "github.com/bookingcom/shipper/pkg/workqueue"
 )
 
@@ -69,8 +70,15 @@ type Controller struct {
 	rolloutBlockLister shipperlisters.RolloutBlockLister
 	rolloutBlockSynced cache.InformerSynced
 
+	stepApprovalLister shipperlisters.StepApprovalLister
+	stepApprovalSynced cache.InformerSynced
+
 	releaseWorkqueue workqueue.RateLimitingInterface
 
+	// hookRateLimiter spaces out calls to step hooks that keep holding
+	// a release back.
+	hookRateLimiter workqueue.RateLimiter
+
 	chartFetcher shipperrepo.ChartFetcher
 
 	recorder record.EventRecorder
@@ -103,6 +111,7 @@ func NewController(
 	trafficTargetInformer := informerFactory.Shipper().V1alpha1().TrafficTargets()
 	capacityTargetInformer := informerFactory.Shipper().V1alpha1().CapacityTargets()
 	rolloutBlockInformer := informerFactory.Shipper().V1alpha1().RolloutBlocks()
+	stepApprovalInformer := informerFactory.Shipper().V1alpha1().StepApprovals()
 
 	klog.Info("Building a release controller")
 
@@ -130,10 +139,14 @@ func NewController(
 		rolloutBlockLister: rolloutBlockInformer.Lister(),
 		rolloutBlockSynced: rolloutBlockInformer.Informer().HasSynced,
 
+		stepApprovalLister: stepApprovalInformer.Lister(),
+		stepApprovalSynced: stepApprovalInformer.Informer().HasSynced,
+
 		releaseWorkqueue: workqueue.NewNamedRateLimitingQueue(
 			shipperworkqueue.NewDefaultControllerRateLimiter(),
 			"release_controller_releases",
 		),
+		hookRateLimiter: shipperworkqueue.NewDefaultControllerRateLimiter(),
 
 		chartFetcher: chartFetcher,
 
@@ -156,6 +169,11 @@ func NewController(
 			DeleteFunc: controller.enqueueReleaseFromRolloutBlock,
 		})
 
+	stepApprovalInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: controller.enqueueReleaseFromStepApproval,
+		})
+
 	eventHandler := cache.ResourceEventHandlerFuncs{
 		AddFunc: controller.enqueueReleaseFromAssociatedObject,
 		UpdateFunc: func(oldObj, newObj interface{}) {
@@ -188,6 +206,7 @@ func (c *Controller) Run(threadiness int, stopCh <-chan struct{}) {
 		c.trafficTargetsSynced,
 		c.capacityTargetsSynced,
 		c.rolloutBlockSynced,
+		c.stepApprovalSynced,
 	); !ok {
 		runtime.HandleError(fmt.Errorf("failed to wait for caches to sync"))
 		return
@@ -276,6 +295,7 @@ func (c *Controller) syncOneReleaseHandler(key string) error {
 	var strategyPatches []StrategyPatch
 	var trans []ReleaseStrategyStateTransition
 	var relinfo *releaseInfo
+	var scheduledRel *shipper.Release
 	var stepAchieved bool
 
 	// we keep baseRel as a comparison baseline in order to figure out if
@@ -283,6 +303,8 @@ func (c *Controller) syncOneReleaseHandler(key string) error {
 	baseRel := rel.DeepCopy()
 	patches := make([]StrategyPatch, 0)
 
+	recordTargetStepChange(rel, rel.Annotations[shipper.ReleaseTargetStepChangedByAnnotation], "", time.Now())
+
 	diff := diffutil.NewMultiDiff()
 	defer func() {
 		if !diff.IsEmpty() {
@@ -333,8 +355,20 @@ func (c *Controller) syncOneReleaseHandler(key string) error {
 	)
 	diff.Append(releaseutil.SetReleaseCondition(&rel.Status, *condition))
 
-	relinfo, err = scheduler.ScheduleRelease(rel.DeepCopy())
+	condition, err = c.startRescheduling(rel)
 	if err != nil {
+		goto ApplyChanges
+	} else if condition != nil {
+		diff.Append(releaseutil.SetReleaseCondition(&rel.Status, *condition))
+	}
+
+	scheduledRel = rel.DeepCopy()
+	relinfo, err = scheduler.ScheduleRelease(scheduledRel)
+	if err != nil {
+		// Scheduling tells why clusters weren't picked even when it
+		// fails, which is when it matters the most.
+		rel.Status.SchedulingDecisions = scheduledRel.Status.SchedulingDecisions
+
 		reason := reasonForReleaseCondition(err)
 		condition := releaseutil.NewReleaseCondition(
 			shipper.ReleaseConditionTypeScheduled,
@@ -379,6 +413,13 @@ func (c *Controller) syncOneReleaseHandler(key string) error {
 	)
 	diff.Append(releaseutil.SetReleaseCondition(&rel.Status, *condition))
 
+	condition, err = c.continueRescheduling(relinfo)
+	if err != nil {
+		goto ApplyChanges
+	} else if condition != nil {
+		diff.Append(releaseutil.SetReleaseCondition(&rel.Status, *condition))
+	}
+
 	if stepAchieved {
 		strategy := rel.Spec.Environment.Strategy
 		targetStep := rel.Spec.TargetStep
@@ -390,9 +431,11 @@ func (c *Controller) syncOneReleaseHandler(key string) error {
 			// Strategy.Steps early in the process
 			targetStepName := strategy.Steps[targetStep].Name
 			rel.Status.AchievedStep = &shipper.AchievedStep{
-				Step: targetStep,
-				Name: targetStepName,
+				Step:         targetStep,
+				Name:         targetStepName,
+				AchievedTime: metav1.Now(),
 			}
+			recordStepAchieved(rel, releaseStrategyConditions(rel, strategyPatches), time.Now())
 			c.recorder.Eventf(
 				rel,
 				corev1.EventTypeNormal,
@@ -411,6 +454,25 @@ func (c *Controller) syncOneReleaseHandler(key string) error {
 			)
 			diff.Append(releaseutil.SetReleaseCondition(&rel.Status, *condition))
 		}
+
+		if remaining := c.advanceStep(rel, time.Now()); remaining > 0 {
+			c.releaseWorkqueue.AddAfter(key, remaining)
+		}
+	} else if stepHasAnalysis(rel) || len(registeredPipelineSteps()) > 0 {
+		// Neither metrics nor whatever plugins look at are watched,
+		// so the only way to notice they changed is to check them
+		// again.
+		c.releaseWorkqueue.AddAfter(key, analysisInterval)
+	}
+
+	if stepHookFailed(rel, strategyPatches) {
+		c.releaseWorkqueue.AddAfter(key, c.hookRateLimiter.When(key))
+	} else {
+		c.hookRateLimiter.Forget(key)
+	}
+
+	if remaining := c.checkProgressDeadline(rel, stepAchieved, diff, time.Now()); remaining > 0 {
+		c.releaseWorkqueue.AddAfter(key, remaining)
 	}
 
 	for _, t := range trans {
@@ -486,7 +548,26 @@ func (c *Controller) ensureReleaseState(relinfo *releaseInfo) (bool, []StrategyP
 	// see pkg/util/conditions/strategy.go for more details.
 	hasIncumbent := len(releases) > 1
 
-	executor := NewStrategyExecutor(relinfo, relinfoPrev, relinfoSucc, hasIncumbent)
+	clusterRegions, err := c.clusterRegions()
+	if err != nil {
+		return false, nil, nil, err
+	}
+
+	executor := NewStrategyExecutor(relinfo, relinfoPrev, relinfoSucc, clusterRegions, hasIncumbent)
+
+	if draining := getReleaseDrainingClusters(relinfo.release); len(draining) > 0 &&
+		reschedulingSurged(relinfo, relinfoSucc != nil, clusterRegions) {
+		executor.drainingClusters = stringSet(draining)
+	}
+
+	strategy := relinfo.release.Spec.Environment.Strategy
+	if targetStep := relinfo.release.Spec.TargetStep; strategyutil.StepRequiresApproval(strategy, targetStep) {
+		approval, err := strategyutil.FindStepApproval(c.stepApprovalLister, relinfo.release, targetStep)
+		if err != nil {
+			return false, nil, nil, err
+		}
+		executor.stepApproved = approval != nil
+	}
 
 	complete, patches, trans, err := executor.Execute()
 
@@ -499,7 +580,29 @@ func (c *Controller) ensureReleaseState(relinfo *releaseInfo) (bool, []StrategyP
 	return complete, patches, trans, err
 }
 
+// clusterRegions returns a map of all known cluster names to their regions.
+func (c *Controller) clusterRegions() (map[string]string, error) {
+	selector := labels.Everything()
+	clusters, err := c.clusterLister.List(selector)
+	if err != nil {
+		return nil, shippererrors.NewKubeclientListError(
+			shipper.SchemeGroupVersion.WithKind("Cluster"),
+			"", selector, err)
+	}
+
+	regions := make(map[string]string, len(clusters))
+	for _, cluster := range clusters {
+		regions[cluster.Name] = cluster.Spec.Region
+	}
+
+	return regions, nil
+}
+
 func (c *Controller) applyPatch(namespace string, patch StrategyPatch) error {
+	if abort, ok := patch.(*ReleaseAbortPatch); ok {
+		return c.abortRelease(namespace, abort)
+	}
+
 	name, gvk, b := patch.PatchSpec()
 
 	var err error
@@ -522,6 +625,37 @@ func (c *Controller) applyPatch(namespace string, patch StrategyPatch) error {
 	return nil
 }
 
+// abortRelease deletes a contender release. The application controller
+// notices the release is gone and rolls the application back to the
+// incumbent.
+func (c *Controller) abortRelease(namespace string, patch *ReleaseAbortPatch) error {
+	rel, err := c.releaseLister.Releases(namespace).Get(patch.Name)
+	if err != nil {
+		return shippererrors.NewKubeclientGetError(namespace, patch.Name, err).
+			WithShipperKind("Release")
+	}
+
+	if err := c.recordAbort(rel, patch.Reason, time.Now()); err != nil {
+		return err
+	}
+
+	c.recorder.Eventf(
+		rel,
+		corev1.EventTypeWarning,
+		"ReleaseAborted",
+		"aborting release: %s",
+		patch.Reason,
+	)
+
+	err = c.clientset.ShipperV1alpha1().Releases(namespace).Delete(patch.Name, &metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return shippererrors.NewKubeclientDeleteError(namespace, patch.Name, err).
+			WithShipperKind("Release")
+	}
+
+	return nil
+}
+
 // getAssociatedApplicationKey returns an application key in the format:
 // <namespace>/<application name>
 func (c *Controller) getAssociatedApplicationKey(rel *shipper.Release) (string, error) {
@@ -692,6 +826,10 @@ func reasonForReleaseCondition(err error) string {
 		return "NotEnoughClustersInRegion"
 	case shippererrors.NotEnoughCapableClustersInRegionError:
 		return "NotEnoughCapableClustersInRegion"
+	case shippererrors.NotEnoughZonesInRegionError:
+		return "NotEnoughZonesInRegion"
+	case shippererrors.InvalidClusterSelectorError:
+		return "InvalidClusterSelector"
 
 	case shippererrors.DuplicateCapabilityRequirementError:
 		return "DuplicateCapabilityRequirement"
@@ -163,6 +163,19 @@ func (f *fixture) addObjects(objects ...runtime.Object) {
 }
 
 func (f *fixture) run() {
+	// Fixture releases are built already targeting the step under test,
+	// so we make their history agree, or else every sync would record a
+	// change of target step. The same goes for the releases we expect
+	// to be updated.
+	for _, obj := range f.objects {
+		seedReleaseHistory(obj)
+	}
+	for _, action := range f.actions {
+		if update, ok := action.(kubetesting.UpdateAction); ok {
+			seedReleaseHistory(update.GetObject())
+		}
+	}
+
 	f.clientset = shipperfake.NewSimpleClientset(f.objects...)
 
 	const syncPeriod time.Duration = 0
@@ -210,6 +223,12 @@ func (f *fixture) run() {
 	shippertesting.CheckEvents(f.expectedEvents, f.receivedEvents, f.t)
 }
 
+func seedReleaseHistory(obj runtime.Object) {
+	if rel, ok := obj.(*shipper.Release); ok && len(rel.Status.History) == 0 {
+		recordTargetStepChange(rel, "", "", rel.CreationTimestamp.Time)
+	}
+}
+
 func (f *fixture) newController() *Controller {
 	return NewController(
 		f.clientset,
@@ -878,8 +897,20 @@ func (f *fixture) expectReleaseScheduled(release *shipper.Release, clusters []*s
 	sort.Strings(clusterNames)
 	clusterNamesStr := strings.Join(clusterNames, ",")
 
+	allClusters := make([]*shipper.Cluster, 0)
+	for _, obj := range f.objects {
+		if cluster, ok := obj.(*shipper.Cluster); ok {
+			allClusters = append(allClusters, cluster)
+		}
+	}
+	_, decisions, err := explainTargetClusters(release, allClusters, nil)
+	if err != nil {
+		f.t.Fatalf("could not explain how release %q gets scheduled: %s", release.Name, err)
+	}
+
 	expected := release.DeepCopy()
 	expected.Annotations[shipper.ReleaseClustersAnnotation] = clusterNamesStr
+	expected.Status.SchedulingDecisions = decisions
 	expected.Status.Conditions = []shipper.ReleaseCondition{
 		{Type: shipper.ReleaseConditionTypeBlocked, Status: corev1.ConditionFalse},
 		{Type: shipper.ReleaseConditionTypeScheduled, Status: corev1.ConditionTrue},
@@ -2640,6 +2671,22 @@ func TestControllerChooseClusters(t *testing.T) {
 
 	expected := contender.DeepCopy()
 	expected.Annotations[shipper.ReleaseClustersAnnotation] = fmt.Sprintf("%s,%s", clusterA.Name, clusterB.Name)
+	expected.Status.SchedulingDecisions = []shipper.ClusterSchedulingDecision{
+		{
+			Name:       clusterA.Name,
+			Region:     shippertesting.TestRegion,
+			Selected:   true,
+			Reason:     shipper.ClusterSchedulingSelected,
+			Preference: 1,
+		},
+		{
+			Name:       clusterB.Name,
+			Region:     shippertesting.TestRegion,
+			Selected:   true,
+			Reason:     shipper.ClusterSchedulingSelected,
+			Preference: 2,
+		},
+	}
 	condScheduled := releaseutil.NewReleaseCondition(shipper.ReleaseConditionTypeScheduled, corev1.ConditionTrue, "", "")
 	releaseutil.SetReleaseCondition(&expected.Status, *condScheduled)
 	condStrategyExecuted := releaseutil.NewReleaseCondition(shipper.ReleaseConditionTypeStrategyExecuted, corev1.ConditionTrue, "", "")
@@ -2696,6 +2743,22 @@ func TestControllerChooseClustersSkipsUnschedulable(t *testing.T) {
 
 	expected := contender.DeepCopy()
 	expected.Annotations[shipper.ReleaseClustersAnnotation] = clusterA.Name
+	expected.Status.SchedulingDecisions = []shipper.ClusterSchedulingDecision{
+		{
+			Name:       clusterA.Name,
+			Region:     shippertesting.TestRegion,
+			Selected:   true,
+			Reason:     shipper.ClusterSchedulingSelected,
+			Preference: 1,
+		},
+		{
+			Name:       clusterB.Name,
+			Region:     shippertesting.TestRegion,
+			Reason:     shipper.ClusterSchedulingUnschedulable,
+			Preference: 2,
+			Message:    "cluster is marked unschedulable",
+		},
+	}
 
 	condScheduled := releaseutil.NewReleaseCondition(shipper.ReleaseConditionTypeScheduled, corev1.ConditionTrue, "", "")
 	releaseutil.SetReleaseCondition(&expected.Status, *condScheduled)
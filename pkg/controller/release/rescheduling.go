@@ -0,0 +1,348 @@
+package release
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+	shippererrors "github.com/bookingcom/shipper/pkg/errors"
+	releaseutil "github.com/bookingcom/shipper/pkg/util/release"
+	strategyutil "github.com/bookingcom/shipper/pkg/util/strategy"
+)
+
+const (
+	ReschedulingAddingClusters   = "AddingClusters"
+	ReschedulingDrainingClusters = "DrainingClusters"
+)
+
+// startRescheduling moves a release off the clusters it can't stay on, if
+// its cluster requirements ask for it. It only ever moves the latest release
+// of an application once it's complete: releases still rolling out finish
+// on the clusters they started on.
+//
+// Rescheduling goes through the same machinery as any other change of
+// clusters. The release is first scheduled on both the clusters it is
+// moving away from and the ones replacing them, so the strategy brings up
+// capacity and traffic in the new clusters. Only then are the old ones
+// drained (see continueRescheduling), and finally dropped.
+func (c *Controller) startRescheduling(rel *shipper.Release) (*shipper.ReleaseCondition, error) {
+	if !rel.Spec.Environment.ClusterRequirements.Reschedule ||
+		!releaseHasClusters(rel) ||
+		len(getReleaseDrainingClusters(rel)) > 0 ||
+		!releaseutil.ReleaseComplete(rel) {
+		return nil, nil
+	}
+
+	releases, err := c.applicationReleases(rel)
+	if err != nil {
+		return nil, err
+	}
+	_, succ, err := releaseutil.GetSiblingReleases(rel, releases)
+	if err != nil || succ != nil {
+		return nil, err
+	}
+
+	selector := labels.Everything()
+	clusterList, err := c.clusterLister.List(selector)
+	if err != nil {
+		return nil, shippererrors.NewKubeclientListError(
+			shipper.SchemeGroupVersion.WithKind("Cluster"),
+			"", selector, err)
+	}
+
+	current := getReleaseClusters(rel)
+	lost := lostClusters(current, clusterList)
+	if len(lost) == 0 {
+		return nil, nil
+	}
+
+	requests := releaseResourceRequests(c.chartFetcher, rel)
+	selectedClusters, decisions, err := explainTargetClusters(rel, clusterList, requests)
+	rel.Status.SchedulingDecisions = decisions
+	if err != nil {
+		return releaseutil.NewReleaseCondition(
+			shipper.ReleaseConditionTypeRescheduled,
+			corev1.ConditionFalse,
+			reasonForReleaseCondition(err),
+			fmt.Sprintf("cannot reschedule away from clusters [%s]: %s", strings.Join(lost, ","), err),
+		), nil
+	}
+
+	selected := make([]string, 0, len(selectedClusters))
+	for _, cluster := range selectedClusters {
+		selected = append(selected, cluster.Name)
+	}
+
+	draining := subtractClusters(current, selected)
+	if len(draining) == 0 {
+		return nil, nil
+	}
+	added := subtractClusters(selected, current)
+
+	// Clusters that were removed have nothing left to drain, and the
+	// release could never be installed there, so they are dropped right
+	// away instead.
+	removed := removedClusters(draining, clusterList)
+	draining = subtractClusters(draining, removed)
+
+	clusters := append(append([]string{}, selected...), draining...)
+	sort.Strings(clusters)
+
+	rel.Annotations[shipper.ReleaseClustersAnnotation] = strings.Join(clusters, ",")
+	if len(draining) == 0 {
+		return c.removedClustersDropped(rel, removed), nil
+	}
+	rel.Annotations[shipper.ReleaseDrainingClustersAnnotation] = strings.Join(draining, ",")
+
+	c.recorder.Eventf(
+		rel,
+		corev1.EventTypeNormal,
+		"ReleaseRescheduling",
+		"Rescheduling from clusters [%s] to [%s]",
+		strings.Join(draining, ","),
+		strings.Join(added, ","),
+	)
+
+	return releaseutil.NewReleaseCondition(
+		shipper.ReleaseConditionTypeRescheduled,
+		corev1.ConditionFalse,
+		ReschedulingAddingClusters,
+		fmt.Sprintf("bringing up clusters [%s] before draining [%s]", strings.Join(added, ","), strings.Join(draining, ",")),
+	), nil
+}
+
+// continueRescheduling drops the clusters a release is being rescheduled
+// away from once they are drained, and reports how far along it is
+// otherwise. It returns nil for releases that aren't being rescheduled.
+func (c *Controller) continueRescheduling(relinfo *releaseInfo) (*shipper.ReleaseCondition, error) {
+	rel := relinfo.release
+	draining := getReleaseDrainingClusters(rel)
+	if len(draining) == 0 {
+		return nil, nil
+	}
+
+	releases, err := c.applicationReleases(rel)
+	if err != nil {
+		return nil, err
+	}
+	_, succ, err := releaseutil.GetSiblingReleases(rel, releases)
+	if err != nil {
+		return nil, err
+	}
+
+	selector := labels.Everything()
+	clusterList, err := c.clusterLister.List(selector)
+	if err != nil {
+		return nil, shippererrors.NewKubeclientListError(
+			shipper.SchemeGroupVersion.WithKind("Cluster"),
+			"", selector, err)
+	}
+
+	// Clusters removed while the release was being moved off them can't
+	// be drained, or have the release installed, so they would hold it
+	// back forever.
+	if removed := removedClusters(draining, clusterList); len(removed) > 0 {
+		draining = subtractClusters(draining, removed)
+		clusters := subtractClusters(getReleaseClusters(rel), removed)
+		rel.Annotations[shipper.ReleaseClustersAnnotation] = strings.Join(clusters, ",")
+		if len(draining) == 0 {
+			return c.removedClustersDropped(rel, removed), nil
+		}
+		rel.Annotations[shipper.ReleaseDrainingClustersAnnotation] = strings.Join(draining, ",")
+	}
+
+	clusterRegions, err := c.clusterRegions()
+	if err != nil {
+		return nil, err
+	}
+
+	if !reschedulingSurged(relinfo, succ != nil, clusterRegions) {
+		return releaseutil.NewReleaseCondition(
+			shipper.ReleaseConditionTypeRescheduled,
+			corev1.ConditionFalse,
+			ReschedulingAddingClusters,
+			fmt.Sprintf("waiting for the other clusters to achieve step %d before draining [%s]", rel.Spec.TargetStep, strings.Join(draining, ",")),
+		), nil
+	}
+
+	if !clustersDrained(relinfo, draining) {
+		return releaseutil.NewReleaseCondition(
+			shipper.ReleaseConditionTypeRescheduled,
+			corev1.ConditionFalse,
+			ReschedulingDrainingClusters,
+			fmt.Sprintf("draining clusters [%s]", strings.Join(draining, ",")),
+		), nil
+	}
+
+	remaining := subtractClusters(getReleaseClusters(rel), draining)
+	rel.Annotations[shipper.ReleaseClustersAnnotation] = strings.Join(remaining, ",")
+	delete(rel.Annotations, shipper.ReleaseDrainingClustersAnnotation)
+
+	c.recorder.Eventf(
+		rel,
+		corev1.EventTypeNormal,
+		"ReleaseRescheduled",
+		"Drained clusters [%s], release now runs on [%s]",
+		strings.Join(draining, ","),
+		strings.Join(remaining, ","),
+	)
+
+	return releaseutil.NewReleaseCondition(
+		shipper.ReleaseConditionTypeRescheduled,
+		corev1.ConditionTrue,
+		"",
+		fmt.Sprintf("moved away from clusters [%s]", strings.Join(draining, ",")),
+	), nil
+}
+
+// removedClustersDropped finishes rescheduling a release that has nothing
+// left to drain once the clusters that were removed are dropped.
+func (c *Controller) removedClustersDropped(rel *shipper.Release, removed []string) *shipper.ReleaseCondition {
+	delete(rel.Annotations, shipper.ReleaseDrainingClustersAnnotation)
+
+	c.recorder.Eventf(
+		rel,
+		corev1.EventTypeNormal,
+		"ReleaseRescheduled",
+		"Dropped removed clusters [%s], release now runs on [%s]",
+		strings.Join(removed, ","),
+		rel.Annotations[shipper.ReleaseClustersAnnotation],
+	)
+
+	return releaseutil.NewReleaseCondition(
+		shipper.ReleaseConditionTypeRescheduled,
+		corev1.ConditionTrue,
+		"",
+		fmt.Sprintf("moved away from removed clusters [%s]", strings.Join(removed, ",")),
+	)
+}
+
+// reschedulingSurged tells whether a release being rescheduled achieved its
+// step in every cluster it isn't moving away from, which is when it's safe
+// to start draining the others. Releases that were superseded in the
+// meantime are on their way out anyway, so they are drained right away.
+func reschedulingSurged(curr *releaseInfo, superseded bool, clusterRegions map[string]string) bool {
+	if superseded {
+		return true
+	}
+
+	rel := curr.release
+	strategy := rel.Spec.Environment.Strategy
+	targetStep := rel.Spec.TargetStep
+	clusters := getReleaseClusters(rel)
+	draining := stringSet(getReleaseDrainingClusters(rel))
+
+	var steps map[string]int32
+	if strategyutil.HasClusterSteps(strategy) {
+		steps = strategyutil.ClusterStepIndices(strategy, targetStep, clusters, clusterRegions)
+	}
+
+	for _, cluster := range clusters {
+		if _, ok := draining[cluster]; ok {
+			continue
+		}
+
+		step, ok := steps[cluster]
+		if !ok {
+			step = targetStep
+		}
+
+		capacity := strategyutil.StepValue(strategy, step, true, strategyutil.PickCapacity)
+		replicas := strategyutil.StepReplicas(strategy, step, true)
+		traffic := uint32(strategyutil.StepValue(strategy, step, true, strategyutil.PickTraffic))
+		if !clusterAchievedStep(curr, cluster, capacity, replicas, traffic) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// clustersDrained tells whether a release has no capacity and no traffic
+// left in the given clusters.
+func clustersDrained(relinfo *releaseInfo, draining []string) bool {
+	for _, cluster := range draining {
+		if !clusterAchievedStep(relinfo, cluster, 0, nil, 0) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// removedClusters returns the given clusters that no longer exist.
+func removedClusters(clusters []string, clusterList []*shipper.Cluster) []string {
+	known := make(map[string]struct{}, len(clusterList))
+	for _, cluster := range clusterList {
+		known[cluster.Name] = struct{}{}
+	}
+
+	removed := []string{}
+	for _, name := range clusters {
+		if _, ok := known[name]; !ok {
+			removed = append(removed, name)
+		}
+	}
+
+	return removed
+}
+
+// lostClusters returns the clusters a release can't stay on, because they
+// were marked unschedulable, went out of service or were removed
+// altogether.
+func lostClusters(clusters []string, clusterList []*shipper.Cluster) []string {
+	byName := make(map[string]*shipper.Cluster, len(clusterList))
+	for _, cluster := range clusterList {
+		byName[cluster.Name] = cluster
+	}
+
+	lost := []string{}
+	for _, name := range clusters {
+		cluster, ok := byName[name]
+		if !ok || cluster.Spec.Scheduler.Unschedulable || !clusterInService(cluster) {
+			lost = append(lost, name)
+		}
+	}
+
+	return lost
+}
+
+// getReleaseDrainingClusters returns the clusters a release is being
+// rescheduled away from.
+func getReleaseDrainingClusters(rel *shipper.Release) []string {
+	annotation := rel.Annotations[shipper.ReleaseDrainingClustersAnnotation]
+	if annotation == "" {
+		return nil
+	}
+
+	clusters := strings.Split(annotation, ",")
+	sort.Strings(clusters)
+
+	return clusters
+}
+
+// subtractClusters returns the clusters in a that aren't in b.
+func subtractClusters(a, b []string) []string {
+	exclude := stringSet(b)
+
+	clusters := []string{}
+	for _, cluster := range a {
+		if _, ok := exclude[cluster]; !ok {
+			clusters = append(clusters, cluster)
+		}
+	}
+
+	return clusters
+}
+
+func stringSet(strs []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(strs))
+	for _, s := range strs {
+		set[s] = struct{}{}
+	}
+	return set
+}
@@ -0,0 +1,244 @@
+package release
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+	shippertesting "github.com/bookingcom/shipper/pkg/testing"
+	releaseutil "github.com/bookingcom/shipper/pkg/util/release"
+)
+
+// TestLostClusters verifies that clusters are only lost when they are
+// marked unschedulable, explicitly taken out of service or removed, and
+// not when they never said whether they are in service.
+func TestLostClusters(t *testing.T) {
+	noStatus := buildCluster("cluster-a")
+	inService := buildCluster("cluster-b")
+	inService.Status.InService = pbool(true)
+	unschedulable := buildCluster("cluster-c")
+	unschedulable.Spec.Scheduler.Unschedulable = true
+	outOfService := buildCluster("cluster-d")
+	outOfService.Status.InService = pbool(false)
+
+	clusterList := []*shipper.Cluster{noStatus, inService, unschedulable, outOfService}
+	lost := lostClusters([]string{"cluster-a", "cluster-b", "cluster-c", "cluster-d", "cluster-e"}, clusterList)
+
+	expected := []string{"cluster-c", "cluster-d", "cluster-e"}
+	if !reflect.DeepEqual(lost, expected) {
+		t.Fatalf("expected lost clusters %v, got %v", expected, lost)
+	}
+}
+
+func TestSubtractClusters(t *testing.T) {
+	got := subtractClusters([]string{"cluster-a", "cluster-b", "cluster-c"}, []string{"cluster-b"})
+	expected := []string{"cluster-a", "cluster-c"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+// TestDrainingClustersGetNoCapacity verifies that, once a release being
+// rescheduled is ready to be drained, the strategy takes capacity and
+// traffic away from the clusters it is moving off while keeping the others
+// at its target step.
+func TestDrainingClustersGetNoCapacity(t *testing.T) {
+	namespace := "test-namespace"
+	app := buildApplication(namespace, "test-app")
+	staying := buildCluster("cluster-a")
+	draining := buildCluster("cluster-b")
+
+	f := newFixture(t, app.DeepCopy(), staying.DeepCopy(), draining.DeepCopy())
+
+	totalReplicaCount := int32(10)
+	contender := f.buildContender(namespace, "test-contender", totalReplicaCount)
+	contender.release.Spec.TargetStep = 2
+	contender.release.Annotations[shipper.ReleaseDrainingClustersAnnotation] = draining.Name
+	for i := range contender.capacityTarget.Spec.Clusters {
+		contender.capacityTarget.Spec.Clusters[i].Percent = 100
+	}
+	for i := range contender.trafficTarget.Spec.Clusters {
+		contender.trafficTarget.Spec.Clusters[i].Weight = 100
+	}
+
+	regions := map[string]string{
+		staying.Name:  shippertesting.TestRegion,
+		draining.Name: shippertesting.TestRegion,
+	}
+
+	executor := NewStrategyExecutor(contender, nil, nil, regions, false)
+	executor.drainingClusters = stringSet(getReleaseDrainingClusters(contender.release))
+
+	_, patches, _, err := executor.Execute()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var ctPatch *CapacityTargetSpecPatch
+	for _, patch := range patches {
+		if p, ok := patch.(*CapacityTargetSpecPatch); ok {
+			ctPatch = p
+		}
+	}
+	if ctPatch == nil {
+		t.Fatalf("expected a capacity target patch, got %v", patches)
+	}
+
+	expected := map[string]int32{
+		staying.Name:  100,
+		draining.Name: 0,
+	}
+	for _, spec := range ctPatch.NewSpec.Clusters {
+		if spec.Percent != expected[spec.Name] {
+			t.Errorf("expected cluster %q to be at %d%% capacity, got %d%%",
+				spec.Name, expected[spec.Name], spec.Percent)
+		}
+	}
+}
+
+func TestReschedulingSurged(t *testing.T) {
+	namespace := "test-namespace"
+	app := buildApplication(namespace, "test-app")
+	staying := buildCluster("cluster-a")
+	draining := buildCluster("cluster-b")
+
+	f := newFixture(t, app.DeepCopy(), staying.DeepCopy(), draining.DeepCopy())
+
+	contender := f.buildContender(namespace, "test-contender", 10)
+	contender.release.Spec.TargetStep = 2
+	contender.release.Annotations[shipper.ReleaseDrainingClustersAnnotation] = draining.Name
+
+	regions := map[string]string{
+		staying.Name:  shippertesting.TestRegion,
+		draining.Name: shippertesting.TestRegion,
+	}
+
+	if reschedulingSurged(contender, false, regions) {
+		t.Errorf("expected release not to have surged while %q has no capacity", staying.Name)
+	}
+
+	if !reschedulingSurged(contender, true, regions) {
+		t.Errorf("expected superseded release to be drained right away")
+	}
+}
+
+// TestRescheduleOffRemovedCluster verifies that a complete release on a
+// cluster that was removed drops it right away, so its installation target
+// stops waiting for a cluster the release can never be installed in,
+// rather than trying to drain it.
+func TestRescheduleOffRemovedCluster(t *testing.T) {
+	namespace := "test-namespace"
+	app := buildApplication(namespace, "test-app")
+	clusterA := buildCluster("cluster-a")
+	clusterB := buildCluster("cluster-b")
+	clusterC := buildCluster("cluster-c")
+
+	f := newFixture(t, app.DeepCopy(), clusterA.DeepCopy(), clusterB.DeepCopy())
+
+	contender := f.buildContender(namespace, "test-contender", 10)
+	rel := contender.release
+	rel.Spec.TargetStep = 2
+	rel.Spec.Environment.ClusterRequirements.Regions[0].Replicas = pint32(2)
+	rel.Spec.Environment.ClusterRequirements.Reschedule = true
+	rel.Status.AchievedStep = &shipper.AchievedStep{Step: 2, Name: vanguard.Steps[2].Name}
+	releaseutil.SetReleaseCondition(&rel.Status, *releaseutil.NewReleaseCondition(
+		shipper.ReleaseConditionTypeComplete, corev1.ConditionTrue, "", ""))
+	for i := range contender.capacityTarget.Spec.Clusters {
+		contender.capacityTarget.Spec.Clusters[i].Percent = 100
+	}
+	for i := range contender.trafficTarget.Spec.Clusters {
+		contender.trafficTarget.Spec.Clusters[i].Weight = 100
+	}
+	contender.installationTarget.Status.Conditions = []shipper.TargetCondition{
+		{
+			Type:    shipper.TargetConditionTypeReady,
+			Status:  corev1.ConditionFalse,
+			Message: "cluster \"cluster-b\" not found",
+		},
+	}
+
+	// cluster-b goes away, and cluster-c is the only other cluster left
+	// to replace it.
+	f.objects = []runtime.Object{
+		app.DeepCopy(),
+		clusterA.DeepCopy(),
+		clusterC.DeepCopy(),
+		rel,
+		contender.installationTarget,
+		contender.capacityTarget,
+		contender.trafficTarget,
+	}
+	f.cycles = 1
+	f.filter = f.filter.Extend(actionfilter{[]string{"none"}, []string{"none"}})
+	f.expectedEvents = []string{
+		"Normal ReleaseRescheduled Dropped removed clusters [cluster-b], release now runs on [cluster-a,cluster-c]",
+		`Normal ReleaseScheduled Updated InstallationTarget "test-namespace/test-contender" cluster set to [cluster-a,cluster-c]`,
+		`Normal ReleaseScheduled Updated TrafficTarget "test-namespace/test-contender" cluster set to [cluster-a,cluster-c]`,
+		`Normal ReleaseScheduled Updated CapacityTarget "test-namespace/test-contender" cluster set to [cluster-a,cluster-c]`,
+		"Normal ReleaseConditionChanged [] -> [Rescheduled True moved away from removed clusters [cluster-b]], [] -> [Scheduled True], [] -> [StrategyExecuted True]",
+	}
+
+	f.run()
+
+	updated, err := f.clientset.ShipperV1alpha1().Releases(namespace).Get(rel.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if clusters := updated.Annotations[shipper.ReleaseClustersAnnotation]; clusters != "cluster-a,cluster-c" {
+		t.Errorf("expected release to run on clusters %q, got %q", "cluster-a,cluster-c", clusters)
+	}
+	if draining, ok := updated.Annotations[shipper.ReleaseDrainingClustersAnnotation]; ok {
+		t.Errorf("expected release not to drain any clusters, got %q", draining)
+	}
+
+	cond := releaseutil.GetReleaseCondition(updated.Status, shipper.ReleaseConditionTypeRescheduled)
+	if cond == nil || cond.Status != corev1.ConditionTrue {
+		t.Errorf("expected release to be rescheduled, got condition %+v", cond)
+	}
+
+	it, err := f.clientset.ShipperV1alpha1().InstallationTargets(namespace).Get(rel.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"cluster-a", "cluster-c"}
+	if !reflect.DeepEqual(it.Spec.Clusters, expected) {
+		t.Fatalf("expected installation target to be on clusters %v, got %v", expected, it.Spec.Clusters)
+	}
+
+	// Once the release is installed in the clusters it has left, the
+	// strategy brings capacity up in the one replacing cluster-b.
+	ct, err := f.clientset.ShipperV1alpha1().CapacityTargets(namespace).Get(rel.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tt, err := f.clientset.ShipperV1alpha1().TrafficTargets(namespace).Get(rel.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	it.Status.Conditions = []shipper.TargetCondition{
+		{Type: shipper.TargetConditionTypeReady, Status: corev1.ConditionTrue},
+	}
+
+	f = newFixture(t, app.DeepCopy(), clusterA.DeepCopy(), clusterC.DeepCopy(), updated, it, ct, tt)
+	f.cycles = 1
+	f.filter = f.filter.Extend(actionfilter{[]string{"none"}, []string{"none"}})
+	f.expectedEvents = []string{}
+
+	f.run()
+
+	ct, err = f.clientset.ShipperV1alpha1().CapacityTargets(namespace).Get(rel.Name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, spec := range ct.Spec.Clusters {
+		if spec.Percent != 100 {
+			t.Errorf("expected cluster %q to be at 100%% capacity, got %d%%", spec.Name, spec.Percent)
+		}
+	}
+}
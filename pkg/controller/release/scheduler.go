@@ -20,7 +20,10 @@ import (
 	listers "github.com/bookingcom/shipper/pkg/client/listers/shipper/v1alpha1"
 	"github.com/bookingcom/shipper/pkg/controller"
 	shippererrors "github.com/bookingcom/shipper/pkg/errors"
+	capacityutil "github.com/bookingcom/shipper/pkg/util/capacity"
+	"github.com/bookingcom/shipper/pkg/util/headroom"
 	releaseutil "github.com/bookingcom/shipper/pkg/util/release"
+	"github.com/bookingcom/shipper/pkg/util/replicas"
 )
 
 type Scheduler struct {
@@ -77,7 +80,9 @@ func (s *Scheduler) ChooseClusters(rel *shipper.Release) (*shipper.Release, erro
 			"", selector, err)
 	}
 
-	selectedClusters, err := computeTargetClusters(rel, allClusters)
+	requests := releaseResourceRequests(s.chartFetcher, rel)
+	selectedClusters, decisions, err := explainTargetClusters(rel, allClusters, requests)
+	rel.Status.SchedulingDecisions = decisions
 	if err != nil {
 		return nil, err
 	}
@@ -107,7 +112,7 @@ func (s *Scheduler) ScheduleRelease(rel *shipper.Release) (*releaseInfo, error)
 		)
 	}
 
-	replicaCount, err := s.fetchChartAndExtractReplicaCount(rel)
+	workloads, err := s.fetchChartAndExtractReplicaCounts(rel)
 	if err != nil {
 		return nil, err
 	}
@@ -124,7 +129,7 @@ func (s *Scheduler) ScheduleRelease(rel *shipper.Release) (*releaseInfo, error)
 		releaseErrors.Append(err)
 	}
 
-	ct, err := s.CreateOrUpdateCapacityTarget(rel, replicaCount)
+	ct, err := s.CreateOrUpdateCapacityTarget(rel, workloads)
 	if err != nil {
 		releaseErrors.Append(err)
 	}
@@ -213,23 +218,148 @@ func setInstallationTargetClusters(it *shipper.InstallationTarget, clusters []st
 	it.Spec.Clusters = clusters
 }
 
-func setCapacityTargetClusters(ct *shipper.CapacityTarget, clusters []string, totalReplicaCount int32) {
+// setCapacityTargetClusters sets the clusters of a capacity target. Clusters
+// it already had keep their capacity, so changing the set of clusters never
+// scales down the ones that stay. New clusters start out empty, and are
+// brought up by the strategy. Every cluster gets the workloads
+// workloadCounts has for it, and a total replica count adding them all up.
+func setCapacityTargetClusters(ct *shipper.CapacityTarget, clusters []string, workloadCounts map[string][]shipper.WorkloadCapacityTarget) {
+	existing := make(map[string]shipper.ClusterCapacityTarget, len(ct.Spec.Clusters))
+	for _, spec := range ct.Spec.Clusters {
+		existing[spec.Name] = spec
+	}
+
 	capacityTargetClusters := make([]shipper.ClusterCapacityTarget, 0, len(clusters))
 	for _, cluster := range clusters {
-		capacityTargetClusters = append(
-			capacityTargetClusters,
-			shipper.ClusterCapacityTarget{
-				Name:              cluster,
-				Percent:           0,
-				TotalReplicaCount: totalReplicaCount,
-			})
+		spec, ok := existing[cluster]
+		if !ok {
+			spec = shipper.ClusterCapacityTarget{
+				Name:    cluster,
+				Percent: 0,
+			}
+		}
+
+		spec.TotalReplicaCount = 0
+		for _, workload := range workloadCounts[cluster] {
+			spec.TotalReplicaCount += workload.TotalReplicaCount
+		}
+
+		// Charts with a single workload don't list it, so their
+		// capacity targets look just like they always have.
+		spec.Workloads = nil
+		if len(workloadCounts[cluster]) > 1 {
+			spec.Workloads = workloadCounts[cluster]
+		}
+
+		capacityTargetClusters = append(capacityTargetClusters, spec)
 	}
 	ct.Spec.Clusters = capacityTargetClusters
 }
 
+// clusterWorkloadCounts works out how many replicas each of a release's
+// workloads runs in each of its clusters, splitting each of them up the
+// same way clusterReplicaCounts does.
+func (s *Scheduler) clusterWorkloadCounts(rel *shipper.Release, clusters []string, workloads []shipper.WorkloadCapacityTarget) (map[string][]shipper.WorkloadCapacityTarget, error) {
+	counts := make(map[string][]shipper.WorkloadCapacityTarget, len(clusters))
+	for _, workload := range workloads {
+		replicaCounts, err := s.clusterReplicaCounts(rel, clusters, workload.TotalReplicaCount)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, cluster := range clusters {
+			w := workload
+			w.TotalReplicaCount = replicaCounts[cluster]
+			counts[cluster] = append(counts[cluster], w)
+		}
+	}
+
+	return counts, nil
+}
+
+// clusterReplicaCounts works out how many replicas a release runs in each of
+// its clusters: as many as the chart asks for, unless the release asks for
+// them to be weighted in the cluster's region. Clusters the release is being
+// rescheduled away from are left out of the split, so they don't take
+// replicas away from the ones replacing them.
+func (s *Scheduler) clusterReplicaCounts(rel *shipper.Release, clusters []string, replicaCount int32) (map[string]int32, error) {
+	counts := make(map[string]int32, len(clusters))
+	for _, cluster := range clusters {
+		counts[cluster] = replicaCount
+	}
+
+	weightedRegions := map[string]struct{}{}
+	for _, region := range rel.Spec.Environment.ClusterRequirements.Regions {
+		if region.WeightedReplicas {
+			weightedRegions[region.Name] = struct{}{}
+		}
+	}
+	if len(weightedRegions) == 0 {
+		return counts, nil
+	}
+
+	draining := stringSet(getReleaseDrainingClusters(rel))
+	clustersByRegion := map[string][]*shipper.Cluster{}
+	for _, name := range clusters {
+		if _, ok := draining[name]; ok {
+			continue
+		}
+
+		cluster, err := s.clusterLister.Get(name)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, shippererrors.NewKubeclientGetError("", name, err).
+				WithShipperKind("Cluster")
+		}
+
+		region := cluster.Spec.Region
+		if _, ok := weightedRegions[region]; !ok {
+			continue
+		}
+
+		clustersByRegion[region] = append(clustersByRegion[region], cluster)
+	}
+
+	for _, regionClusters := range clustersByRegion {
+		for cluster, count := range weightedReplicaCounts(replicaCount, regionClusters) {
+			counts[cluster] = count
+		}
+	}
+
+	return counts, nil
+}
+
+// weightedReplicaCounts splits the replicas of the given clusters, all of
+// them in the same region, among them according to their scheduler weights.
+func weightedReplicaCounts(replicaCount int32, clusters []*shipper.Cluster) map[string]int32 {
+	weights := make(map[string]int32, len(clusters))
+	for _, cluster := range clusters {
+		weight := int32(defaultClusterWeight)
+		if cluster.Spec.Scheduler.Weight != nil {
+			weight = *cluster.Spec.Scheduler.Weight
+		}
+		weights[cluster.Name] = weight
+	}
+
+	return replicas.WeightedReplicaCounts(replicaCount, weights)
+}
+
+// setTrafficTargetClusters sets the clusters of a traffic target. Like with
+// capacity, clusters it already had keep their weight.
 func setTrafficTargetClusters(tt *shipper.TrafficTarget, clusters []string) {
+	existing := make(map[string]shipper.ClusterTrafficTarget, len(tt.Spec.Clusters))
+	for _, spec := range tt.Spec.Clusters {
+		existing[spec.Name] = spec
+	}
+
 	trafficTargetClusters := make([]shipper.ClusterTrafficTarget, 0, len(clusters))
 	for _, cluster := range clusters {
+		if spec, ok := existing[cluster]; ok {
+			trafficTargetClusters = append(trafficTargetClusters, spec)
+			continue
+		}
+
 		trafficTargetClusters = append(
 			trafficTargetClusters,
 			shipper.ClusterTrafficTarget{
@@ -313,9 +443,14 @@ func (s *Scheduler) CreateOrUpdateInstallationTarget(rel *shipper.Release) (*shi
 	return it, nil
 }
 
-func (s *Scheduler) CreateOrUpdateCapacityTarget(rel *shipper.Release, totalReplicaCount int32) (*shipper.CapacityTarget, error) {
+func (s *Scheduler) CreateOrUpdateCapacityTarget(rel *shipper.Release, workloads []shipper.WorkloadCapacityTarget) (*shipper.CapacityTarget, error) {
 	clusters := getReleaseClusters(rel)
 
+	workloadCounts, err := s.clusterWorkloadCounts(rel, clusters, workloads)
+	if err != nil {
+		return nil, err
+	}
+
 	ct, err := s.capacityTargetLister.CapacityTargets(rel.GetNamespace()).Get(rel.GetName())
 	if err != nil {
 		if !errors.IsNotFound(err) {
@@ -334,7 +469,7 @@ func (s *Scheduler) CreateOrUpdateCapacityTarget(rel *shipper.Release, totalRepl
 				},
 			},
 		}
-		setCapacityTargetClusters(ct, clusters, totalReplicaCount)
+		setCapacityTargetClusters(ct, clusters, workloadCounts)
 
 		updCt, err := s.clientset.ShipperV1alpha1().CapacityTargets(rel.GetNamespace()).Create(ct)
 		if err != nil {
@@ -360,7 +495,7 @@ func (s *Scheduler) CreateOrUpdateCapacityTarget(rel *shipper.Release, totalRepl
 		klog.V(4).Infof("Updating CapacityTarget %q clusters to %s",
 			controller.MetaKey(ct),
 			strings.Join(clusters, ","))
-		setCapacityTargetClusters(ct, clusters, totalReplicaCount)
+		setCapacityTargetClusters(ct, clusters, workloadCounts)
 		updCt, err := s.clientset.ShipperV1alpha1().CapacityTargets(rel.GetNamespace()).Update(ct)
 		if err != nil {
 			klog.Errorf("Failed to update CapacityTarget %q clusters: %s",
@@ -403,6 +538,9 @@ func (s *Scheduler) CreateOrUpdateTrafficTarget(rel *shipper.Release) (*shipper.
 			},
 		}
 		setTrafficTargetClusters(tt, clusters)
+		if strategy := rel.Spec.Environment.Strategy; strategy != nil {
+			tt.Spec.StrategyType = strategy.Type
+		}
 
 		updTt, err := s.clientset.ShipperV1alpha1().TrafficTargets(rel.GetNamespace()).Create(tt)
 		if err != nil {
@@ -450,8 +588,10 @@ func (s *Scheduler) CreateOrUpdateTrafficTarget(rel *shipper.Release) (*shipper.
 }
 
 // computeTargetClusters picks out the clusters from the given list which match
-// the release's clusterRequirements.
-func computeTargetClusters(rel *shipper.Release, clusterList []*shipper.Cluster) ([]*shipper.Cluster, error) {
+// the release's clusterRequirements. Clusters known not to have room for the
+// resources the release would request in them are only picked when there's
+// nothing else left.
+func computeTargetClusters(rel *shipper.Release, clusterList []*shipper.Cluster, requests []workloadRequests) ([]*shipper.Cluster, error) {
 	regionSpecs := rel.Spec.Environment.ClusterRequirements.Regions
 	requiredCapabilities := rel.Spec.Environment.ClusterRequirements.Capabilities
 	capableClustersByRegion := map[string][]*shipper.Cluster{}
@@ -471,7 +611,13 @@ func computeTargetClusters(rel *shipper.Release, clusterList []*shipper.Cluster)
 		return nil, err
 	}
 
-	prefList := buildPrefList(app, clusterList)
+	selector, err := clusterSelector(rel.Spec.Environment.ClusterRequirements)
+	if err != nil {
+		return nil, err
+	}
+
+	prefList := buildPrefList(app, clusterList, selector)
+	prefList = preferClustersWithHeadroom(prefList, clusterResourceRequests(rel, prefList, requests))
 	// This algo could probably build up hashes instead of doing linear searches,
 	// but these data sets are so tiny (1-20 items) that it'd only be useful for
 	// readability.
@@ -483,14 +629,23 @@ func computeTargetClusters(rel *shipper.Release, clusterList []*shipper.Cluster)
 			regionReplicas[region.Name] = int(*region.Replicas)
 		}
 
+		// Clusters the selector doesn't match are already left out of
+		// the preference list, but they still count as being in the
+		// region, so running out of them is told apart from not
+		// having enough clusters at all.
 		matchedRegion := 0
+		for _, cluster := range clusterList {
+			if clusterSchedulable(rel, cluster) && cluster.Spec.Region == region.Name {
+				matchedRegion++
+			}
+		}
+
 		for _, cluster := range prefList {
-			if cluster.Spec.Scheduler.Unschedulable {
+			if !clusterSchedulable(rel, cluster) {
 				continue
 			}
 
 			if cluster.Spec.Region == region.Name {
-				matchedRegion++
 				capabilityMatch := 0
 				for _, requiredCapability := range requiredCapabilities {
 					for _, providedCapability := range cluster.Spec.Capabilities {
@@ -511,17 +666,34 @@ func computeTargetClusters(rel *shipper.Release, clusterList []*shipper.Cluster)
 		}
 	}
 
+	spreadAcrossZones := map[string]bool{}
+	for _, region := range regionSpecs {
+		spreadAcrossZones[region.Name] = region.SpreadAcrossZones
+	}
+
 	resClusters := make([]*shipper.Cluster, 0)
 	for region, clusters := range capableClustersByRegion {
 		if regionReplicas[region] > len(clusters) {
 			return nil, shippererrors.NewNotEnoughCapableClustersInRegionError(
 				region,
 				requiredCapabilities,
+				selectorString(selector),
 				regionReplicas[region],
 				len(clusters),
 			)
 		}
 
+		if spreadAcrossZones[region] {
+			clusters = oneClusterPerZone(clusters)
+			if regionReplicas[region] > len(clusters) {
+				return nil, shippererrors.NewNotEnoughZonesInRegionError(
+					region,
+					regionReplicas[region],
+					len(clusters),
+				)
+			}
+		}
+
 		//NOTE(btyler): this assumes we do not have duplicate cluster names. For the
 		//moment cluster objects are cluster scoped; if they become namespace scoped
 		//and releases can somehow be scheduled to clusters from multiple namespaces,
@@ -541,6 +713,46 @@ func computeTargetClusters(rel *shipper.Release, clusterList []*shipper.Cluster)
 	return resClusters, nil
 }
 
+// clusterSchedulable tells whether a release can be scheduled on a cluster
+// at all. Releases that ask to be rescheduled also stay off clusters that
+// are out of service, since they would only be moved away from them again.
+func clusterSchedulable(rel *shipper.Release, cluster *shipper.Cluster) bool {
+	if cluster.Spec.Scheduler.Unschedulable {
+		return false
+	}
+
+	return !rel.Spec.Environment.ClusterRequirements.Reschedule || clusterInService(cluster)
+}
+
+// clusterInService tells whether a cluster is fit to run workloads. Only
+// clusters explicitly taken out of service aren't.
+func clusterInService(cluster *shipper.Cluster) bool {
+	return cluster.Status.InService == nil || *cluster.Status.InService
+}
+
+// oneClusterPerZone keeps the first cluster of every zone, keeping the order
+// they're preferred in. Clusters without a zone can't be told apart from any
+// other, so they're left out.
+func oneClusterPerZone(clusters []*shipper.Cluster) []*shipper.Cluster {
+	seenZones := map[string]struct{}{}
+	zoneClusters := make([]*shipper.Cluster, 0, len(clusters))
+	for _, cluster := range clusters {
+		zone := cluster.Spec.Zone
+		if zone == "" {
+			continue
+		}
+
+		if _, ok := seenZones[zone]; ok {
+			continue
+		}
+
+		seenZones[zone] = struct{}{}
+		zoneClusters = append(zoneClusters, cluster)
+	}
+
+	return zoneClusters
+}
+
 func validateClusterRequirements(requirements shipper.ClusterRequirements) error {
 	// Ensure capability uniqueness. Erroring instead of de-duping in order to
 	// avoid second-guessing by operators about how Shipper might treat repeated
@@ -557,6 +769,29 @@ func validateClusterRequirements(requirements shipper.ClusterRequirements) error
 	return nil
 }
 
+// clusterSelector returns the selector for the clusters a release can be
+// scheduled on, which selects every cluster if it doesn't have one.
+func clusterSelector(requirements shipper.ClusterRequirements) (labels.Selector, error) {
+	if requirements.Selector == nil {
+		return labels.Everything(), nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(requirements.Selector)
+	if err != nil {
+		return nil, shippererrors.NewInvalidClusterSelectorError(err)
+	}
+
+	return selector, nil
+}
+
+func selectorString(selector labels.Selector) string {
+	if selector.Empty() {
+		return ""
+	}
+
+	return selector.String()
+}
+
 func setReleaseClusters(rel *shipper.Release, clusters []*shipper.Cluster) {
 	clusterNames := make([]string, 0, len(clusters))
 	memo := make(map[string]struct{})
@@ -571,53 +806,226 @@ func setReleaseClusters(rel *shipper.Release, clusters []*shipper.Cluster) {
 	rel.Annotations[shipper.ReleaseClustersAnnotation] = strings.Join(clusterNames, ",")
 }
 
-func (s *Scheduler) fetchChartAndExtractReplicaCount(rel *shipper.Release) (int32, error) {
+func (s *Scheduler) fetchChartAndExtractReplicaCounts(rel *shipper.Release) ([]shipper.WorkloadCapacityTarget, error) {
 	chart, err := s.chartFetcher(&rel.Spec.Environment.Chart)
 	if err != nil {
-		return 0, err
+		return nil, err
 	}
 
-	replicas, err := extractReplicasFromChartForRel(chart, rel)
+	workloads, err := extractReplicasFromChartForRel(chart, rel)
 	if err != nil {
-		return 0, err
+		return nil, err
+	}
+
+	for _, workload := range workloads {
+		klog.V(4).Infof("Extracted %d replicas for %s %q from release %q",
+			workload.TotalReplicaCount, workload.Kind, workload.Name, controller.MetaKey(rel))
+	}
+
+	return workloads, nil
+}
+
+// extractReplicasFromChartForRel returns how many replicas each of the
+// workloads in a release's chart asks for. Workloads that opted out of
+// capacity management are left out, as the strategy doesn't scale them.
+func extractReplicasFromChartForRel(chart *helmchart.Chart, rel *shipper.Release) ([]shipper.WorkloadCapacityTarget, error) {
+	workloads, err := extractWorkloadsFromChartForRel(chart, rel)
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make([]shipper.WorkloadCapacityTarget, 0, len(workloads))
+	for _, workload := range workloads {
+		counts = append(counts, shipper.WorkloadCapacityTarget{
+			Kind:              workload.kind,
+			Name:              workload.name,
+			TotalReplicaCount: workload.replicas,
+		})
 	}
 
-	klog.V(4).Infof("Extracted %d replicas from release %q", replicas, controller.MetaKey(rel))
+	return counts, nil
+}
+
+// chartWorkload is what the release controller needs to know about an
+// object a release's capacity is scaled through, which is either a
+// Deployment or a StatefulSet.
+type chartWorkload struct {
+	kind     string
+	name     string
+	replicas int32
+	template corev1.PodTemplateSpec
+}
+
+func newChartWorkload(kind string, obj metav1.Object, replicas *int32, template corev1.PodTemplateSpec) chartWorkload {
+	// Deployments and StatefulSets default to 1 replica when replicas is
+	// nil or unspecified. See k8s.io/api/apps/v1/types.go's DeploymentSpec
+	// and StatefulSetSpec.
+	count := int32(1)
+	if replicas != nil {
+		count = *replicas
+	}
 
-	return int32(replicas), nil
+	return chartWorkload{
+		kind:     kind,
+		name:     obj.GetName(),
+		replicas: count,
+		template: template,
+	}
 }
 
-func extractReplicasFromChartForRel(chart *helmchart.Chart, rel *shipper.Release) (int32, error) {
+// extractWorkloadsFromChartForRel renders a release's chart the same way
+// the installation controller does, so workloads have the names they're
+// installed with, and returns the ones capacity is scaled through.
+func extractWorkloadsFromChartForRel(chart *helmchart.Chart, rel *shipper.Release) ([]chartWorkload, error) {
 	owners := rel.OwnerReferences
 	if l := len(owners); l != 1 {
-		return 0, shippererrors.NewMultipleOwnerReferencesError(rel.Name, l)
+		return nil, shippererrors.NewMultipleOwnerReferencesError(rel.Name, l)
 	}
 
-	applicationName := owners[0].Name
-	rendered, err := shipperchart.Render(chart, applicationName, rel.Namespace, rel.Spec.Environment.Values)
+	rendered, err := shipperchart.Render(chart, rel.Name, rel.Namespace, rel.Spec.Environment.Values)
 	if err != nil {
-		return 0, shippererrors.NewBrokenChartSpecError(
+		return nil, shippererrors.NewBrokenChartSpecError(
 			&rel.Spec.Environment.Chart,
 			err,
 		)
 	}
 
-	deployments := shipperchart.GetDeployments(rendered)
-	if len(deployments) != 1 {
-		return 0, shippererrors.NewWrongChartDeploymentsError(
+	workloads := []chartWorkload{}
+	for _, d := range shipperchart.GetDeployments(rendered) {
+		if !capacityutil.OptedOut(&d) {
+			workloads = append(workloads, newChartWorkload("Deployment", &d, d.Spec.Replicas, d.Spec.Template))
+		}
+	}
+	for _, ss := range shipperchart.GetStatefulSets(rendered) {
+		if !capacityutil.OptedOut(&ss) {
+			workloads = append(workloads, newChartWorkload("StatefulSet", &ss, ss.Spec.Replicas, ss.Spec.Template))
+		}
+	}
+
+	if len(workloads) == 0 {
+		return nil, shippererrors.NewWrongChartDeploymentsError(
 			&rel.Spec.Environment.Chart,
-			len(deployments),
+			len(workloads),
 		)
 	}
 
-	replicas := deployments[0].Spec.Replicas
-	// Deployments default to 1 replica when replicas is nil or unspecified. See
-	// k8s.io/api/apps/v1/types.go's DeploymentSpec.
-	if replicas == nil {
-		return 1, nil
+	return workloads, nil
+}
+
+// workloadRequests is what every pod of one of a release's workloads
+// requests, and how many replicas of it the chart asks for.
+type workloadRequests struct {
+	pod      corev1.ResourceList
+	replicas int32
+}
+
+// releaseResourceRequests returns what the workloads of a release request.
+// Scheduling doesn't depend on it, so releases with charts that can't be
+// worked out are scheduled as if they requested nothing, and fail later on.
+func releaseResourceRequests(chartFetcher shipperrepo.ChartFetcher, rel *shipper.Release) []workloadRequests {
+	chart, err := chartFetcher(&rel.Spec.Environment.Chart)
+	if err != nil {
+		klog.V(4).Infof("Could not fetch chart for release %q: %s", controller.MetaKey(rel), err)
+		return nil
+	}
+
+	workloads, err := extractWorkloadsFromChartForRel(chart, rel)
+	if err != nil {
+		klog.V(4).Infof("Could not extract resource requests for release %q: %s", controller.MetaKey(rel), err)
+		return nil
+	}
+
+	requests := make([]workloadRequests, 0, len(workloads))
+	for _, workload := range workloads {
+		requests = append(requests, workloadRequests{
+			pod:      headroom.PodRequests(workload.template.Spec),
+			replicas: workload.replicas,
+		})
+	}
+
+	return requests
+}
+
+// clusterResourceRequests works out how much CPU and memory a release would
+// request in each of the clusters in a preference list. Clusters run as
+// many replicas as the chart asks for, except in regions with weighted
+// replicas, where they only run their share of them. Which clusters that
+// share is split with isn't known until they are picked, so it's taken to
+// be the most preferred other clusters in the region the release could run
+// on.
+func clusterResourceRequests(rel *shipper.Release, prefList []*shipper.Cluster, requests []workloadRequests) map[string]corev1.ResourceList {
+	if len(requests) == 0 {
+		return nil
+	}
+
+	requirements := rel.Spec.Environment.ClusterRequirements
+	weightedRegions := map[string]int{}
+	for _, region := range requirements.Regions {
+		if region.WeightedReplicas {
+			weightedRegions[region.Name] = int(regionReplicas(region))
+		}
+	}
+
+	candidates := map[string][]*shipper.Cluster{}
+	for _, cluster := range prefList {
+		if clusterSchedulable(rel, cluster) && len(missingCapabilities(cluster, requirements.Capabilities)) == 0 {
+			candidates[cluster.Spec.Region] = append(candidates[cluster.Spec.Region], cluster)
+		}
+	}
+
+	required := make(map[string]corev1.ResourceList, len(prefList))
+	for _, cluster := range prefList {
+		var peers []*shipper.Cluster
+		if n, ok := weightedRegions[cluster.Spec.Region]; ok {
+			peers = []*shipper.Cluster{cluster}
+			for _, other := range candidates[cluster.Spec.Region] {
+				if len(peers) >= n {
+					break
+				}
+				if other.Name != cluster.Name {
+					peers = append(peers, other)
+				}
+			}
+		}
+
+		clusterRequests := corev1.ResourceList{}
+		for _, workload := range requests {
+			replicaCount := workload.replicas
+			if peers != nil {
+				replicaCount = weightedReplicaCounts(workload.replicas, peers)[cluster.Name]
+			}
+
+			for name, q := range headroom.Multiply(workload.pod, replicaCount) {
+				total := clusterRequests[name]
+				total.Add(q)
+				clusterRequests[name] = total
+			}
+		}
+		required[cluster.Name] = clusterRequests
+	}
+
+	return required
+}
+
+// preferClustersWithHeadroom moves clusters that don't have room for the
+// resources required in them to the end of a preference list, keeping the
+// order clusters are preferred in otherwise.
+func preferClustersWithHeadroom(prefList []*shipper.Cluster, required map[string]corev1.ResourceList) []*shipper.Cluster {
+	if len(required) == 0 {
+		return prefList
+	}
+
+	fitting := make([]*shipper.Cluster, 0, len(prefList))
+	crowded := make([]*shipper.Cluster, 0)
+	for _, cluster := range prefList {
+		if headroom.Fits(cluster.Status.Headroom, required[cluster.Name]) {
+			fitting = append(fitting, cluster)
+		} else {
+			crowded = append(crowded, cluster)
+		}
 	}
 
-	return int32(*replicas), nil
+	return append(fitting, crowded...)
 }
 
 // The strings here are insane, but if you create a fresh release object for
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	kubetesting "k8s.io/client-go/testing"
@@ -89,6 +90,14 @@ func buildRelease() *shipper.Release {
 	}
 }
 
+// buildWorkloadCapacityTargets returns the workloads of a chart with a
+// single Deployment asking for the given number of replicas.
+func buildWorkloadCapacityTargets(replicas int32) []shipper.WorkloadCapacityTarget {
+	return []shipper.WorkloadCapacityTarget{
+		{Kind: "Deployment", Name: "test-deployment", TotalReplicaCount: replicas},
+	}
+}
+
 func buildAssociatedObjects(release *shipper.Release, clusters []*shipper.Cluster) (*shipper.InstallationTarget, *shipper.TrafficTarget, *shipper.CapacityTarget) {
 
 	clusterNames := make([]string, 0, len(clusters))
@@ -263,6 +272,10 @@ func pint32(i int32) *int32 {
 	return &i
 }
 
+func pbool(b bool) *bool {
+	return &b
+}
+
 func pstr(s string) *string {
 	return &s
 }
@@ -282,7 +295,7 @@ func computeClusterTestCase(
 		clusters = append(clusters, generateClusterForTestCase(i, spec))
 	}
 
-	actualClusters, err := computeTargetClusters(release, clusters)
+	actualClusters, err := computeTargetClusters(release, clusters, nil)
 	if expectError {
 		if err == nil {
 			t.Errorf("test %q expected an error but didn't get one!", name)
@@ -405,6 +418,39 @@ func TestScheduleSkipsUnschedulable(t *testing.T) {
 	}
 }
 
+// TestScheduleSkipsOutOfService checks that releases asking to be
+// rescheduled are never scheduled on clusters out of service, while
+// clusters that never said whether they are in service, like the ones from
+// before clusters could say, still get them.
+func TestScheduleSkipsOutOfService(t *testing.T) {
+	clusterA := buildCluster("minikube-a")
+	clusterB := buildCluster("minikube-b")
+	clusterB.Status.InService = pbool(false)
+	clusterList := []*shipper.Cluster{clusterA, clusterB}
+
+	release := buildRelease()
+	release.Spec.Environment.ClusterRequirements.Regions[0].Replicas = pint32(2)
+
+	if _, err := computeTargetClusters(release, clusterList, nil); err != nil {
+		t.Fatalf("expected release not asking to be rescheduled to use both clusters, got: %s", err)
+	}
+
+	release.Spec.Environment.ClusterRequirements.Reschedule = true
+	if _, err := computeTargetClusters(release, clusterList, nil); err == nil {
+		t.Fatalf("expected release asking to be rescheduled not to fit in one cluster in service")
+	}
+
+	release.Spec.Environment.ClusterRequirements.Regions[0].Replicas = pint32(1)
+	clusters, err := computeTargetClusters(release, clusterList, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(clusters) != 1 || clusters[0].Name != clusterA.Name {
+		t.Errorf("expected release to be scheduled on %q only, got %v", clusterA.Name, clusters)
+	}
+}
+
 // TestCreateAssociatedObjects checks whether the associated object set is being
 // created while a release is being scheduled. In a normal case scenario, all 3
 // objects do not exist by the moment of scheduling, therefore 3 extra create
@@ -438,6 +484,164 @@ func TestCreateAssociatedObjects(t *testing.T) {
 	shippertesting.CheckActions(expectedActions, filteredActions, t)
 }
 
+// TestCreateCapacityTargetWeightedReplicas verifies that, for regions asking
+// for weighted replicas, the replicas of every cluster in the region are
+// split among them according to their scheduler weights.
+func TestCreateCapacityTargetWeightedReplicas(t *testing.T) {
+	clusterA := buildCluster("minikube-a")
+	clusterA.Spec.Scheduler.Weight = pint32(70)
+	clusterB := buildCluster("minikube-b")
+	clusterB.Spec.Scheduler.Weight = pint32(30)
+
+	release := buildRelease()
+	release.Annotations[shipper.ReleaseClustersAnnotation] = fmt.Sprintf("%s,%s", clusterA.Name, clusterB.Name)
+	release.Spec.Environment.ClusterRequirements.Regions[0].Replicas = pint32(2)
+	release.Spec.Environment.ClusterRequirements.Regions[0].WeightedReplicas = true
+
+	c, _ := newScheduler([]runtime.Object{release, clusterA, clusterB})
+	ct, err := c.CreateOrUpdateCapacityTarget(release.DeepCopy(), buildWorkloadCapacityTargets(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]int32{
+		clusterA.Name: 14,
+		clusterB.Name: 6,
+	}
+	if len(ct.Spec.Clusters) != len(expected) {
+		t.Fatalf("expected capacity target to carry %d clusters, got %d", len(expected), len(ct.Spec.Clusters))
+	}
+	for _, spec := range ct.Spec.Clusters {
+		if spec.TotalReplicaCount != expected[spec.Name] {
+			t.Errorf("expected cluster %q to run %d replicas, got %d",
+				spec.Name, expected[spec.Name], spec.TotalReplicaCount)
+		}
+	}
+}
+
+// TestCreateCapacityTargetWeightedReplicasZeroWeight verifies that a
+// cluster weighted 0 still runs a replica, since it gets traffic like any
+// other cluster the release is scheduled on.
+func TestCreateCapacityTargetWeightedReplicasZeroWeight(t *testing.T) {
+	clusterA := buildCluster("minikube-a")
+	clusterB := buildCluster("minikube-b")
+	clusterB.Spec.Scheduler.Weight = pint32(0)
+
+	release := buildRelease()
+	release.Annotations[shipper.ReleaseClustersAnnotation] = fmt.Sprintf("%s,%s", clusterA.Name, clusterB.Name)
+	release.Spec.Environment.ClusterRequirements.Regions[0].Replicas = pint32(2)
+	release.Spec.Environment.ClusterRequirements.Regions[0].WeightedReplicas = true
+
+	c, _ := newScheduler([]runtime.Object{release, clusterA, clusterB})
+	ct, err := c.CreateOrUpdateCapacityTarget(release.DeepCopy(), buildWorkloadCapacityTargets(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]int32{
+		clusterA.Name: 19,
+		clusterB.Name: 1,
+	}
+	for _, spec := range ct.Spec.Clusters {
+		if spec.TotalReplicaCount != expected[spec.Name] {
+			t.Errorf("expected cluster %q to run %d replicas, got %d",
+				spec.Name, expected[spec.Name], spec.TotalReplicaCount)
+		}
+	}
+}
+
+// TestCreateCapacityTargetMultipleWorkloads verifies that a chart with more
+// than one workload gets each of them listed in every cluster of its
+// capacity target, with their replicas split up among clusters just like
+// the total.
+func TestCreateCapacityTargetMultipleWorkloads(t *testing.T) {
+	clusterA := buildCluster("minikube-a")
+	clusterB := buildCluster("minikube-b")
+
+	release := buildRelease()
+	release.Annotations[shipper.ReleaseClustersAnnotation] = fmt.Sprintf("%s,%s", clusterA.Name, clusterB.Name)
+
+	workloads := []shipper.WorkloadCapacityTarget{
+		{Kind: "Deployment", Name: "web", TotalReplicaCount: 10},
+		{Kind: "Deployment", Name: "worker", TotalReplicaCount: 4},
+	}
+
+	c, _ := newScheduler([]runtime.Object{release, clusterA, clusterB})
+	ct, err := c.CreateOrUpdateCapacityTarget(release.DeepCopy(), workloads)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, spec := range ct.Spec.Clusters {
+		if spec.TotalReplicaCount != 14 {
+			t.Errorf("expected cluster %q to run 14 replicas, got %d", spec.Name, spec.TotalReplicaCount)
+		}
+
+		if len(spec.Workloads) != len(workloads) {
+			t.Fatalf("expected cluster %q to list %d workloads, got %v", spec.Name, len(workloads), spec.Workloads)
+		}
+		for i, workload := range workloads {
+			if spec.Workloads[i] != workload {
+				t.Errorf("expected cluster %q to have workload %+v, got %+v", spec.Name, workload, spec.Workloads[i])
+			}
+		}
+	}
+}
+
+// TestExtractReplicasFromChartMultipleWorkloads verifies that replicas are
+// extracted for every Deployment and StatefulSet in a chart, leaving out the
+// ones that opted out of capacity management.
+func TestExtractReplicasFromChartMultipleWorkloads(t *testing.T) {
+	chart := &helmchart.Chart{
+		Metadata: &helmchart.Metadata{Name: "multi", Version: "0.0.1"},
+		Templates: []*helmchart.Template{
+			{
+				Name: "templates/workloads.yaml",
+				Data: []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ .Release.Name }}-web
+spec:
+  replicas: 10
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ .Release.Name }}-cron
+  annotations:
+    shipper.booking.com/capacity.optOut: "true"
+spec:
+  replicas: 1
+---
+apiVersion: apps/v1
+kind: StatefulSet
+metadata:
+  name: {{ .Release.Name }}-db
+`),
+			},
+		},
+	}
+
+	release := buildRelease()
+	workloads, err := extractReplicasFromChartForRel(chart, release)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []shipper.WorkloadCapacityTarget{
+		{Kind: "Deployment", Name: release.Name + "-web", TotalReplicaCount: 10},
+		{Kind: "StatefulSet", Name: release.Name + "-db", TotalReplicaCount: 1},
+	}
+	if len(workloads) != len(expected) {
+		t.Fatalf("expected workloads %v, got %v", expected, workloads)
+	}
+	for i := range expected {
+		if workloads[i] != expected[i] {
+			t.Errorf("expected workload %+v, got %+v", expected[i], workloads[i])
+		}
+	}
+}
+
 // TestCreateAssociatedObjectsDuplicateInstallationTargetMismatchingClusters
 // tests a case when an installation target already exists but has a mismatching
 // set of clusters. The job of the scheduler is to correct the mismatch and
@@ -843,7 +1047,9 @@ func TestCreateAssociatedObjectsDuplicateCapacityTargetSameOwner(t *testing.T) {
 			},
 		},
 	}
-	setCapacityTargetClusters(capacitytarget, []string{cluster.Name}, totalReplicaCount)
+	setCapacityTargetClusters(capacitytarget, []string{cluster.Name}, map[string][]shipper.WorkloadCapacityTarget{
+		cluster.Name: buildWorkloadCapacityTargets(totalReplicaCount),
+	})
 	fixtures := []runtime.Object{cluster, release, capacitytarget}
 
 	// Expected release and actions. Even with an existing capacitytarget object
@@ -909,7 +1115,7 @@ func TestCreateAssociatedObjectsDuplicateCapacityTargetNoOwner(t *testing.T) {
 
 	c, _ := newScheduler(fixtures)
 
-	_, err := c.CreateOrUpdateCapacityTarget(release.DeepCopy(), 1)
+	_, err := c.CreateOrUpdateCapacityTarget(release.DeepCopy(), buildWorkloadCapacityTargets(1))
 	if err == nil {
 		t.Fatalf("Expected an error here, none received")
 	}
@@ -1171,4 +1377,278 @@ func TestComputeTargetClusters(t *testing.T) {
 		expected{"cluster-0", "cluster-2"},
 		passingCase,
 	)
+
+	computeClusterTestCase(t, "spread across zones",
+		requirements{
+			Regions: []shipper.RegionRequirement{
+				{Name: "matches", Replicas: pint32(2), SpreadAcrossZones: true},
+			},
+		},
+		clusters{
+			{Region: "matches", Zone: "a", Capabilities: []string{}},
+			{Region: "matches", Capabilities: []string{}},
+			{Region: "matches", Zone: "b", Capabilities: []string{}},
+		},
+		expected{"cluster-0", "cluster-2"},
+		passingCase,
+	)
+
+	computeClusterTestCase(t, "error when spread across zones can't be met",
+		requirements{
+			Regions: []shipper.RegionRequirement{
+				{Name: "matches", Replicas: pint32(2), SpreadAcrossZones: true},
+			},
+		},
+		clusters{
+			{Region: "matches", Zone: "a", Capabilities: []string{}},
+			{Region: "matches", Zone: "a", Capabilities: []string{}},
+			{Region: "matches", Capabilities: []string{}},
+		},
+		expected{},
+		errorCase,
+	)
+}
+
+func TestComputeTargetClustersWithSelector(t *testing.T) {
+	clusterLabels := []map[string]string{
+		{"environment": "canary", "tier": "gold"},
+		{"environment": "production", "tier": "gold"},
+		{"environment": "production"},
+	}
+
+	clusters := make([]*shipper.Cluster, 0, len(clusterLabels))
+	for i, labels := range clusterLabels {
+		cluster := generateClusterForTestCase(i, shipper.ClusterSpec{Region: "matches"})
+		cluster.Labels = labels
+		clusters = append(clusters, cluster)
+	}
+
+	tests := []struct {
+		name        string
+		replicas    int32
+		selector    *metav1.LabelSelector
+		expected    []string
+		expectError bool
+	}{
+		{
+			name:     "match labels",
+			replicas: 1,
+			selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"environment": "canary"},
+			},
+			expected: []string{"cluster-0"},
+		},
+		{
+			name:     "not in",
+			replicas: 2,
+			selector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "environment", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"canary"}},
+				},
+			},
+			expected: []string{"cluster-1", "cluster-2"},
+		},
+		{
+			name:     "does not exist",
+			replicas: 1,
+			selector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "tier", Operator: metav1.LabelSelectorOpDoesNotExist},
+				},
+			},
+			expected: []string{"cluster-2"},
+		},
+		{
+			name:     "not enough selected clusters",
+			replicas: 2,
+			selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"environment": "canary"},
+			},
+			expectError: true,
+		},
+		{
+			name:     "invalid selector",
+			replicas: 1,
+			selector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "tier", Operator: metav1.LabelSelectorOpIn},
+				},
+			},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		release := generateReleaseForTestCase(shipper.ClusterRequirements{
+			Regions:  []shipper.RegionRequirement{{Name: "matches", Replicas: pint32(tt.replicas)}},
+			Selector: tt.selector,
+		})
+
+		actualClusters, err := computeTargetClusters(release, clusters, nil)
+		if tt.expectError {
+			if err == nil {
+				t.Errorf("test %q expected an error but didn't get one!", tt.name)
+			}
+			continue
+		} else if err != nil {
+			t.Errorf("error %q: %q", tt.name, err)
+			continue
+		}
+
+		actualClusterNames := make([]string, 0, len(actualClusters))
+		for _, cluster := range actualClusters {
+			actualClusterNames = append(actualClusterNames, cluster.GetName())
+		}
+
+		if strings.Join(tt.expected, ",") != strings.Join(actualClusterNames, ",") {
+			t.Errorf("%q expected clusters %q, but got %q", tt.name, strings.Join(tt.expected, ","), strings.Join(actualClusterNames, ","))
+		}
+	}
+}
+
+func TestComputeTargetClustersPrefersHeadroom(t *testing.T) {
+	requests := []workloadRequests{
+		{
+			pod: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse("1"),
+				corev1.ResourceMemory: resource.MustParse("2Gi"),
+			},
+			replicas: 2,
+		},
+	}
+	roomy := &shipper.ClusterHeadroom{
+		Available: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("8"),
+			corev1.ResourceMemory: resource.MustParse("16Gi"),
+		},
+	}
+	crowded := &shipper.ClusterHeadroom{
+		Available: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("1"),
+			corev1.ResourceMemory: resource.MustParse("16Gi"),
+		},
+	}
+
+	release := generateReleaseForTestCase(shipper.ClusterRequirements{
+		Regions: []shipper.RegionRequirement{{Name: "a", Replicas: pint32(1)}},
+	})
+
+	// Whichever of the two clusters is otherwise preferred, the one with
+	// room for the release should be picked.
+	for roomyIndex := 0; roomyIndex < 2; roomyIndex++ {
+		clusters := []*shipper.Cluster{
+			generateClusterForTestCase(0, shipper.ClusterSpec{Region: "a"}),
+			generateClusterForTestCase(1, shipper.ClusterSpec{Region: "a"}),
+		}
+		clusters[roomyIndex].Status.Headroom = roomy
+		clusters[1-roomyIndex].Status.Headroom = crowded
+
+		actualClusters, err := computeTargetClusters(release, clusters, requests)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if len(actualClusters) != 1 || actualClusters[0].Name != clusters[roomyIndex].Name {
+			t.Errorf("expected cluster %q to be picked, got %v", clusters[roomyIndex].Name, actualClusters)
+		}
+	}
+
+	// Clusters without room are still picked when nothing else is left.
+	clusters := []*shipper.Cluster{
+		generateClusterForTestCase(0, shipper.ClusterSpec{Region: "a"}),
+	}
+	clusters[0].Status.Headroom = crowded
+
+	actualClusters, err := computeTargetClusters(release, clusters, requests)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(actualClusters) != 1 {
+		t.Errorf("expected crowded cluster to be picked for lack of others, got %v", actualClusters)
+	}
+}
+
+// TestComputeTargetClustersWeightedHeadroom verifies that, in regions with
+// weighted replicas, clusters only need room for their share of the
+// release's replicas to be preferred.
+func TestComputeTargetClustersWeightedHeadroom(t *testing.T) {
+	requests := []workloadRequests{
+		{
+			pod:      corev1.ResourceList{corev1.ResourceCPU: resource.MustParse("1")},
+			replicas: 4,
+		},
+	}
+	headroomFor := func(cpu string) *shipper.ClusterHeadroom {
+		return &shipper.ClusterHeadroom{
+			Available: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)},
+		}
+	}
+
+	clusters := []*shipper.Cluster{
+		generateClusterForTestCase(0, shipper.ClusterSpec{
+			Region:    "a",
+			Scheduler: shipper.ClusterSchedulerSettings{Weight: pint32(75)},
+		}),
+		generateClusterForTestCase(1, shipper.ClusterSpec{
+			Region:    "a",
+			Scheduler: shipper.ClusterSchedulerSettings{Weight: pint32(25)},
+		}),
+		generateClusterForTestCase(2, shipper.ClusterSpec{
+			Region:    "a",
+			Scheduler: shipper.ClusterSchedulerSettings{Weight: pint32(25)},
+		}),
+	}
+	clusters[0].Status.Headroom = headroomFor("8")
+	clusters[1].Status.Headroom = headroomFor("3")
+	clusters[2].Status.Headroom = headroomFor("1")
+
+	tests := []struct {
+		name     string
+		weighted bool
+		required map[string]string
+	}{
+		{
+			"every cluster runs all replicas",
+			false,
+			map[string]string{"cluster-0": "4", "cluster-1": "4", "cluster-2": "4"},
+		},
+		{
+			"clusters run their share of replicas",
+			true,
+			map[string]string{"cluster-0": "6", "cluster-1": "2", "cluster-2": "2"},
+		},
+	}
+
+	for _, tt := range tests {
+		release := generateReleaseForTestCase(shipper.ClusterRequirements{
+			Regions: []shipper.RegionRequirement{
+				{Name: "a", Replicas: pint32(2), WeightedReplicas: tt.weighted},
+			},
+		})
+
+		required := clusterResourceRequests(release, clusters, requests)
+		for name, cpu := range tt.required {
+			q := required[name][corev1.ResourceCPU]
+			if q.Cmp(resource.MustParse(cpu)) != 0 {
+				t.Errorf("%s: expected cluster %q to require %s cpu, got %s", tt.name, name, cpu, q.String())
+			}
+		}
+
+		_, decisions, err := explainTargetClusters(release, clusters, requests)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", tt.name, err)
+		}
+
+		// Clusters without room are either left out, or picked for
+		// lack of others with a message saying so.
+		for _, decision := range decisions {
+			crowded := decision.Reason == shipper.ClusterSchedulingInsufficientHeadroom ||
+				(decision.Selected && decision.Message != "")
+			expectCrowded := decision.Name == "cluster-2" || (!tt.weighted && decision.Name == "cluster-1")
+			if crowded != expectCrowded {
+				t.Errorf("%s: expected cluster %q to lack headroom: %t, got decision %+v",
+					tt.name, decision.Name, expectCrowded, decision)
+			}
+		}
+	}
 }
@@ -0,0 +1,165 @@
+package release
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+	"github.com/bookingcom/shipper/pkg/util/headroom"
+	releaseutil "github.com/bookingcom/shipper/pkg/util/release"
+)
+
+// explainTargetClusters picks out clusters for a release just like
+// computeTargetClusters does, and also tells why each of the given clusters
+// was or wasn't picked, most preferred first. There is nothing to explain
+// when the release's cluster requirements aren't valid to begin with, so no
+// decisions are returned then.
+func explainTargetClusters(rel *shipper.Release, clusterList []*shipper.Cluster, requests []workloadRequests) ([]*shipper.Cluster, []shipper.ClusterSchedulingDecision, error) {
+	selectedClusters, err := computeTargetClusters(rel, clusterList, requests)
+
+	requirements := rel.Spec.Environment.ClusterRequirements
+	if len(requirements.Regions) == 0 || validateClusterRequirements(requirements) != nil {
+		return selectedClusters, nil, err
+	}
+
+	app, appErr := releaseutil.ApplicationNameForRelease(rel)
+	selector, selectorErr := clusterSelector(requirements)
+	if appErr != nil || selectorErr != nil {
+		return selectedClusters, nil, err
+	}
+
+	selected := make(map[string]struct{}, len(selectedClusters))
+	for _, cluster := range selectedClusters {
+		selected[cluster.Name] = struct{}{}
+	}
+
+	regions := make(map[string]shipper.RegionRequirement, len(requirements.Regions))
+	for _, region := range requirements.Regions {
+		regions[region.Name] = region
+	}
+
+	// zonesTaken tracks, for every region spread across zones, the most
+	// preferred capable cluster in each zone, which is the only one of
+	// them that can be picked.
+	zonesTaken := map[string]map[string]string{}
+
+	prefList := buildPrefList(app, clusterList, selector)
+	required := clusterResourceRequests(rel, prefList, requests)
+	prefList = preferClustersWithHeadroom(prefList, required)
+	decisions := make([]shipper.ClusterSchedulingDecision, 0, len(clusterList))
+	inPrefList := make(map[string]struct{}, len(prefList))
+	for i, cluster := range prefList {
+		inPrefList[cluster.Name] = struct{}{}
+
+		decision := shipper.ClusterSchedulingDecision{
+			Name:       cluster.Name,
+			Region:     cluster.Spec.Region,
+			Preference: int32(i + 1),
+		}
+
+		region, inRegion := regions[cluster.Spec.Region]
+		missing := missingCapabilities(cluster, requirements.Capabilities)
+		zone := cluster.Spec.Zone
+		fits := headroom.Fits(cluster.Status.Headroom, required[cluster.Name])
+
+		if inRegion && region.SpreadAcrossZones && zonesTaken[region.Name] == nil {
+			zonesTaken[region.Name] = map[string]string{}
+		}
+
+		switch {
+		case isSelected(selected, cluster.Name):
+			decision.Selected = true
+			decision.Reason = shipper.ClusterSchedulingSelected
+			if !fits {
+				decision.Message = fmt.Sprintf("picked for lack of clusters with room for %s", headroom.String(required[cluster.Name]))
+			}
+		case cluster.Spec.Scheduler.Unschedulable:
+			decision.Reason = shipper.ClusterSchedulingUnschedulable
+			decision.Message = "cluster is marked unschedulable"
+		case !clusterSchedulable(rel, cluster):
+			decision.Reason = shipper.ClusterSchedulingOutOfService
+			decision.Message = "cluster is out of service, and the release is rescheduled away from such clusters"
+		case !inRegion:
+			decision.Reason = shipper.ClusterSchedulingWrongRegion
+			decision.Message = fmt.Sprintf("region %q is not one of the required regions", cluster.Spec.Region)
+		case len(missing) > 0:
+			decision.Reason = shipper.ClusterSchedulingMissingCapabilities
+			decision.Message = fmt.Sprintf("cluster is missing capabilities [%s]", strings.Join(missing, ","))
+		case region.SpreadAcrossZones && zone == "":
+			decision.Reason = shipper.ClusterSchedulingNoZone
+			decision.Message = fmt.Sprintf("cluster has no zone, and region %q is spread across zones", region.Name)
+		case region.SpreadAcrossZones && zonesTaken[region.Name][zone] != "":
+			decision.Reason = shipper.ClusterSchedulingZoneTaken
+			decision.Message = fmt.Sprintf("cluster %q is preferred in zone %q", zonesTaken[region.Name][zone], zone)
+		case !fits:
+			decision.Reason = shipper.ClusterSchedulingInsufficientHeadroom
+			decision.Message = fmt.Sprintf("cluster has %s left, but the release needs %s",
+				headroom.String(cluster.Status.Headroom.Available), headroom.String(required[cluster.Name]))
+		case err != nil:
+			decision.Reason = shipper.ClusterSchedulingRegionUnsatisfiable
+			decision.Message = fmt.Sprintf("no clusters were picked: %s", err)
+		default:
+			decision.Reason = shipper.ClusterSchedulingLowerPreference
+			decision.Message = fmt.Sprintf("region %q only needs %d clusters, and more preferred ones were picked", region.Name, regionReplicas(region))
+		}
+
+		if inRegion && region.SpreadAcrossZones && zone != "" && zonesTaken[region.Name][zone] == "" &&
+			clusterSchedulable(rel, cluster) && len(missing) == 0 {
+			zonesTaken[region.Name][zone] = cluster.Name
+		}
+
+		decisions = append(decisions, decision)
+	}
+
+	mismatched := make([]*shipper.Cluster, 0, len(clusterList)-len(prefList))
+	for _, cluster := range clusterList {
+		if _, ok := inPrefList[cluster.Name]; !ok {
+			mismatched = append(mismatched, cluster)
+		}
+	}
+	sort.Slice(mismatched, func(i, j int) bool {
+		return mismatched[i].Name < mismatched[j].Name
+	})
+
+	for _, cluster := range mismatched {
+		decisions = append(decisions, shipper.ClusterSchedulingDecision{
+			Name:    cluster.Name,
+			Region:  cluster.Spec.Region,
+			Reason:  shipper.ClusterSchedulingSelectorMismatch,
+			Message: fmt.Sprintf("cluster labels don't match selector %q", selectorString(selector)),
+		})
+	}
+
+	if len(decisions) > shipper.ReleaseSchedulingDecisionsLimit {
+		decisions = decisions[:shipper.ReleaseSchedulingDecisionsLimit]
+	}
+
+	return selectedClusters, decisions, err
+}
+
+func isSelected(selected map[string]struct{}, name string) bool {
+	_, ok := selected[name]
+	return ok
+}
+
+func missingCapabilities(cluster *shipper.Cluster, required []string) []string {
+	provided := stringSet(cluster.Spec.Capabilities)
+
+	missing := []string{}
+	for _, capability := range required {
+		if _, ok := provided[capability]; !ok {
+			missing = append(missing, capability)
+		}
+	}
+
+	return missing
+}
+
+func regionReplicas(region shipper.RegionRequirement) int32 {
+	if region.Replicas == nil {
+		return 1
+	}
+
+	return *region.Replicas
+}
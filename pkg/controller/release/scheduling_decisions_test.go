@@ -0,0 +1,142 @@
+package release
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+)
+
+func TestExplainTargetClusters(t *testing.T) {
+	specs := []shipper.ClusterSpec{
+		{Region: "a", Zone: "z1", Capabilities: []string{"gpu"}},
+		{Region: "a", Zone: "z1", Capabilities: []string{"gpu"}},
+		{Region: "a", Capabilities: []string{"gpu"}},
+		{Region: "a", Zone: "z2"},
+		{Region: "b", Zone: "z1", Capabilities: []string{"gpu"}},
+		{Region: "a", Zone: "z3", Capabilities: []string{"gpu"}, Scheduler: shipper.ClusterSchedulerSettings{Unschedulable: true}},
+		{Region: "a", Zone: "z4", Capabilities: []string{"gpu"}},
+	}
+
+	clusterList := make([]*shipper.Cluster, 0, len(specs))
+	for i, spec := range specs {
+		clusterList = append(clusterList, generateClusterForTestCase(i, spec))
+	}
+	clusterList[6].Labels = map[string]string{"environment": "canary"}
+
+	rel := generateReleaseForTestCase(shipper.ClusterRequirements{
+		Regions:      []shipper.RegionRequirement{{Name: "a", Replicas: pint32(1), SpreadAcrossZones: true}},
+		Capabilities: []string{"gpu"},
+		Selector: &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "environment", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"canary"}},
+			},
+		},
+	})
+
+	selected, decisions, err := explainTargetClusters(rel, clusterList, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(selected) != 1 {
+		t.Fatalf("expected 1 cluster to be selected, got %d", len(selected))
+	}
+	if len(decisions) != len(clusterList) {
+		t.Fatalf("expected a decision for each of %d clusters, got %d", len(clusterList), len(decisions))
+	}
+
+	reasons := map[string]shipper.ClusterSchedulingReason{}
+	for _, decision := range decisions {
+		reasons[decision.Name] = decision.Reason
+
+		if decision.Selected != (decision.Reason == shipper.ClusterSchedulingSelected) {
+			t.Errorf("expected cluster %q to be selected only if its reason is %q, got %q",
+				decision.Name, shipper.ClusterSchedulingSelected, decision.Reason)
+		}
+	}
+
+	// cluster-0 and cluster-1 share a zone, so whichever is preferred
+	// gets picked, and the other one is left out.
+	zoneReasons := []shipper.ClusterSchedulingReason{reasons["cluster-0"], reasons["cluster-1"]}
+	if !(zoneReasons[0] == shipper.ClusterSchedulingSelected && zoneReasons[1] == shipper.ClusterSchedulingZoneTaken) &&
+		!(zoneReasons[0] == shipper.ClusterSchedulingZoneTaken && zoneReasons[1] == shipper.ClusterSchedulingSelected) {
+		t.Errorf("expected one of cluster-0 and cluster-1 to be selected and the other to have its zone taken, got %v", zoneReasons)
+	}
+
+	expected := map[string]shipper.ClusterSchedulingReason{
+		"cluster-2": shipper.ClusterSchedulingNoZone,
+		"cluster-3": shipper.ClusterSchedulingMissingCapabilities,
+		"cluster-4": shipper.ClusterSchedulingWrongRegion,
+		"cluster-5": shipper.ClusterSchedulingUnschedulable,
+		"cluster-6": shipper.ClusterSchedulingSelectorMismatch,
+	}
+	for name, reason := range expected {
+		if reasons[name] != reason {
+			t.Errorf("expected cluster %q to have reason %q, got %q", name, reason, reasons[name])
+		}
+	}
+
+	last := decisions[len(decisions)-1]
+	if last.Name != "cluster-6" || last.Preference != 0 {
+		t.Errorf("expected clusters the selector doesn't match to come last with no preference, got %+v", last)
+	}
+}
+
+func TestExplainTargetClustersLowerPreference(t *testing.T) {
+	clusterList := []*shipper.Cluster{
+		generateClusterForTestCase(0, shipper.ClusterSpec{Region: "a"}),
+		generateClusterForTestCase(1, shipper.ClusterSpec{Region: "a"}),
+	}
+
+	rel := generateReleaseForTestCase(shipper.ClusterRequirements{
+		Regions: []shipper.RegionRequirement{{Name: "a", Replicas: pint32(1)}},
+	})
+
+	_, decisions, err := explainTargetClusters(rel, clusterList, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(decisions) != 2 {
+		t.Fatalf("expected 2 decisions, got %d", len(decisions))
+	}
+	if decisions[0].Reason != shipper.ClusterSchedulingSelected || decisions[0].Preference != 1 {
+		t.Errorf("expected the most preferred cluster to be selected, got %+v", decisions[0])
+	}
+	if decisions[1].Reason != shipper.ClusterSchedulingLowerPreference || decisions[1].Preference != 2 {
+		t.Errorf("expected the other cluster to be left out for lower preference, got %+v", decisions[1])
+	}
+}
+
+func TestExplainTargetClustersUnsatisfiable(t *testing.T) {
+	clusterList := []*shipper.Cluster{
+		generateClusterForTestCase(0, shipper.ClusterSpec{Region: "a"}),
+		generateClusterForTestCase(1, shipper.ClusterSpec{Region: "a", Capabilities: []string{"gpu"}}),
+	}
+
+	rel := generateReleaseForTestCase(shipper.ClusterRequirements{
+		Regions:      []shipper.RegionRequirement{{Name: "a", Replicas: pint32(2)}},
+		Capabilities: []string{"gpu"},
+	})
+
+	selected, decisions, err := explainTargetClusters(rel, clusterList, nil)
+	if err == nil {
+		t.Fatalf("expected an error, got clusters %v", selected)
+	}
+
+	reasons := map[string]shipper.ClusterSchedulingReason{}
+	for _, decision := range decisions {
+		reasons[decision.Name] = decision.Reason
+	}
+
+	expected := map[string]shipper.ClusterSchedulingReason{
+		"cluster-0": shipper.ClusterSchedulingMissingCapabilities,
+		"cluster-1": shipper.ClusterSchedulingRegionUnsatisfiable,
+	}
+	for name, reason := range expected {
+		if reasons[name] != reason {
+			t.Errorf("expected cluster %q to have reason %q, got %q", name, reason, reasons[name])
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package release
+
+import (
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+	releaseutil "github.com/bookingcom/shipper/pkg/util/release"
+	strategyutil "github.com/bookingcom/shipper/pkg/util/strategy"
+)
+
+// advanceStep moves the release on to the next strategy step if the step it
+// targets has been achieved, that step's pause has elapsed, and the next step
+// has been approved if it requires approval. Steps with neither a pause nor
+// a next step requiring approval are left for users to move on from. If the
+// pause is still running, it returns how long is left, so the caller can
+// look at the release again once it is over.
+func (c *Controller) advanceStep(rel *shipper.Release, now time.Time) time.Duration {
+	strategy := rel.Spec.Environment.Strategy
+	targetStep := rel.Spec.TargetStep
+	achievedStep := rel.Status.AchievedStep
+
+	if achievedStep == nil || achievedStep.Step != targetStep {
+		return 0
+	}
+
+	if int(targetStep) >= len(strategy.Steps)-1 {
+		return 0
+	}
+
+	nextStep := targetStep + 1
+	pause := strategy.Steps[targetStep].Pause
+	requiresApproval := strategyutil.StepRequiresApproval(strategy, nextStep)
+	if pause == nil && !requiresApproval {
+		return 0
+	}
+
+	// Only the contender moves through the strategy: incumbents follow
+	// whatever step their successor is at.
+	if isHead, err := c.releaseIsHead(rel); err != nil || !isHead {
+		return 0
+	}
+
+	if pause != nil {
+		if achievedStep.AchievedTime.IsZero() {
+			// Releases that achieved their step before pauses
+			// were introduced have no record of when that
+			// happened, so the pause starts now.
+			achievedStep.AchievedTime.Time = now
+		}
+
+		remaining := achievedStep.AchievedTime.Add(pause.Duration).Sub(now)
+		if remaining > 0 {
+			return remaining
+		}
+	}
+
+	if requiresApproval {
+		// The approval informer brings the release back here once
+		// an approval shows up.
+		approval, err := strategyutil.FindStepApproval(c.stepApprovalLister, rel, nextStep)
+		if err != nil || approval == nil {
+			return 0
+		}
+
+		rel.Spec.TargetStep = nextStep
+		recordTargetStepChange(rel, approval.Spec.ApprovedBy, fmt.Sprintf("approved in %q", approval.Name), now)
+		c.recorder.Eventf(
+			rel,
+			corev1.EventTypeNormal,
+			"StepAdvanced",
+			"step [%d] was approved by %q in %q, advancing to it",
+			nextStep,
+			approval.Spec.ApprovedBy,
+			approval.Name,
+		)
+
+		return 0
+	}
+
+	rel.Spec.TargetStep = nextStep
+	recordTargetStepChange(rel, AgentName, fmt.Sprintf("step [%d] was paused for %s", targetStep, pause.Duration), now)
+	c.recorder.Eventf(
+		rel,
+		corev1.EventTypeNormal,
+		"StepAdvanced",
+		"step [%d] was paused for %s, advancing to step [%d]",
+		targetStep,
+		pause.Duration,
+		rel.Spec.TargetStep,
+	)
+
+	return 0
+}
+
+// releaseIsHead returns true if the release is the latest one of its
+// application.
+func (c *Controller) releaseIsHead(rel *shipper.Release) (bool, error) {
+	releases, err := c.applicationReleases(rel)
+	if err != nil {
+		return false, err
+	}
+	_, succ, err := releaseutil.GetSiblingReleases(rel, releases)
+	if err != nil {
+		return false, err
+	}
+	return succ == nil, nil
+}
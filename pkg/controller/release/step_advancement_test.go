@@ -0,0 +1,217 @@
+package release
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+	shipperfake "github.com/bookingcom/shipper/pkg/client/clientset/versioned/fake"
+	shipperinformers "github.com/bookingcom/shipper/pkg/client/informers/externalversions"
+)
+
+func TestAdvanceStepAfterPause(t *testing.T) {
+	now := time.Now()
+	pause := metav1.Duration{Duration: 10 * time.Minute}
+
+	tests := []struct {
+		name               string
+		targetStep         int32
+		achievedStep       int32
+		achievedAgo        time.Duration
+		expectedTargetStep int32
+		expectedRemaining  time.Duration
+	}{
+		{
+			name:               "pause still running",
+			targetStep:         0,
+			achievedStep:       0,
+			achievedAgo:        4 * time.Minute,
+			expectedTargetStep: 0,
+			expectedRemaining:  6 * time.Minute,
+		},
+		{
+			name:               "pause elapsed",
+			targetStep:         0,
+			achievedStep:       0,
+			achievedAgo:        11 * time.Minute,
+			expectedTargetStep: 1,
+		},
+		{
+			name:               "target step not achieved yet",
+			targetStep:         1,
+			achievedStep:       0,
+			achievedAgo:        11 * time.Minute,
+			expectedTargetStep: 1,
+		},
+		{
+			name:               "last step never advances",
+			targetStep:         2,
+			achievedStep:       2,
+			achievedAgo:        11 * time.Minute,
+			expectedTargetStep: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		namespace := "test-namespace"
+		app := buildApplication(namespace, "test-app")
+		cluster := buildCluster("minikube")
+
+		f := newFixture(t, app.DeepCopy(), cluster.DeepCopy())
+		contender := f.buildContender(namespace, "test-contender", 10)
+
+		strategy := vanguard.DeepCopy()
+		for i := range strategy.Steps {
+			strategy.Steps[i].Pause = &pause
+		}
+
+		rel := contender.release
+		rel.Spec.Environment.Strategy = strategy
+		rel.Spec.TargetStep = tt.targetStep
+		rel.Status.AchievedStep = &shipper.AchievedStep{
+			Step:         tt.achievedStep,
+			Name:         strategy.Steps[tt.achievedStep].Name,
+			AchievedTime: metav1.NewTime(now.Add(-tt.achievedAgo)),
+		}
+		f.addObjects(rel.DeepCopy())
+
+		c := newControllerForObjects(t, f.objects...)
+
+		remaining := c.advanceStep(rel, now)
+		if remaining != tt.expectedRemaining {
+			t.Errorf("%s: expected %s of pause remaining, got %s",
+				tt.name, tt.expectedRemaining, remaining)
+		}
+		if rel.Spec.TargetStep != tt.expectedTargetStep {
+			t.Errorf("%s: expected target step %d, got %d",
+				tt.name, tt.expectedTargetStep, rel.Spec.TargetStep)
+		}
+	}
+}
+
+func newControllerForObjects(t *testing.T, objects ...runtime.Object) *Controller {
+	clientset := shipperfake.NewSimpleClientset(objects...)
+	informerFactory := shipperinformers.NewSharedInformerFactory(clientset, 0)
+
+	c := NewController(clientset, informerFactory, localFetchChart, record.NewFakeRecorder(42))
+
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+
+	informerFactory.Start(stopCh)
+	informerFactory.WaitForCacheSync(stopCh)
+
+	return c
+}
+
+func TestAdvanceStepAfterApproval(t *testing.T) {
+	tests := []struct {
+		name               string
+		approvedStep       *int32
+		expectedTargetStep int32
+	}{
+		{
+			name:               "next step not approved",
+			expectedTargetStep: 0,
+		},
+		{
+			name:               "another step approved",
+			approvedStep:       func() *int32 { s := int32(2); return &s }(),
+			expectedTargetStep: 0,
+		},
+		{
+			name:               "next step approved",
+			approvedStep:       func() *int32 { s := int32(1); return &s }(),
+			expectedTargetStep: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		namespace := "test-namespace"
+		app := buildApplication(namespace, "test-app")
+		cluster := buildCluster("minikube")
+
+		f := newFixture(t, app.DeepCopy(), cluster.DeepCopy())
+		contender := f.buildContender(namespace, "test-contender", 10)
+
+		strategy := vanguard.DeepCopy()
+		strategy.Steps[1].RequiresApproval = true
+		strategy.Steps[2].RequiresApproval = true
+
+		rel := contender.release
+		rel.Spec.Environment.Strategy = strategy
+		rel.Spec.TargetStep = 0
+		rel.Status.AchievedStep = &shipper.AchievedStep{
+			Step:         0,
+			Name:         strategy.Steps[0].Name,
+			AchievedTime: metav1.Now(),
+		}
+		f.addObjects(rel.DeepCopy())
+
+		if tt.approvedStep != nil {
+			f.addObjects(&shipper.StepApproval{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-approval",
+					Namespace: namespace,
+				},
+				Spec: shipper.StepApprovalSpec{
+					Release:    rel.Name,
+					Step:       *tt.approvedStep,
+					ApprovedBy: "jdoe",
+				},
+			})
+		}
+
+		c := newControllerForObjects(t, f.objects...)
+
+		if remaining := c.advanceStep(rel, time.Now()); remaining != 0 {
+			t.Errorf("%s: expected nothing to wait for, got %s", tt.name, remaining)
+		}
+		if rel.Spec.TargetStep != tt.expectedTargetStep {
+			t.Errorf("%s: expected target step %d, got %d",
+				tt.name, tt.expectedTargetStep, rel.Spec.TargetStep)
+		}
+	}
+}
+
+func TestUnapprovedStepIsNotExecuted(t *testing.T) {
+	for _, approved := range []bool{false, true} {
+		namespace := "test-namespace"
+		app := buildApplication(namespace, "test-app")
+		cluster := buildCluster("minikube")
+		f := newFixture(t, app.DeepCopy(), cluster.DeepCopy())
+
+		totalReplicaCount := int32(10)
+		incumbent := f.buildIncumbent(namespace, "test-incumbent", totalReplicaCount)
+		contender := f.buildContender(namespace, "test-contender", totalReplicaCount)
+
+		strategy := vanguard.DeepCopy()
+		strategy.Steps[1].RequiresApproval = true
+		contender.release.Spec.Environment.Strategy = strategy
+		contender.release.Spec.TargetStep = 1
+
+		executor := NewStrategyExecutor(contender, incumbent, nil, nil, true)
+		executor.stepApproved = approved
+
+		_, patches, _, err := executor.Execute()
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		var capacityPatched bool
+		for _, patch := range patches {
+			if _, ok := patch.(*CapacityTargetSpecPatch); ok {
+				capacityPatched = true
+			}
+		}
+
+		if capacityPatched != approved {
+			t.Errorf("approved %t: expected contender capacity patch to be %t, got %t",
+				approved, approved, capacityPatched)
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package release
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+	strategyutil "github.com/bookingcom/shipper/pkg/util/strategy"
+)
+
+// clusterStepValues computes the capacity or traffic value for each cluster
+// in targetClusters. The strategy and the steps are taken from the head
+// release (the one defining the desired state); clusters the head release is
+// not scheduled on fall back to the release-wide target step.
+func (e *StrategyExecutor) clusterStepValues(
+	head *releaseInfo,
+	targetClusters []string,
+	isContender bool,
+	pick func(shipper.RolloutStrategyStep) shipper.RolloutStrategyStepValue,
+) map[string]int32 {
+	strategy := head.release.Spec.Environment.Strategy
+	targetStep := head.release.Spec.TargetStep
+
+	var steps map[string]int32
+	if strategyutil.HasClusterSteps(strategy) {
+		steps = strategyutil.ClusterStepIndices(strategy, targetStep, getReleaseClusters(head.release), e.clusterRegions)
+	}
+
+	values := make(map[string]int32, len(targetClusters))
+	for _, cluster := range targetClusters {
+		if _, ok := e.drainingClusters[cluster]; ok {
+			values[cluster] = 0
+			continue
+		}
+
+		step, ok := steps[cluster]
+		if !ok {
+			step = targetStep
+		}
+		values[cluster] = strategyutil.StepValue(strategy, step, isContender, pick)
+	}
+	return values
+}
+
+// clusterStepReplicas computes the absolute number of replicas the capacity
+// step of each cluster in targetClusters asks for, the same way
+// clusterStepValues computes percentages. Clusters at steps asking for a
+// percentage are left out.
+func (e *StrategyExecutor) clusterStepReplicas(
+	head *releaseInfo,
+	targetClusters []string,
+	isContender bool,
+) map[string]int32 {
+	strategy := head.release.Spec.Environment.Strategy
+	targetStep := head.release.Spec.TargetStep
+
+	var steps map[string]int32
+	if strategyutil.HasClusterSteps(strategy) {
+		steps = strategyutil.ClusterStepIndices(strategy, targetStep, getReleaseClusters(head.release), e.clusterRegions)
+	}
+
+	values := make(map[string]int32, len(targetClusters))
+	for _, cluster := range targetClusters {
+		if _, ok := e.drainingClusters[cluster]; ok {
+			continue
+		}
+
+		step, ok := steps[cluster]
+		if !ok {
+			step = targetStep
+		}
+		if replicas := strategyutil.StepReplicas(strategy, step, isContender); replicas != nil {
+			values[cluster] = *replicas
+		}
+	}
+	return values
+}
+
+// buildClusterStrategyStatus reports the step each of the head release's
+// clusters is at, and whether the contender has achieved it there. It
+// returns nil for strategies without cluster-scoped steps.
+func (e *StrategyExecutor) buildClusterStrategyStatus() []shipper.ClusterStrategyStatus {
+	head := e.curr
+	strategy := head.release.Spec.Environment.Strategy
+	if e.succ != nil || !strategyutil.HasClusterSteps(strategy) {
+		return nil
+	}
+
+	clusters := getReleaseClusters(head.release)
+	steps := strategyutil.ClusterStepIndices(strategy, head.release.Spec.TargetStep, clusters, e.clusterRegions)
+
+	statuses := make([]shipper.ClusterStrategyStatus, 0, len(clusters))
+	for _, cluster := range clusters {
+		step := steps[cluster]
+		status := shipper.ClusterStrategyStatus{
+			Name: cluster,
+			Step: step,
+			Achieved: clusterAchievedStep(
+				head,
+				cluster,
+				strategyutil.StepValue(strategy, step, true, strategyutil.PickCapacity),
+				strategyutil.StepReplicas(strategy, step, true),
+				uint32(strategyutil.StepValue(strategy, step, true, strategyutil.PickTraffic)),
+			),
+		}
+		if step >= 0 {
+			status.StepName = strategy.Steps[step].Name
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses
+}
+
+// clusterAchievedStep checks whether a release's capacity and traffic target
+// specs for a cluster are at the given values and report as ready. When
+// replicas is set, the capacity target has to ask for that many replicas
+// instead of the capacity percentage.
+func clusterAchievedStep(relinfo *releaseInfo, cluster string, capacity int32, replicas *int32, traffic uint32) bool {
+	ct, tt := relinfo.capacityTarget, relinfo.trafficTarget
+	if ct == nil || tt == nil {
+		return false
+	}
+	if ct.Status.ObservedGeneration < ct.Generation || tt.Status.ObservedGeneration < tt.Generation {
+		return false
+	}
+
+	capacityMatches := false
+	for _, spec := range ct.Spec.Clusters {
+		if spec.Name == cluster && capacityMatchesStep(spec, capacity, replicas) {
+			capacityMatches = true
+			break
+		}
+	}
+
+	trafficMatches := false
+	for _, spec := range tt.Spec.Clusters {
+		if spec.Name == cluster && spec.Weight == traffic {
+			trafficMatches = true
+			break
+		}
+	}
+
+	if !capacityMatches || !trafficMatches {
+		return false
+	}
+
+	capacityReady := false
+	for _, status := range ct.Status.Clusters {
+		if status.Name != cluster {
+			continue
+		}
+		for _, c := range status.Conditions {
+			if c.Type == shipper.ClusterConditionTypeReady {
+				capacityReady = c.Status == corev1.ConditionTrue
+			}
+		}
+	}
+
+	trafficReady := false
+	for _, status := range tt.Status.Clusters {
+		if status == nil || status.Name != cluster {
+			continue
+		}
+		for _, c := range status.Conditions {
+			if c.Type == shipper.ClusterConditionTypeReady {
+				trafficReady = c.Status == corev1.ConditionTrue
+			}
+		}
+	}
+
+	return capacityReady && trafficReady
+}
+
+// capacityMatchesStep tells whether a cluster's capacity target spec asks
+// for the capacity of a step: either the given absolute number of
+// replicas, or when that's nil, the given percentage.
+func capacityMatchesStep(spec shipper.ClusterCapacityTarget, capacity int32, replicas *int32) bool {
+	if replicas != nil {
+		return spec.Replicas != nil && *spec.Replicas == *replicas
+	}
+
+	return spec.Replicas == nil && spec.Percent == capacity
+}
@@ -0,0 +1,163 @@
+package release
+
+import (
+	"testing"
+
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+	shippertesting "github.com/bookingcom/shipper/pkg/testing"
+)
+
+var clusterByCluster = shipper.RolloutStrategy{
+	Steps: []shipper.RolloutStrategyStep{
+		{
+			Name:     "staging",
+			Capacity: shipper.RolloutStrategyStepValue{Incumbent: 100, Contender: 1},
+			Traffic:  shipper.RolloutStrategyStepValue{Incumbent: 100, Contender: 0},
+		},
+		{
+			Name:     "us-east full on",
+			Capacity: shipper.RolloutStrategyStepValue{Incumbent: 0, Contender: 100},
+			Traffic:  shipper.RolloutStrategyStepValue{Incumbent: 0, Contender: 100},
+			Clusters: &shipper.RolloutStrategyStepClusters{
+				Names: []string{"kube-us-east1-a"},
+			},
+		},
+		{
+			Name:     "eu-west full on",
+			Capacity: shipper.RolloutStrategyStepValue{Incumbent: 0, Contender: 100},
+			Traffic:  shipper.RolloutStrategyStepValue{Incumbent: 0, Contender: 100},
+			Clusters: &shipper.RolloutStrategyStepClusters{
+				Regions: []string{"eu-west"},
+			},
+		},
+	},
+}
+
+func TestContenderCapacityShouldIncreaseClusterByCluster(t *testing.T) {
+	namespace := "test-namespace"
+	app := buildApplication(namespace, "test-app")
+	usEast := buildCluster("kube-us-east1-a")
+	euWest := buildCluster("kube-eu-west2-b")
+	euWest.Spec.Region = "eu-west"
+
+	f := newFixture(t, app.DeepCopy(), usEast.DeepCopy(), euWest.DeepCopy())
+
+	totalReplicaCount := int32(10)
+	contender := f.buildContender(namespace, "test-contender", totalReplicaCount)
+	incumbent := f.buildIncumbent(namespace, "test-incumbent", totalReplicaCount)
+
+	strategy := clusterByCluster.DeepCopy()
+	contender.release.Spec.Environment.Strategy = strategy
+	contender.release.Spec.TargetStep = 1
+	for i := range contender.capacityTarget.Spec.Clusters {
+		contender.capacityTarget.Spec.Clusters[i].Percent = 1
+	}
+
+	regions := map[string]string{
+		usEast.Name: shippertesting.TestRegion,
+		euWest.Name: euWest.Spec.Region,
+	}
+
+	executor := NewStrategyExecutor(contender, incumbent, nil, regions, true)
+	complete, patches, _, err := executor.Execute()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if complete {
+		t.Fatalf("expected strategy to be incomplete")
+	}
+
+	var ctPatch *CapacityTargetSpecPatch
+	for _, patch := range patches {
+		if p, ok := patch.(*CapacityTargetSpecPatch); ok {
+			ctPatch = p
+		}
+	}
+	if ctPatch == nil {
+		t.Fatalf("expected a capacity target patch, got %v", patches)
+	}
+
+	expected := map[string]int32{
+		usEast.Name: 100,
+		euWest.Name: 1,
+	}
+	if len(ctPatch.NewSpec.Clusters) != len(expected) {
+		t.Fatalf("expected capacity target patch to carry %d clusters, got %d",
+			len(expected), len(ctPatch.NewSpec.Clusters))
+	}
+	for _, spec := range ctPatch.NewSpec.Clusters {
+		if spec.Percent != expected[spec.Name] {
+			t.Errorf("expected cluster %q to be at %d%% capacity, got %d%%",
+				spec.Name, expected[spec.Name], spec.Percent)
+		}
+	}
+
+	statuses := executor.buildClusterStrategyStatus()
+	if len(statuses) != 2 {
+		t.Fatalf("expected per-cluster strategy status for 2 clusters, got %d", len(statuses))
+	}
+	for _, status := range statuses {
+		if status.Name == usEast.Name && status.Step != 1 {
+			t.Errorf("expected cluster %q to be at step 1, got %d", status.Name, status.Step)
+		}
+		if status.Name == euWest.Name && status.Step != 0 {
+			t.Errorf("expected cluster %q to be at step 0, got %d", status.Name, status.Step)
+		}
+	}
+}
+
+// TestContenderCapacityReplicas verifies that steps asking for an absolute
+// number of replicas get the capacity target to ask for that many of them,
+// whatever the size of the release.
+func TestContenderCapacityReplicas(t *testing.T) {
+	namespace := "test-namespace"
+	app := buildApplication(namespace, "test-app")
+	cluster := buildCluster("kube-us-east1-a")
+
+	f := newFixture(t, app.DeepCopy(), cluster.DeepCopy())
+
+	totalReplicaCount := int32(400)
+	contender := f.buildContender(namespace, "test-contender", totalReplicaCount)
+	incumbent := f.buildIncumbent(namespace, "test-incumbent", totalReplicaCount)
+
+	replicas := int32(1)
+	strategy := clusterByCluster.DeepCopy()
+	strategy.Steps[0].Capacity = shipper.RolloutStrategyStepValue{Incumbent: 100, ContenderReplicas: &replicas}
+	contender.release.Spec.Environment.Strategy = strategy
+	contender.release.Spec.TargetStep = 0
+
+	regions := map[string]string{cluster.Name: shippertesting.TestRegion}
+
+	executor := NewStrategyExecutor(contender, incumbent, nil, regions, true)
+	_, patches, _, err := executor.Execute()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var ctPatch *CapacityTargetSpecPatch
+	for _, patch := range patches {
+		if p, ok := patch.(*CapacityTargetSpecPatch); ok {
+			ctPatch = p
+		}
+	}
+	if ctPatch == nil {
+		t.Fatalf("expected a capacity target patch, got %v", patches)
+	}
+	if len(ctPatch.NewSpec.Clusters) != 1 {
+		t.Fatalf("expected capacity target patch to carry 1 cluster, got %d", len(ctPatch.NewSpec.Clusters))
+	}
+
+	for _, spec := range ctPatch.NewSpec.Clusters {
+		if spec.Replicas == nil || *spec.Replicas != replicas {
+			t.Errorf("expected cluster %q to ask for %d replicas, got %v", spec.Name, replicas, spec.Replicas)
+		}
+		if spec.Percent != 1 {
+			t.Errorf("expected cluster %q to be at 1%% capacity, got %d%%", spec.Name, spec.Percent)
+		}
+	}
+
+	contender.capacityTarget.Spec = *ctPatch.NewSpec
+	if achieved, newSpec, _ := checkCapacity(contender.capacityTarget, map[string]int32{cluster.Name: 0}, map[string]int32{cluster.Name: replicas}); newSpec != nil {
+		t.Errorf("expected capacity target asking for %d replicas to need no more changes, got %v (achieved: %t)", replicas, newSpec, achieved)
+	}
+}
@@ -11,22 +11,82 @@ import (
 	"github.com/bookingcom/shipper/pkg/controller"
 	shippererrors "github.com/bookingcom/shipper/pkg/errors"
 	"github.com/bookingcom/shipper/pkg/util/conditions"
+	strategyutil "github.com/bookingcom/shipper/pkg/util/strategy"
 )
 
 type StrategyExecutor struct {
 	curr, prev, succ *releaseInfo
 	hasIncumbent     bool
+
+	// clusterRegions maps cluster names to their regions, so strategy
+	// steps can select clusters by region.
+	clusterRegions map[string]string
+
+	// plugins are the pipeline steps registered with
+	// RegisterPipelineStep.
+	plugins []PipelineStepPlugin
+
+	// stepApproved tells whether there is a StepApproval for the step the
+	// release targets. It only matters for steps requiring approval.
+	stepApproved bool
+
+	// drainingClusters are the clusters the release is being rescheduled
+	// away from, once it's safe to drain them. The release gets no
+	// capacity and no traffic there.
+	drainingClusters map[string]struct{}
 }
 
-func NewStrategyExecutor(curr, prev, succ *releaseInfo, hasIncumbent bool) *StrategyExecutor {
+func NewStrategyExecutor(curr, prev, succ *releaseInfo, clusterRegions map[string]string, hasIncumbent bool) *StrategyExecutor {
 	return &StrategyExecutor{
-		curr:         curr,
-		prev:         prev,
-		succ:         succ,
-		hasIncumbent: hasIncumbent,
+		curr:           curr,
+		prev:           prev,
+		succ:           succ,
+		hasIncumbent:   hasIncumbent,
+		clusterRegions: clusterRegions,
+		plugins:        registeredPipelineSteps(),
 	}
 }
 
+// Release returns the release the strategy is being executed for.
+func (e *StrategyExecutor) Release() *shipper.Release {
+	return e.curr.release
+}
+
+// Incumbent returns the release before the one the strategy is being
+// executed for, or nil if there isn't one.
+func (e *StrategyExecutor) Incumbent() *shipper.Release {
+	if e.prev == nil {
+		return nil
+	}
+	return e.prev.release
+}
+
+// StrategyStatusPatch returns a patch storing the given strategy conditions
+// in the release's status, for pipeline steps that break the pipeline.
+func (e *StrategyExecutor) StrategyStatusPatch(cond conditions.StrategyConditionsMap) StrategyPatch {
+	strategy := e.curr.release.Spec.Environment.Strategy
+	targetStep := e.curr.release.Spec.TargetStep
+
+	return buildContenderStrategyConditionsPatch(
+		e.curr.release.Name,
+		cond,
+		targetStep,
+		int(targetStep) == len(strategy.Steps)-1,
+		e.hasIncumbent,
+		e.buildClusterStrategyStatus(),
+		e.pluginConditionTypes()...,
+	)
+}
+
+// pluginConditionTypes returns the strategy conditions set by plugins.
+func (e *StrategyExecutor) pluginConditionTypes() []shipper.StrategyConditionType {
+	var condTypes []shipper.StrategyConditionType
+	for _, plugin := range e.plugins {
+		condTypes = append(condTypes, plugin.ConditionTypes()...)
+	}
+	return condTypes
+}
+
 type PipelineContinuation bool
 
 const (
@@ -85,6 +145,8 @@ func genInstallationEnforcer(curr, succ *releaseInfo) PipelineStep {
 				targetStep,
 				isLastStep,
 				e.hasIncumbent,
+				e.buildClusterStrategyStatus(),
+				e.pluginConditionTypes()...,
 			)
 			if relPatch.Alters(e.curr.release) {
 				patches = append(patches, relPatch)
@@ -107,30 +169,34 @@ func genInstallationEnforcer(curr, succ *releaseInfo) PipelineStep {
 
 func genCapacityEnforcer(curr, succ *releaseInfo) PipelineStep {
 	return func(e *StrategyExecutor, cond conditions.StrategyConditionsMap) (PipelineContinuation, []StrategyPatch, []ReleaseStrategyStateTransition) {
-		var targetStep, capacityWeight int32
+		var targetStep int32
 		var strategy *shipper.RolloutStrategy
-		var strategyStep shipper.RolloutStrategyStep
+		var head *releaseInfo
 		var condType shipper.StrategyConditionType
 
 		isHead := succ == nil
 
 		if isHead {
-			targetStep = curr.release.Spec.TargetStep
-			strategy = curr.release.Spec.Environment.Strategy
-			strategyStep = strategy.Steps[targetStep]
-			capacityWeight = strategyStep.Capacity.Contender
+			head = curr
 			condType = shipper.StrategyConditionContenderAchievedCapacity
 		} else {
-			targetStep = succ.release.Spec.TargetStep
-			strategy = succ.release.Spec.Environment.Strategy
-			strategyStep = strategy.Steps[targetStep]
-			capacityWeight = strategyStep.Capacity.Incumbent
+			head = succ
 			condType = shipper.StrategyConditionIncumbentAchievedCapacity
 		}
 
+		targetStep = head.release.Spec.TargetStep
+		strategy = head.release.Spec.Environment.Strategy
+
 		isLastStep := int(targetStep) == len(strategy.Steps)-1
 
-		if achieved, newSpec, clustersNotReady := checkCapacity(curr.capacityTarget, capacityWeight); !achieved {
+		clusters := make([]string, 0, len(curr.capacityTarget.Spec.Clusters))
+		for _, spec := range curr.capacityTarget.Spec.Clusters {
+			clusters = append(clusters, spec.Name)
+		}
+		capacityWeights := e.clusterStepValues(head, clusters, isHead, strategyutil.PickCapacity)
+		capacityReplicas := e.clusterStepReplicas(head, clusters, isHead)
+
+		if achieved, newSpec, clustersNotReady := checkCapacity(curr.capacityTarget, capacityWeights, capacityReplicas); !achieved {
 			e.info("release hasn't achieved capacity yet")
 
 			var patches []StrategyPatch
@@ -159,6 +225,8 @@ func genCapacityEnforcer(curr, succ *releaseInfo) PipelineStep {
 				targetStep,
 				isLastStep,
 				e.hasIncumbent,
+				e.buildClusterStrategyStatus(),
+				e.pluginConditionTypes()...,
 			)
 			if relPatch.Alters(e.curr.release) {
 				patches = append(patches, relPatch)
@@ -183,9 +251,9 @@ func genCapacityEnforcer(curr, succ *releaseInfo) PipelineStep {
 
 func genTrafficEnforcer(curr, succ *releaseInfo) PipelineStep {
 	return func(e *StrategyExecutor, cond conditions.StrategyConditionsMap) (PipelineContinuation, []StrategyPatch, []ReleaseStrategyStateTransition) {
-		var targetStep, trafficWeight int32
+		var targetStep int32
 		var strategy *shipper.RolloutStrategy
-		var strategyStep shipper.RolloutStrategyStep
+		var head *releaseInfo
 		var condType shipper.StrategyConditionType
 
 		// isHead is equivalent to the contender concept: it hjas no
@@ -196,22 +264,28 @@ func genTrafficEnforcer(curr, succ *releaseInfo) PipelineStep {
 		isHead := succ == nil
 
 		if isHead {
-			targetStep = curr.release.Spec.TargetStep
-			strategy = curr.release.Spec.Environment.Strategy
-			strategyStep = strategy.Steps[targetStep]
-			trafficWeight = strategyStep.Traffic.Contender
+			head = curr
 			condType = shipper.StrategyConditionContenderAchievedTraffic
 		} else {
-			targetStep = succ.release.Spec.TargetStep
-			strategy = succ.release.Spec.Environment.Strategy
-			strategyStep = strategy.Steps[targetStep]
-			trafficWeight = strategyStep.Traffic.Incumbent
+			head = succ
 			condType = shipper.StrategyConditionIncumbentAchievedTraffic
 		}
 
+		targetStep = head.release.Spec.TargetStep
+		strategy = head.release.Spec.Environment.Strategy
+
 		isLastStep := int(targetStep) == len(strategy.Steps)-1
 
-		if achieved, newSpec, reason := checkTraffic(curr.trafficTarget, uint32(trafficWeight)); !achieved {
+		clusters := make([]string, 0, len(curr.trafficTarget.Spec.Clusters))
+		for _, spec := range curr.trafficTarget.Spec.Clusters {
+			clusters = append(clusters, spec.Name)
+		}
+		trafficWeights := make(map[string]uint32, len(clusters))
+		for cluster, weight := range e.clusterStepValues(head, clusters, isHead, strategyutil.PickTraffic) {
+			trafficWeights[cluster] = uint32(weight)
+		}
+
+		if achieved, newSpec, reason := checkTraffic(curr.trafficTarget, trafficWeights); !achieved {
 			e.info("release hasn't achieved traffic yet")
 
 			var patches []StrategyPatch
@@ -240,6 +314,8 @@ func genTrafficEnforcer(curr, succ *releaseInfo) PipelineStep {
 				targetStep,
 				isLastStep,
 				e.hasIncumbent,
+				e.buildClusterStrategyStatus(),
+				e.pluginConditionTypes()...,
 			)
 			if relPatch.Alters(e.curr.release) {
 				patches = append(patches, relPatch)
@@ -276,7 +352,8 @@ func genReleaseStrategyStateEnforcer(curr, succ *releaseInfo) PipelineStep {
 		newReleaseStrategyState := cond.AsReleaseStrategyState(
 			curr.release.Spec.TargetStep,
 			e.hasIncumbent,
-			isLastStep)
+			isLastStep,
+			e.pluginConditionTypes()...)
 
 		oldReleaseStrategyState := shipper.ReleaseStrategyState{}
 		if relStatus.Strategy != nil {
@@ -292,6 +369,7 @@ func genReleaseStrategyStateEnforcer(curr, succ *releaseInfo) PipelineStep {
 		relStrategyStatus := &shipper.ReleaseStrategyStatus{
 			Conditions: cond.AsReleaseStrategyConditions(),
 			State:      newReleaseStrategyState,
+			Clusters:   e.buildClusterStrategyStatus(),
 		}
 
 		if !equality.Semantic.DeepEqual(curr.release.Status.Strategy, relStrategyStatus) {
@@ -312,6 +390,8 @@ func genReleaseStrategyStateEnforcer(curr, succ *releaseInfo) PipelineStep {
 	  0.2. Ensure target objects exist.
 	    0.2.1. Compare chosen clusters and if different, update the spec.
 	1. Find it's ancestor.
+	  1.1. For the head release, make sure its target step was approved if
+	       it requires approval.
 	2. For the head release, ensure installation.
 	  2.1. Simply check installation targets.
 	3. For the head release, ensure capacity.
@@ -324,7 +404,13 @@ func genReleaseStrategyStateEnforcer(curr, succ *releaseInfo) PipelineStep {
 	6. For a tail release, ensure capacity.
 	  6.1. Look at the leader and check it's target capacity.
 	  6.2 Look at the strategy and figure out the target capacity.
-	7. Make necessary adjustments to the release object.
+	7. For the head release, if the step has an analysis, check metrics.
+	8. For the head release, run the registered plugins, each right after
+	   the built-in step it asked to follow.
+	9. For the head release, call the step's pre-step hook before
+	   ensuring capacity, and its post-step hook once everything else is
+	   achieved.
+	10. Make necessary adjustments to the release object.
 */
 
 func (e *StrategyExecutor) Execute() (bool, []StrategyPatch, []ReleaseStrategyStateTransition, error) {
@@ -352,17 +438,45 @@ func (e *StrategyExecutor) Execute() (bool, []StrategyPatch, []ReleaseStrategySt
 	isHead, hasTail := e.succ == nil, e.prev != nil
 
 	pipeline := NewPipeline()
+	enqueuePlugins := func(after string) {
+		if !isHead {
+			return
+		}
+		for _, plugin := range e.plugins {
+			if plugin.After() == after {
+				pipeline.Enqueue(genPluginEnforcer(plugin))
+			}
+		}
+	}
+
+	if isHead && strategyutil.StepRequiresApproval(e.curr.release.Spec.Environment.Strategy, e.curr.release.Spec.TargetStep) {
+		pipeline.Enqueue(genApprovalEnforcer(e.curr, e.stepApproved))
+	}
+	enqueuePlugins("")
 	if isHead {
 		pipeline.Enqueue(genInstallationEnforcer(e.curr, nil))
+		enqueuePlugins(PipelineStepInstallation)
+		if stepHasHook(e.curr.release, PreStepHook) {
+			pipeline.Enqueue(genStepHookEnforcer(e.curr, PreStepHook))
+		}
 	}
 	pipeline.Enqueue(genCapacityEnforcer(e.curr, e.succ))
+	enqueuePlugins(PipelineStepCapacity)
 	pipeline.Enqueue(genTrafficEnforcer(e.curr, e.succ))
+	enqueuePlugins(PipelineStepTraffic)
 
 	if isHead {
 		if hasTail {
 			pipeline.Enqueue(genTrafficEnforcer(e.prev, e.curr))
 			pipeline.Enqueue(genCapacityEnforcer(e.prev, e.curr))
 		}
+		if stepHasAnalysis(e.curr.release) {
+			pipeline.Enqueue(genAnalysisEnforcer(e.curr, e.prev))
+		}
+		enqueuePlugins(PipelineStepAnalysis)
+		if stepHasHook(e.curr.release, PostStepHook) {
+			pipeline.Enqueue(genStepHookEnforcer(e.curr, PostStepHook))
+		}
 		pipeline.Enqueue(genReleaseStrategyStateEnforcer(e.curr, nil))
 	}
 
@@ -381,10 +495,13 @@ func buildContenderStrategyConditionsPatch(
 	step int32,
 	isLastStep bool,
 	hasIncumbent bool,
+	clusters []shipper.ClusterStrategyStatus,
+	gates ...shipper.StrategyConditionType,
 ) StrategyPatch {
 	newStrategyStatus := &shipper.ReleaseStrategyStatus{
 		Conditions: cond.AsReleaseStrategyConditions(),
-		State:      cond.AsReleaseStrategyState(step, hasIncumbent, isLastStep),
+		State:      cond.AsReleaseStrategyState(step, hasIncumbent, isLastStep, gates...),
+		Clusters:   clusters,
 	}
 	return &ReleaseStrategyStatusPatch{
 		NewStrategyStatus: newStrategyStatus,
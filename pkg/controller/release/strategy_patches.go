@@ -93,3 +93,41 @@ func (p *ReleaseStrategyStatusPatch) Alters(o interface{}) bool {
 func (p *ReleaseStrategyStatusPatch) IsEmpty() bool {
 	return p == nil || p.NewStrategyStatus == nil
 }
+
+// ReleaseAbortPatch is not a patch in the merge patch sense: applying it
+// deletes the contender release, which makes the application controller roll
+// back to the incumbent.
+type ReleaseAbortPatch struct {
+	Name   string
+	Reason string
+}
+
+var _ StrategyPatch = (*ReleaseAbortPatch)(nil)
+
+func (p *ReleaseAbortPatch) PatchSpec() (string, schema.GroupVersionKind, []byte) {
+	return p.Name, shipper.SchemeGroupVersion.WithKind("Release"), nil
+}
+
+func (p *ReleaseAbortPatch) Alters(o interface{}) bool {
+	return !p.IsEmpty()
+}
+
+func (p *ReleaseAbortPatch) IsEmpty() bool {
+	return p == nil || p.Name == ""
+}
+
+// releaseStrategyConditions returns the strategy conditions of a release
+// as they'll be once the given patches are applied.
+func releaseStrategyConditions(rel *shipper.Release, patches []StrategyPatch) []shipper.ReleaseStrategyCondition {
+	var strategyConditions []shipper.ReleaseStrategyCondition
+	if rel.Status.Strategy != nil {
+		strategyConditions = rel.Status.Strategy.Conditions
+	}
+	for _, patch := range patches {
+		if p, ok := patch.(*ReleaseStrategyStatusPatch); ok && p.Name == rel.Name && p.NewStrategyStatus != nil {
+			strategyConditions = p.NewStrategyStatus.Conditions
+		}
+	}
+
+	return strategyConditions
+}
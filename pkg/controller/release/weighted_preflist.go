@@ -5,6 +5,7 @@ import (
 	"sort"
 
 	"github.com/cespare/xxhash"
+	"k8s.io/apimachinery/pkg/labels"
 
 	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
 )
@@ -18,7 +19,7 @@ type scoredCluster struct {
 	score   float64
 }
 
-func buildPrefList(appIdentity string, clusterList []*shipper.Cluster) []*shipper.Cluster {
+func buildPrefList(appIdentity string, clusterList []*shipper.Cluster, selector labels.Selector) []*shipper.Cluster {
 	/*
 		This part is a bit subtle: we're creating a preference list of clusters
 		by creating a sorting key composed of a hash of the Application name
@@ -28,7 +29,8 @@ func buildPrefList(appIdentity string, clusterList []*shipper.Cluster) []*shippe
 		list because it is the order in which clusters will be selected for
 		this Application. All other scheduling concerns (unschedulable,
 		capability, capacity) just _mask_ this initial list by skipping over
-		entries when requirements are not met.
+		entries when requirements are not met. Clusters the Application's
+		selector doesn't match are left out of it altogether.
 
 		By using a good hash function and a key specific to this Application,
 		we get distribution of Applications across clusters (load balancing).
@@ -57,6 +59,10 @@ func buildPrefList(appIdentity string, clusterList []*shipper.Cluster) []*shippe
 	*/
 	scoredClusters := make([]scoredCluster, 0, len(clusterList))
 	for _, cluster := range clusterList {
+		if !selector.Matches(labels.Set(cluster.Labels)) {
+			continue
+		}
+
 		var clusterIdentity string
 
 		if cluster.Spec.Scheduler.Identity == nil {
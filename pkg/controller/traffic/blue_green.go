@@ -0,0 +1,203 @@
+package traffic
+
+import (
+	"encoding/json"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+	shippererrors "github.com/bookingcom/shipper/pkg/errors"
+)
+
+type blueGreenStatus struct {
+	ready                 bool
+	achievedTrafficWeight uint32
+	podsReady             int
+	podsNotReady          int
+	podsInRelease         int
+	// podsToEnable are the pods of the release that are still missing
+	// the traffic label. They are labeled ahead of the switch, while the
+	// Service keeps them out of its endpoints.
+	podsToEnable []*corev1.Pod
+	// switchTo is the release the Service should be pointed at, if it is
+	// this one's job to do it.
+	switchTo string
+}
+
+// buildBlueGreenStatus looks at the current state of a cluster regarding
+// blue/green traffic shifting. Instead of labeling a share of every
+// release's pods, the production Service selects the pods of a single
+// release, the one with the highest weight, so traffic moves between
+// releases in one atomic update to the Service. The other releases' pods
+// keep their traffic labels, which makes switching back just as quick.
+func buildBlueGreenStatus(
+	cluster, appName, releaseName string,
+	clusterReleaseWeights clusterReleaseWeights,
+	service *corev1.Service,
+	endpoints *corev1.Endpoints,
+	appPods []*corev1.Pod,
+) blueGreenStatus {
+	releaseTargetWeights := clusterReleaseWeights[cluster]
+	active := activeRelease(releaseTargetWeights)
+	selected, pinned := service.Spec.Selector[shipper.ReleaseLabel]
+
+	releaseSelector := labels.Set(map[string]string{
+		shipper.AppLabel:     appName,
+		shipper.ReleaseLabel: releaseName,
+	}).AsSelector()
+
+	podsByTrafficStatus, podsInRelease, podsReady, podsNotReady := summarizePods(
+		appPods, endpoints, releaseSelector)
+
+	status := blueGreenStatus{
+		podsReady:     podsReady,
+		podsNotReady:  podsNotReady,
+		podsInRelease: podsInRelease,
+	}
+
+	// Pods are only labeled while the Service is pinned to a release, so
+	// labeling them never sends them any traffic on its own.
+	if pinned {
+		status.podsToEnable = podsByTrafficStatus[shipper.Disabled]
+	}
+
+	if releaseName != active {
+		// This release must not get any traffic, which is the case
+		// as soon as the Service points elsewhere. A Service that
+		// isn't pinned yet still sends traffic to every labeled pod,
+		// unless no release is meant to get any.
+		status.ready = selected != releaseName && (pinned || active == "")
+		return status
+	}
+
+	if !pinned {
+		// The Service selects every labeled pod, so it has to be
+		// pinned to the release getting traffic right now before any
+		// of this one's pods can be labeled, or they'd get traffic one
+		// at a time instead of all at once.
+		status.switchTo = servingRelease(appPods, releaseName)
+		return status
+	}
+
+	if selected != releaseName {
+		// Only the release about to get traffic switches the Service
+		// over, and only once all of its pods are labeled, so they all
+		// get traffic at once.
+		if len(status.podsToEnable) == 0 {
+			status.switchTo = releaseName
+		}
+		return status
+	}
+
+	var totalTargetWeight uint32
+	for _, weight := range releaseTargetWeights {
+		totalTargetWeight += weight
+	}
+
+	status.achievedTrafficWeight = totalTargetWeight
+	status.ready = len(status.podsToEnable) == 0 && podsReady == podsInRelease
+
+	return status
+}
+
+// activeRelease returns the release that should get all of the traffic in a
+// cluster, or an empty string if no release has any weight.
+func activeRelease(releaseWeights map[string]uint32) string {
+	var active string
+	var activeWeight uint32
+	for release, weight := range releaseWeights {
+		if weight == 0 || weight < activeWeight {
+			continue
+		}
+
+		// Blue/green strategies never split traffic, so ties only
+		// happen halfway through a change of strategy. Picking by
+		// name keeps us from flapping between releases meanwhile.
+		if weight == activeWeight && release > active {
+			continue
+		}
+
+		active = release
+		activeWeight = weight
+	}
+
+	return active
+}
+
+// servingRelease returns the release with the most pods labeled to receive
+// traffic, other than the given one, or the given one if there is no other.
+func servingRelease(appPods []*corev1.Pod, releaseName string) string {
+	podCounts := map[string]int{}
+	for _, pod := range appPods {
+		release := pod.Labels[shipper.ReleaseLabel]
+		if release == "" || release == releaseName ||
+			pod.Labels[shipper.PodTrafficStatusLabel] != shipper.Enabled {
+			continue
+		}
+		podCounts[release]++
+	}
+
+	serving := releaseName
+	var servingPods int
+	for release, count := range podCounts {
+		if count > servingPods || (count == servingPods && release < serving) {
+			serving = release
+			servingPods = count
+		}
+	}
+
+	return serving
+}
+
+// appStrategyType returns how traffic is shifted for an application. Like the
+// release controller, we follow the strategy of the latest release.
+func appStrategyType(trafficTargets []*shipper.TrafficTarget) shipper.RolloutStrategyType {
+	var latest *shipper.TrafficTarget
+	for _, tt := range trafficTargets {
+		if latest == nil || latest.CreationTimestamp.Before(&tt.CreationTimestamp) ||
+			(latest.CreationTimestamp.Equal(&tt.CreationTimestamp) && latest.Name < tt.Name) {
+			latest = tt
+		}
+	}
+
+	if latest == nil || latest.Spec.StrategyType == "" {
+		return shipper.RolloutStrategyTypeIncremental
+	}
+
+	return latest.Spec.StrategyType
+}
+
+// patchServiceReleaseSelector points the Service at the pods of a single
+// release, or at those of every release when release is empty. It's a
+// single update, so traffic moves all at once.
+func patchServiceReleaseSelector(
+	clientset kubernetes.Interface,
+	service *corev1.Service,
+	release string,
+) error {
+	var value interface{}
+	if release != "" {
+		value = release
+	}
+
+	patch, _ := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"selector": map[string]interface{}{
+				shipper.ReleaseLabel: value,
+			},
+		},
+	})
+
+	_, err := clientset.CoreV1().Services(service.Namespace).
+		Patch(service.Name, types.StrategicMergePatchType, patch)
+	if err != nil {
+		return shippererrors.
+			NewKubeclientPatchError(service.Namespace, service.Name, err).
+			WithCoreV1Kind("Service")
+	}
+
+	return nil
+}
@@ -0,0 +1,177 @@
+package traffic
+
+import (
+	"encoding/json"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubetesting "k8s.io/client-go/testing"
+
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+	shippertesting "github.com/bookingcom/shipper/pkg/testing"
+)
+
+// TestBlueGreenSwitch verifies that, with a blue/green strategy, the
+// traffic controller labels all of the contender's pods while the Service
+// still points at the incumbent, and then moves all of the traffic over by
+// pointing the Service at the contender. The incumbent's pods keep their
+// labels, so switching back is just as quick.
+func TestBlueGreenSwitch(t *testing.T) {
+	incumbent := buildTrafficTarget(
+		shippertesting.TestApp, "foobar-a",
+		map[string]uint32{clusterA: 0},
+	)
+	incumbent.Spec.StrategyType = shipper.RolloutStrategyTypeBlueGreen
+
+	contender := buildTrafficTarget(
+		shippertesting.TestApp, "foobar-b",
+		map[string]uint32{clusterA: 100},
+	)
+	contender.Spec.StrategyType = shipper.RolloutStrategyTypeBlueGreen
+
+	service := buildService(shippertesting.TestApp)
+	service.Spec.Selector[shipper.ReleaseLabel] = incumbent.Name
+
+	podCount := 3
+	clusterObjects := []runtime.Object{
+		service,
+		buildEndpoints(shippertesting.TestApp),
+	}
+	clusterObjects = addPodsToList(clusterObjects,
+		buildPods(shippertesting.TestApp, incumbent.Name, podCount, withTraffic))
+	clusterObjects = addPodsToList(clusterObjects,
+		buildPods(shippertesting.TestApp, contender.Name, podCount, noTraffic))
+
+	incumbentStatus := buildSuccessStatus(incumbent.Spec.Clusters)
+	contenderStatus := buildSuccessStatus(contender.Spec.Clusters)
+
+	f := runTrafficControllerTest(t,
+		map[string][]runtime.Object{clusterA: clusterObjects},
+		[]trafficTargetTestExpectation{
+			{
+				trafficTarget: incumbent,
+				status:        incumbentStatus,
+				podsByCluster: map[string]podStatus{
+					clusterA: {withTraffic: podCount},
+				},
+			},
+			{
+				trafficTarget: contender,
+				status:        contenderStatus,
+				podsByCluster: map[string]podStatus{
+					clusterA: {withTraffic: podCount},
+				},
+			},
+		},
+	)
+
+	serviceGVR := corev1.SchemeGroupVersion.WithResource("services")
+	object, err := f.Clusters[clusterA].Client.Tracker().
+		Get(serviceGVR, service.Namespace, service.Name)
+	if err != nil {
+		t.Fatalf("could not Get Service %q: %s", service.Name, err)
+	}
+
+	selected := object.(*corev1.Service).Spec.Selector[shipper.ReleaseLabel]
+	if selected != contender.Name {
+		t.Errorf("expected Service to select release %q, got %q", contender.Name, selected)
+	}
+}
+
+// TestBlueGreenSwitchPinsServiceFirst verifies that, when the Service still
+// selects every labeled pod, it is pinned to the incumbent before any of
+// the contender's pods are labeled, so the contender gets all of its
+// traffic at once when the Service is pointed at it.
+func TestBlueGreenSwitchPinsServiceFirst(t *testing.T) {
+	incumbent := buildTrafficTarget(
+		shippertesting.TestApp, "foobar-a",
+		map[string]uint32{clusterA: 0},
+	)
+	incumbent.Spec.StrategyType = shipper.RolloutStrategyTypeBlueGreen
+
+	contender := buildTrafficTarget(
+		shippertesting.TestApp, "foobar-b",
+		map[string]uint32{clusterA: 100},
+	)
+	contender.Spec.StrategyType = shipper.RolloutStrategyTypeBlueGreen
+
+	service := buildService(shippertesting.TestApp)
+
+	podCount := 3
+	clusterObjects := []runtime.Object{
+		service,
+		buildEndpoints(shippertesting.TestApp),
+	}
+	clusterObjects = addPodsToList(clusterObjects,
+		buildPods(shippertesting.TestApp, incumbent.Name, podCount, withTraffic))
+	clusterObjects = addPodsToList(clusterObjects,
+		buildPods(shippertesting.TestApp, contender.Name, podCount, noTraffic))
+
+	f := runTrafficControllerTest(t,
+		map[string][]runtime.Object{clusterA: clusterObjects},
+		[]trafficTargetTestExpectation{
+			{
+				trafficTarget: incumbent,
+				status:        buildSuccessStatus(incumbent.Spec.Clusters),
+				podsByCluster: map[string]podStatus{
+					clusterA: {withTraffic: podCount},
+				},
+			},
+			{
+				trafficTarget: contender,
+				status:        buildSuccessStatus(contender.Spec.Clusters),
+				podsByCluster: map[string]podStatus{
+					clusterA: {withTraffic: podCount},
+				},
+			},
+		},
+	)
+
+	var selectors []string
+	for _, action := range f.Clusters[clusterA].Client.Actions() {
+		patch, ok := action.(kubetesting.PatchAction)
+		if !ok || patch.GetVerb() != "patch" {
+			continue
+		}
+
+		switch patch.GetResource().Resource {
+		case "services":
+			var svc corev1.Service
+			if err := json.Unmarshal(patch.GetPatch(), &svc); err != nil {
+				t.Fatalf("could not decode Service patch: %s", err)
+			}
+			selectors = append(selectors, svc.Spec.Selector[shipper.ReleaseLabel])
+		case "pods":
+			if len(selectors) == 0 || selectors[0] != incumbent.Name {
+				t.Fatalf("expected Service to be pinned to %q before labeling pods, got selectors %v",
+					incumbent.Name, selectors)
+			}
+		}
+	}
+
+	expected := []string{incumbent.Name, contender.Name}
+	if eq, diff := shippertesting.DeepEqualDiff(expected, selectors); !eq {
+		t.Errorf("unexpected Service selector changes:\n%s", diff)
+	}
+}
+
+func TestActiveRelease(t *testing.T) {
+	tests := []struct {
+		name     string
+		weights  map[string]uint32
+		expected string
+	}{
+		{"no releases", map[string]uint32{}, ""},
+		{"no weights", map[string]uint32{"foobar-a": 0, "foobar-b": 0}, ""},
+		{"contender", map[string]uint32{"foobar-a": 0, "foobar-b": 100}, "foobar-b"},
+		{"incumbent", map[string]uint32{"foobar-a": 100, "foobar-b": 0}, "foobar-a"},
+		{"tie", map[string]uint32{"foobar-a": 50, "foobar-b": 50}, "foobar-a"},
+	}
+
+	for _, tt := range tests {
+		if got := activeRelease(tt.weights); got != tt.expected {
+			t.Errorf("%s: expected active release %q, got %q", tt.name, tt.expected, got)
+		}
+	}
+}
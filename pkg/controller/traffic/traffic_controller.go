@@ -13,6 +13,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/apimachinery/pkg/util/wait"
 	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
@@ -92,15 +93,25 @@ func NewController(
 	return controller
 }
 
-// registerAppClusterEventHandlers listens to events on both Endpoints and
-// Pods. An event on an Endpoints object enqueues all traffic targets for an
-// app, as a change in one of them might affect the weight in the others. For
+// registerAppClusterEventHandlers listens to events on Services, Endpoints and
+// Pods. An event on a Service or an Endpoints object enqueues all traffic
+// targets for an app, as a change in one of them might affect the weight in
+// the others, or which release a blue/green strategy sends traffic to. For
 // Pods, we only enqueue the owning traffic target, and only for adds and
 // deletes, as any changes relevant for traffic will be reflected in the
 // Endpoints object anyway. In case a new or deleted pod does change traffic
 // shifting in any way, the update to the traffic target itself will trigger a
 // new evaluation of all traffic targets for an app.
 func (c *Controller) registerAppClusterEventHandlers(informerFactory kubeinformers.SharedInformerFactory, clusterName string) {
+	informerFactory.Core().V1().Services().Informer().AddEventHandler(cache.FilteringResourceEventHandler{
+		FilterFunc: filters.BelongsToApp,
+		Handler: cache.ResourceEventHandlerFuncs{
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				c.enqueueAllTrafficTargets(newObj)
+			},
+		},
+	})
+
 	informerFactory.Core().V1().Endpoints().Informer().AddEventHandler(cache.FilteringResourceEventHandler{
 		FilterFunc: filters.BelongsToApp,
 		Handler: cache.ResourceEventHandlerFuncs{
@@ -260,6 +271,8 @@ func (c *Controller) processTrafficTarget(tt *shipper.TrafficTarget) (*shipper.T
 
 	tt.Status.Conditions = targetutil.TransitionToOperational(diff, tt.Status.Conditions)
 
+	strategyType := appStrategyType(allTTs)
+
 	clusterErrors := shippererrors.NewMultiError()
 	newClusterStatuses := make([]*shipper.ClusterTrafficStatus, 0, len(tt.Spec.Clusters))
 
@@ -277,7 +290,7 @@ func (c *Controller) processTrafficTarget(tt *shipper.TrafficTarget) (*shipper.T
 			}
 		}
 
-		err := c.processTrafficTargetOnCluster(tt, &clusterSpec, clusterStatus, clusterReleaseWeights)
+		err := c.processTrafficTargetOnCluster(tt, &clusterSpec, clusterStatus, clusterReleaseWeights, strategyType)
 		if err != nil {
 			clusterErrors.Append(err)
 		}
@@ -313,6 +326,7 @@ func (c *Controller) processTrafficTargetOnCluster(
 	spec *shipper.ClusterTrafficTarget,
 	status *shipper.ClusterTrafficStatus,
 	clusterReleaseWeights clusterReleaseWeights,
+	strategyType shipper.RolloutStrategyType,
 ) error {
 	diff := diffutil.NewMultiDiff()
 	operationalCond := trafficutil.NewClusterTrafficCondition(
@@ -350,7 +364,7 @@ func (c *Controller) processTrafficTargetOnCluster(
 	appName := tt.Labels[shipper.AppLabel]
 	releaseName := tt.Labels[shipper.ReleaseLabel]
 
-	appPods, endpoints, err := c.getClusterObjects(spec.Name, tt.Namespace, appName)
+	appPods, service, endpoints, err := c.getClusterObjects(spec.Name, tt.Namespace, appName)
 	if err != nil {
 		operationalCond = trafficutil.NewClusterTrafficCondition(
 			shipper.ClusterConditionTypeOperational,
@@ -369,6 +383,44 @@ func (c *Controller) processTrafficTargetOnCluster(
 		"",
 	)
 
+	if strategyType == shipper.RolloutStrategyTypeBlueGreen {
+		trafficStatus := buildBlueGreenStatus(
+			spec.Name, appName, releaseName,
+			clusterReleaseWeights,
+			service, endpoints, appPods)
+
+		achievedTraffic = trafficStatus.achievedTrafficWeight
+		readyCond, err = c.shiftBlueGreenTraffic(clientset, service, releaseName, trafficStatus)
+
+		return err
+	}
+
+	if _, ok := service.Spec.Selector[shipper.ReleaseLabel]; ok {
+		// The Service was left pointing at a single release by a
+		// blue/green strategy. Pods are labeled for traffic from now on,
+		// so it needs to select all of them again.
+		err := patchServiceReleaseSelector(clientset, service, "")
+		if err != nil {
+			readyCond = trafficutil.NewClusterTrafficCondition(
+				shipper.ClusterConditionTypeReady,
+				corev1.ConditionFalse,
+				InternalError,
+				err.Error(),
+			)
+
+			return err
+		}
+
+		readyCond = trafficutil.NewClusterTrafficCondition(
+			shipper.ClusterConditionTypeReady,
+			corev1.ConditionFalse,
+			InProgress,
+			"",
+		)
+
+		return nil
+	}
+
 	trafficStatus := buildTrafficShiftingStatus(
 		spec.Name, appName, releaseName,
 		clusterReleaseWeights,
@@ -441,19 +493,64 @@ func (c *Controller) processTrafficTargetOnCluster(
 	return nil
 }
 
-func (c *Controller) getClusterObjects(cluster, ns, appName string) ([]*corev1.Pod, *corev1.Endpoints, error) {
-	informerFactory, err := c.clusterClientStore.GetInformerFactory(cluster)
+func (c *Controller) shiftBlueGreenTraffic(
+	clientset kubernetes.Interface,
+	service *corev1.Service,
+	releaseName string,
+	trafficStatus blueGreenStatus,
+) (*shipper.ClusterTrafficCondition, error) {
+	if trafficStatus.ready {
+		return trafficutil.NewClusterTrafficCondition(
+			shipper.ClusterConditionTypeReady,
+			corev1.ConditionTrue,
+			"",
+			"",
+		), nil
+	}
+
+	var err error
+	if len(trafficStatus.podsToEnable) > 0 {
+		err = shiftPodLabels(clientset, map[string][]*corev1.Pod{
+			shipper.Enabled: trafficStatus.podsToEnable,
+		})
+	} else if trafficStatus.switchTo != "" {
+		err = patchServiceReleaseSelector(clientset, service, trafficStatus.switchTo)
+	} else if trafficStatus.podsNotReady > 0 {
+		msg := fmt.Sprintf(
+			"%d out of %d pods designated to receive traffic are not ready. this might require intervention, try `kubectl describe ct %s` for more information",
+			trafficStatus.podsNotReady, trafficStatus.podsInRelease, releaseName)
+		return trafficutil.NewClusterTrafficCondition(
+			shipper.ClusterConditionTypeReady,
+			corev1.ConditionFalse,
+			PodsNotReady,
+			msg,
+		), nil
+	}
+
 	if err != nil {
-		return nil, nil, err
+		return trafficutil.NewClusterTrafficCondition(
+			shipper.ClusterConditionTypeReady,
+			corev1.ConditionFalse,
+			InternalError,
+			err.Error(),
+		), err
 	}
 
-	appSelector := labels.Set{shipper.AppLabel: appName}.AsSelector()
-	appPods, err := informerFactory.Core().V1().Pods().Lister().
-		Pods(ns).List(appSelector)
+	// Either we've just made a change that is yet to be observed, or
+	// we're waiting for another release to move traffic away from this
+	// one or for the pods of this one to make it into endpoints.
+	return trafficutil.NewClusterTrafficCondition(
+		shipper.ClusterConditionTypeReady,
+		corev1.ConditionFalse,
+		InProgress,
+		"",
+	), nil
+}
+
+func (c *Controller) getClusterObjects(cluster, ns, appName string) ([]*corev1.Pod, *corev1.Service, *corev1.Endpoints, error) {
+	informerFactory, err := c.clusterClientStore.GetInformerFactory(cluster)
 	if err != nil {
-		return nil, nil, shippererrors.NewKubeclientListError(
-			corev1.SchemeGroupVersion.WithKind("Pod"),
-			ns, appSelector, err)
+		return nil, nil, nil, err
 	}
 
 	serviceSelector := labels.Set(map[string]string{
@@ -464,26 +561,47 @@ func (c *Controller) getClusterObjects(cluster, ns, appName string) ([]*corev1.P
 	services, err := informerFactory.Core().V1().Services().Lister().
 		Services(ns).List(serviceSelector)
 	if err != nil {
-		return nil, nil, shippererrors.NewKubeclientListError(
+		return nil, nil, nil, shippererrors.NewKubeclientListError(
 			serviceGVK, ns, serviceSelector, err)
 	}
 
 	if len(services) != 1 {
 		err := shippererrors.NewUnexpectedObjectCountFromSelectorError(
 			serviceSelector, serviceGVK, 1, len(services))
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	svc := services[0]
 
+	// Charts can have workloads that don't serve traffic through the
+	// Service, so only the pods it selects get traffic weights. Traffic
+	// and release labels are what this controller uses to shift traffic
+	// between releases, so they're left out.
+	appSet := labels.Set{}
+	for k, v := range svc.Spec.Selector {
+		if k != shipper.PodTrafficStatusLabel && k != shipper.ReleaseLabel {
+			appSet[k] = v
+		}
+	}
+	appSet[shipper.AppLabel] = appName
+	appSelector := appSet.AsSelector()
+
+	appPods, err := informerFactory.Core().V1().Pods().Lister().
+		Pods(ns).List(appSelector)
+	if err != nil {
+		return nil, nil, nil, shippererrors.NewKubeclientListError(
+			corev1.SchemeGroupVersion.WithKind("Pod"),
+			ns, appSelector, err)
+	}
+
 	endpoints, err := informerFactory.Core().V1().Endpoints().Lister().
 		Endpoints(svc.Namespace).Get(svc.Name)
 	if err != nil {
-		return nil, nil, shippererrors.NewKubeclientGetError(svc.Namespace, svc.Name, err).
+		return nil, nil, nil, shippererrors.NewKubeclientGetError(svc.Namespace, svc.Name, err).
 			WithCoreV1Kind("Endpoints")
 	}
 
-	return appPods, endpoints, nil
+	return appPods, svc, endpoints, nil
 }
 
 // enqueueTrafficTarget takes a TrafficTarget resource and converts it into a
@@ -61,6 +61,39 @@ func TestSingleCluster(t *testing.T) {
 	)
 }
 
+// TestPodsNotSelectedByService verifies that only the pods the Service
+// selects get traffic, so pods of workloads that don't serve through it
+// don't skew traffic weights.
+func TestPodsNotSelectedByService(t *testing.T) {
+	tt := buildTrafficTarget(shippertesting.TestApp, ttName,
+		map[string]uint32{clusterA: 10})
+
+	service := buildService(shippertesting.TestApp)
+	service.Spec.Selector["component"] = "web"
+
+	pods := buildPods(shippertesting.TestApp, ttName, 2, noTraffic)
+	pods[0].Labels["component"] = "web"
+	pods[1].Labels["component"] = "worker"
+
+	objects := []runtime.Object{service, buildEndpoints(shippertesting.TestApp)}
+	objects = addPodsToList(objects, pods)
+
+	runTrafficControllerTest(t,
+		map[string][]runtime.Object{
+			clusterA: objects,
+		},
+		[]trafficTargetTestExpectation{
+			{
+				trafficTarget: tt,
+				status:        buildSuccessStatus(tt.Spec.Clusters),
+				podsByCluster: map[string]podStatus{
+					clusterA: {withTraffic: 1, withoutTraffic: 1},
+				},
+			},
+		},
+	)
+}
+
 // TestMultipleClusters does the same thing as TestSingleCluster, but does so
 // for multiple clusters.
 func TestMultipleClusters(t *testing.T) {
@@ -256,7 +289,7 @@ func runTrafficControllerTest(
 	t *testing.T,
 	objectsByCluster map[string][]runtime.Object,
 	expectations []trafficTargetTestExpectation,
-) {
+) *shippertesting.ControllerTestFixture {
 	f := shippertesting.NewControllerTestFixture()
 
 	clusterNames := []string{}
@@ -307,6 +340,8 @@ func runTrafficControllerTest(
 			assertPodTraffic(t, tt, f.Clusters[clusterName], expectedPods)
 		}
 	}
+
+	return f
 }
 
 func assertPodTraffic(
@@ -56,7 +56,7 @@ func buildTrafficShiftingStatus(
 
 	podsInApp := len(appPods)
 	podsLabeledForTraffic := len(podsByTrafficStatus[shipper.Enabled])
-	podsToLabel := calculateReleasePodTarget(
+	podsToLabel := replicas.CalculateReleasePodTarget(
 		podsInRelease, releaseTargetWeight, podsInApp, totalTargetWeight)
 
 	// A TrafficTarget is ready when it has achieved a certain number of
@@ -245,21 +245,3 @@ func buildClusterReleaseWeights(trafficTargets []*shipper.TrafficTarget) (cluste
 
 	return clusterReleaseWeights(clusterReleases), nil
 }
-
-func calculateReleasePodTarget(releasePods int, releaseWeight uint32, totalPods int, totalWeight uint32) int {
-	// What percentage of the entire fleet (across all releases) should
-	// this set of pods represent.
-	var targetPercent float64
-	if totalWeight == 0 {
-		targetPercent = 0
-	} else {
-		targetPercent = float64(releaseWeight) / float64(totalWeight) * 100
-	}
-
-	// Round up to the nearest pod, clamped to the number of pods this
-	// release has.
-	targetPods := int(replicas.CalculateDesiredReplicaCount(uint(totalPods), float64(targetPercent)))
-	targetPods = int(math.Min(float64(releasePods), float64(targetPods)))
-
-	return targetPods
-}
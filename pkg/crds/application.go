@@ -35,6 +35,26 @@ var Application = &apiextensionv1beta1.CustomResourceDefinition{
 						},
 						Properties: map[string]apiextensionv1beta1.JSONSchemaProps{
 							"template": environmentValidation,
+							"rollbackPolicy": apiextensionv1beta1.JSONSchemaProps{
+								Type: "object",
+								Properties: map[string]apiextensionv1beta1.JSONSchemaProps{
+									"maxSadPodPercent": apiextensionv1beta1.JSONSchemaProps{
+										Type:    "integer",
+										Minimum: &zero,
+										Maximum: &hundred,
+									},
+									"maxRestarts": apiextensionv1beta1.JSONSchemaProps{
+										Type:    "integer",
+										Minimum: &zero,
+									},
+									"gracePeriod": apiextensionv1beta1.JSONSchemaProps{
+										Type: "string",
+									},
+									"progressDeadline": apiextensionv1beta1.JSONSchemaProps{
+										Type: "string",
+									},
+								},
+							},
 						},
 					},
 				},
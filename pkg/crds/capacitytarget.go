@@ -55,6 +55,10 @@ var CapacityTarget = &apiextensionv1beta1.CustomResourceDefinition{
 												Minimum: &zero,
 												Maximum: &hundred,
 											},
+											"replicas": apiextensionv1beta1.JSONSchemaProps{
+												Type:    "integer",
+												Minimum: &zero,
+											},
 										},
 									},
 								},
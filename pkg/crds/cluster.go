@@ -39,6 +39,9 @@ var Cluster = &apiextensionv1beta1.CustomResourceDefinition{
 							"region": apiextensionv1beta1.JSONSchemaProps{
 								Type: "string",
 							},
+							"zone": apiextensionv1beta1.JSONSchemaProps{
+								Type: "string",
+							},
 							"apiMaster": apiextensionv1beta1.JSONSchemaProps{
 								Type: "string",
 							},
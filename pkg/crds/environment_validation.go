@@ -8,7 +8,6 @@ var environmentValidation = apiextensionv1beta1.JSONSchemaProps{
 	Type: "object",
 	Required: []string{
 		"clusterRequirements",
-		"strategy",
 		"chart",
 		"values",
 	},
@@ -54,59 +53,223 @@ var environmentValidation = apiextensionv1beta1.JSONSchemaProps{
 						},
 					},
 				},
+				"reschedule": apiextensionv1beta1.JSONSchemaProps{
+					Type: "boolean",
+				},
+				"selector": apiextensionv1beta1.JSONSchemaProps{
+					Type: "object",
+					Properties: map[string]apiextensionv1beta1.JSONSchemaProps{
+						"matchLabels": apiextensionv1beta1.JSONSchemaProps{
+							Type: "object",
+							AdditionalProperties: &apiextensionv1beta1.JSONSchemaPropsOrBool{
+								Schema: &apiextensionv1beta1.JSONSchemaProps{
+									Type: "string",
+								},
+							},
+						},
+						"matchExpressions": apiextensionv1beta1.JSONSchemaProps{
+							Type: "array",
+							Items: &apiextensionv1beta1.JSONSchemaPropsOrArray{
+								Schema: &apiextensionv1beta1.JSONSchemaProps{
+									Type: "object",
+									Required: []string{
+										"key",
+										"operator",
+									},
+									Properties: map[string]apiextensionv1beta1.JSONSchemaProps{
+										"key": apiextensionv1beta1.JSONSchemaProps{
+											Type: "string",
+										},
+										"operator": apiextensionv1beta1.JSONSchemaProps{
+											Type: "string",
+											Enum: []apiextensionv1beta1.JSON{
+												{Raw: []byte(`"In"`)},
+												{Raw: []byte(`"NotIn"`)},
+												{Raw: []byte(`"Exists"`)},
+												{Raw: []byte(`"DoesNotExist"`)},
+											},
+										},
+										"values": apiextensionv1beta1.JSONSchemaProps{
+											Type: "array",
+											Items: &apiextensionv1beta1.JSONSchemaPropsOrArray{
+												Schema: &apiextensionv1beta1.JSONSchemaProps{
+													Type: "string",
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
 			},
 		},
-		"strategy": apiextensionv1beta1.JSONSchemaProps{
+		"strategy": strategyValidation,
+		"strategyRef": apiextensionv1beta1.JSONSchemaProps{
 			Type: "object",
 			Required: []string{
-				"steps",
+				"name",
 			},
 			Properties: map[string]apiextensionv1beta1.JSONSchemaProps{
-				"steps": apiextensionv1beta1.JSONSchemaProps{
-					Type: "array",
-					Items: &apiextensionv1beta1.JSONSchemaPropsOrArray{
-						Schema: &apiextensionv1beta1.JSONSchemaProps{
+				"kind": apiextensionv1beta1.JSONSchemaProps{
+					Type: "string",
+					Enum: []apiextensionv1beta1.JSON{
+						{Raw: []byte(`"StrategyTemplate"`)},
+						{Raw: []byte(`"ClusterStrategyTemplate"`)},
+					},
+				},
+				"name": apiextensionv1beta1.JSONSchemaProps{
+					Type: "string",
+				},
+			},
+		},
+		"values": apiextensionv1beta1.JSONSchemaProps{
+			Type: "object",
+		},
+	},
+}
+
+var strategyValidation = apiextensionv1beta1.JSONSchemaProps{
+	Type: "object",
+	Required: []string{
+		"steps",
+	},
+	Properties: map[string]apiextensionv1beta1.JSONSchemaProps{
+		"progressDeadlineSeconds": apiextensionv1beta1.JSONSchemaProps{
+			Type:    "integer",
+			Minimum: &zero,
+		},
+		"steps": apiextensionv1beta1.JSONSchemaProps{
+			Type: "array",
+			Items: &apiextensionv1beta1.JSONSchemaPropsOrArray{
+				Schema: &apiextensionv1beta1.JSONSchemaProps{
+					Type: "object",
+					Required: []string{
+						"name",
+						"traffic",
+						"capacity",
+					},
+					Properties: map[string]apiextensionv1beta1.JSONSchemaProps{
+						"name": apiextensionv1beta1.JSONSchemaProps{
+							Type: "string",
+						},
+						"capacity": apiextensionv1beta1.JSONSchemaProps{
+							Type: "object",
+							Required: []string{
+								"incumbent",
+								"contender",
+							},
+							Properties: map[string]apiextensionv1beta1.JSONSchemaProps{
+								"incumbent": stepCapacityValidation,
+								"contender": stepCapacityValidation,
+							},
+						},
+						"traffic": apiextensionv1beta1.JSONSchemaProps{
+							Type: "object",
+							Required: []string{
+								"incumbent",
+								"contender",
+							},
+							Properties: map[string]apiextensionv1beta1.JSONSchemaProps{
+								"incumbent": apiextensionv1beta1.JSONSchemaProps{
+									Type:    "integer",
+									Minimum: &zero,
+								},
+								"contender": apiextensionv1beta1.JSONSchemaProps{
+									Type:    "integer",
+									Minimum: &zero,
+								},
+							},
+						},
+						"pause": apiextensionv1beta1.JSONSchemaProps{
+							Type: "string",
+						},
+						"progressDeadlineSeconds": apiextensionv1beta1.JSONSchemaProps{
+							Type:    "integer",
+							Minimum: &zero,
+						},
+						"requiresApproval": apiextensionv1beta1.JSONSchemaProps{
+							Type: "boolean",
+						},
+						"hooks": apiextensionv1beta1.JSONSchemaProps{
+							Type: "object",
+							Properties: map[string]apiextensionv1beta1.JSONSchemaProps{
+								"preStep": apiextensionv1beta1.JSONSchemaProps{
+									Type: "string",
+								},
+								"postStep": apiextensionv1beta1.JSONSchemaProps{
+									Type: "string",
+								},
+							},
+						},
+						"analysis": apiextensionv1beta1.JSONSchemaProps{
 							Type: "object",
 							Required: []string{
-								"name",
-								"traffic",
-								"capacity",
+								"url",
+								"metrics",
 							},
 							Properties: map[string]apiextensionv1beta1.JSONSchemaProps{
-								"name": apiextensionv1beta1.JSONSchemaProps{
+								"url": apiextensionv1beta1.JSONSchemaProps{
 									Type: "string",
 								},
-								"capacity": apiextensionv1beta1.JSONSchemaProps{
-									Type: "object",
-									Required: []string{
-										"incumbent",
-										"contender",
-									},
-									Properties: map[string]apiextensionv1beta1.JSONSchemaProps{
-										"incumbent": apiextensionv1beta1.JSONSchemaProps{
-											Type:    "integer",
-											Minimum: &zero,
-											Maximum: &hundred,
-										},
-										"contender": apiextensionv1beta1.JSONSchemaProps{
-											Type:    "integer",
-											Minimum: &zero,
-											Maximum: &hundred,
+								"abortOnFailure": apiextensionv1beta1.JSONSchemaProps{
+									Type: "boolean",
+								},
+								"metrics": apiextensionv1beta1.JSONSchemaProps{
+									Type: "array",
+									Items: &apiextensionv1beta1.JSONSchemaPropsOrArray{
+										Schema: &apiextensionv1beta1.JSONSchemaProps{
+											Type: "object",
+											Required: []string{
+												"name",
+												"query",
+											},
+											Properties: map[string]apiextensionv1beta1.JSONSchemaProps{
+												"name": apiextensionv1beta1.JSONSchemaProps{
+													Type: "string",
+												},
+												"query": apiextensionv1beta1.JSONSchemaProps{
+													Type: "string",
+												},
+												"min": apiextensionv1beta1.JSONSchemaProps{
+													Type: "string",
+												},
+												"max": apiextensionv1beta1.JSONSchemaProps{
+													Type: "string",
+												},
+												"maxIncumbentRatio": apiextensionv1beta1.JSONSchemaProps{
+													Type: "string",
+												},
+											},
 										},
 									},
 								},
-								"traffic": apiextensionv1beta1.JSONSchemaProps{
-									Type: "object",
-									Required: []string{
-										"incumbent",
-										"contender",
+							},
+						},
+						"clusters": apiextensionv1beta1.JSONSchemaProps{
+							Type: "object",
+							Properties: map[string]apiextensionv1beta1.JSONSchemaProps{
+								"names": apiextensionv1beta1.JSONSchemaProps{
+									Type: "array",
+									Items: &apiextensionv1beta1.JSONSchemaPropsOrArray{
+										Schema: &apiextensionv1beta1.JSONSchemaProps{
+											Type: "string",
+										},
 									},
-									Properties: map[string]apiextensionv1beta1.JSONSchemaProps{
-										"incumbent": apiextensionv1beta1.JSONSchemaProps{
-											Type:    "integer",
-											Minimum: &zero,
+								},
+								"regions": apiextensionv1beta1.JSONSchemaProps{
+									Type: "array",
+									Items: &apiextensionv1beta1.JSONSchemaPropsOrArray{
+										Schema: &apiextensionv1beta1.JSONSchemaProps{
+											Type: "string",
 										},
-										"contender": apiextensionv1beta1.JSONSchemaProps{
+									},
+								},
+								"ordinals": apiextensionv1beta1.JSONSchemaProps{
+									Type: "array",
+									Items: &apiextensionv1beta1.JSONSchemaPropsOrArray{
+										Schema: &apiextensionv1beta1.JSONSchemaProps{
 											Type:    "integer",
 											Minimum: &zero,
 										},
@@ -118,8 +281,36 @@ var environmentValidation = apiextensionv1beta1.JSONSchemaProps{
 				},
 			},
 		},
-		"values": apiextensionv1beta1.JSONSchemaProps{
+		"type": apiextensionv1beta1.JSONSchemaProps{
+			Type: "string",
+			Enum: []apiextensionv1beta1.JSON{
+				{Raw: []byte(`"Incremental"`)},
+				{Raw: []byte(`"BlueGreen"`)},
+			},
+		},
+	},
+}
+
+// stepCapacityValidation takes either a percentage, or an absolute number
+// of replicas written as {replicas: N}.
+var stepCapacityValidation = apiextensionv1beta1.JSONSchemaProps{
+	AnyOf: []apiextensionv1beta1.JSONSchemaProps{
+		{
+			Type:    "integer",
+			Minimum: &zero,
+			Maximum: &hundred,
+		},
+		{
 			Type: "object",
+			Required: []string{
+				"replicas",
+			},
+			Properties: map[string]apiextensionv1beta1.JSONSchemaProps{
+				"replicas": apiextensionv1beta1.JSONSchemaProps{
+					Type:    "integer",
+					Minimum: &zero,
+				},
+			},
 		},
 	},
 }
@@ -0,0 +1,75 @@
+package crds
+
+import (
+	apiextensionv1beta1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var StepApproval = &apiextensionv1beta1.CustomResourceDefinition{
+	ObjectMeta: metav1.ObjectMeta{
+		Name: "stepapprovals.shipper.booking.com",
+	},
+	Spec: apiextensionv1beta1.CustomResourceDefinitionSpec{
+		Group: "shipper.booking.com",
+		Versions: []apiextensionv1beta1.CustomResourceDefinitionVersion{
+			apiextensionv1beta1.CustomResourceDefinitionVersion{
+				Name:    "v1alpha1",
+				Served:  true,
+				Storage: true,
+			},
+		},
+		Names: apiextensionv1beta1.CustomResourceDefinitionNames{
+			Plural:     "stepapprovals",
+			Singular:   "stepapproval",
+			Kind:       "StepApproval",
+			ShortNames: []string{"sa"},
+			Categories: []string{"shipper"},
+		},
+		Scope: apiextensionv1beta1.NamespaceScoped,
+		Validation: &apiextensionv1beta1.CustomResourceValidation{
+			OpenAPIV3Schema: &apiextensionv1beta1.JSONSchemaProps{
+				Properties: map[string]apiextensionv1beta1.JSONSchemaProps{
+					"spec": apiextensionv1beta1.JSONSchemaProps{
+						Type: "object",
+						Required: []string{
+							"release",
+							"step",
+						},
+						Properties: map[string]apiextensionv1beta1.JSONSchemaProps{
+							"release": apiextensionv1beta1.JSONSchemaProps{
+								Type: "string",
+							},
+							"step": apiextensionv1beta1.JSONSchemaProps{
+								Type:    "integer",
+								Minimum: &zero,
+							},
+							"approvedBy": apiextensionv1beta1.JSONSchemaProps{
+								Type: "string",
+							},
+						},
+					},
+				},
+			},
+		},
+		AdditionalPrinterColumns: []apiextensionv1beta1.CustomResourceColumnDefinition{
+			apiextensionv1beta1.CustomResourceColumnDefinition{
+				Name:        "Release",
+				Type:        "string",
+				Description: "The approved release.",
+				JSONPath:    ".spec.release",
+			},
+			apiextensionv1beta1.CustomResourceColumnDefinition{
+				Name:        "Step",
+				Type:        "integer",
+				Description: "The approved strategy step.",
+				JSONPath:    ".spec.step",
+			},
+			apiextensionv1beta1.CustomResourceColumnDefinition{
+				Name:        "Approved By",
+				Type:        "string",
+				Description: "The user that approved the step.",
+				JSONPath:    ".spec.approvedBy",
+			},
+		},
+	},
+}
@@ -58,6 +58,13 @@ var TrafficTarget = &apiextensionv1beta1.CustomResourceDefinition{
 									},
 								},
 							},
+							"strategyType": apiextensionv1beta1.JSONSchemaProps{
+								Type: "string",
+								Enum: []apiextensionv1beta1.JSON{
+									{Raw: []byte(`"Incremental"`)},
+									{Raw: []byte(`"BlueGreen"`)},
+								},
+							},
 						},
 					},
 				},
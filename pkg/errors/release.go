@@ -131,12 +131,20 @@ func NewNotEnoughClustersInRegionError(region string, required, available int) N
 type NotEnoughCapableClustersInRegionError struct {
 	region       string
 	capabilities []string
+	selector     string
 	required     int
 	available    int
 }
 
 func (e NotEnoughCapableClustersInRegionError) Error() string {
 	capabilitiesString := strings.Join(e.capabilities, ",")
+	if e.selector != "" {
+		return fmt.Sprintf(
+			"Not enough clusters in region %q with required capabilities %q and labels matching %q. Required: %d / Available: %d",
+			e.region, capabilitiesString, e.selector, e.required, e.available,
+		)
+	}
+
 	return fmt.Sprintf(
 		"Not enough clusters in region %q with required capabilities %q. Required: %d / Available: %d",
 		e.region, capabilitiesString, e.required, e.available,
@@ -147,15 +155,57 @@ func (e NotEnoughCapableClustersInRegionError) ShouldRetry() bool {
 	return false
 }
 
-func NewNotEnoughCapableClustersInRegionError(region string, capabilities []string, required, available int) error {
+func NewNotEnoughCapableClustersInRegionError(region string, capabilities []string, selector string, required, available int) error {
 	return NotEnoughCapableClustersInRegionError{
 		region:       region,
 		capabilities: capabilities,
+		selector:     selector,
 		required:     required,
 		available:    available,
 	}
 }
 
+type InvalidClusterSelectorError struct {
+	err error
+}
+
+func (e InvalidClusterSelectorError) Error() string {
+	return fmt.Sprintf("Invalid cluster selector in clusterRequirements: %s", e.err)
+}
+
+func (e InvalidClusterSelectorError) ShouldRetry() bool {
+	return false
+}
+
+func NewInvalidClusterSelectorError(err error) InvalidClusterSelectorError {
+	return InvalidClusterSelectorError{err: err}
+}
+
+type NotEnoughZonesInRegionError struct {
+	region    string
+	required  int
+	available int
+}
+
+func (e NotEnoughZonesInRegionError) Error() string {
+	return fmt.Sprintf(
+		"Not enough zones with capable clusters in region %q to spread across. Required: %d / Available: %d",
+		e.region, e.required, e.available,
+	)
+}
+
+func (e NotEnoughZonesInRegionError) ShouldRetry() bool {
+	return false
+}
+
+func NewNotEnoughZonesInRegionError(region string, required, available int) NotEnoughZonesInRegionError {
+	return NotEnoughZonesInRegionError{
+		region:    region,
+		required:  required,
+		available: available,
+	}
+}
+
 type DuplicateCapabilityRequirementError struct {
 	capability string
 }
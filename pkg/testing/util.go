@@ -130,6 +130,7 @@ func FilterActions(actions []kubetesting.Action) []kubetesting.Action {
 				"applications",
 				"capacitytargets",
 				"clusters",
+				"clusterstrategytemplates",
 				"configmaps",
 				"deployments",
 				"endpoints",
@@ -139,6 +140,8 @@ func FilterActions(actions []kubetesting.Action) []kubetesting.Action {
 				"rolloutblocks",
 				"secrets",
 				"services",
+				"stepapprovals",
+				"strategytemplates",
 				"traffictargets",
 			} {
 				if action.Matches(v, r) {
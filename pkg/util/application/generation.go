@@ -27,4 +27,9 @@ func SetHighestObservedGeneration(app *shipper.Application, generation int) {
 
 func CopyEnvironment(app *shipper.Application, rel *shipper.Release) {
 	app.Spec.Template = *(rel.Spec.Environment.DeepCopy())
+	if app.Spec.Template.StrategyRef != nil {
+		// Keep referring to the template rather than pinning the
+		// application to the strategy the release was created with.
+		app.Spec.Template.Strategy = nil
+	}
 }
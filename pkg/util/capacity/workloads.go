@@ -0,0 +1,13 @@
+package capacity
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+)
+
+// OptedOut tells whether a Deployment or StatefulSet asked not to be scaled
+// with the strategy, and to keep the replicas its chart gives it instead.
+func OptedOut(obj metav1.Object) bool {
+	return obj.GetAnnotations()[shipper.CapacityOptOutAnnotation] == shipper.True
+}
@@ -9,4 +9,5 @@ const (
 	BrokenReleaseGeneration             = "BrokenReleaseGeneration"
 	BrokenApplicationObservedGeneration = "BrokenApplicationObservedGeneration"
 	StrategyExecutionFailed             = "StrategyExecutionFailed"
+	ContenderUnhealthy                  = "ContenderUnhealthy"
 )
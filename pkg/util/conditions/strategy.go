@@ -129,11 +129,13 @@ func (sc StrategyConditionsMap) allConditionTypes() []shipper.StrategyConditionT
 }
 
 // AsReleaseStrategyState returns a ReleaseStrategyState computed from the
-// conditions in the receiver.
+// conditions in the receiver. A release is not waiting for command while any
+// of the gates is False.
 func (sc StrategyConditionsMap) AsReleaseStrategyState(
 	step int32,
 	hasIncumbent bool,
 	isLastStep bool,
+	gates ...shipper.StrategyConditionType,
 ) shipper.ReleaseStrategyState {
 
 	// States we don't know just yet are set to Unknown
@@ -208,9 +210,30 @@ func (sc StrategyConditionsMap) AsReleaseStrategyState(
 		state.WaitingForTraffic = shipper.StrategyStateFalse
 	}
 
+	// A contender that failed its step analysis is not waiting for
+	// anyone to move it forward: it is waiting for its metrics to
+	// recover.
+	failedAnalysis := sc.IsFalse(step, shipper.StrategyConditionContenderPassedAnalysis)
+
+	// Likewise for one held back by a step hook.
+	failedHook := sc.IsFalse(step, shipper.StrategyConditionContenderPassedPreStepHook) ||
+		sc.IsFalse(step, shipper.StrategyConditionContenderPassedPostStepHook)
+
+	// The same goes for any other gate that didn't open.
+	closedGate := false
+	for _, gate := range gates {
+		if sc.IsFalse(step, gate) {
+			closedGate = true
+			break
+		}
+	}
+
 	waitingForCommandFlag := !isLastStep &&
 		!waitingForCapacity &&
 		!waitingForTraffic &&
+		!failedAnalysis &&
+		!failedHook &&
+		!closedGate &&
 		achievedInstallation
 
 	if waitingForCommandFlag {
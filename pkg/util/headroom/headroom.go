@@ -0,0 +1,165 @@
+package headroom
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+)
+
+// Resources are the resources headroom is worked out for.
+var Resources = []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory}
+
+// Compute works out how much CPU and memory a cluster has left for new pods,
+// given its nodes and pods. Only nodes that can take new pods count, and so
+// do only the pods running on them that haven't terminated.
+func Compute(nodes []*corev1.Node, pods []*corev1.Pod) *shipper.ClusterHeadroom {
+	allocatable := emptyResourceList()
+	schedulable := make(map[string]struct{}, len(nodes))
+	for _, node := range nodes {
+		if node.Spec.Unschedulable {
+			continue
+		}
+
+		schedulable[node.Name] = struct{}{}
+		add(allocatable, node.Status.Allocatable)
+	}
+
+	requested := emptyResourceList()
+	for _, pod := range pods {
+		if _, ok := schedulable[pod.Spec.NodeName]; !ok {
+			continue
+		}
+
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+
+		add(requested, PodRequests(pod.Spec))
+	}
+
+	available := emptyResourceList()
+	for _, name := range Resources {
+		q := allocatable[name].DeepCopy()
+		q.Sub(requested[name])
+		if q.Sign() < 0 {
+			q = zero(name)
+		}
+		available[name] = q
+	}
+
+	return &shipper.ClusterHeadroom{
+		Allocatable: allocatable,
+		Requested:   requested,
+		Available:   available,
+	}
+}
+
+// PodRequests returns how much CPU and memory a pod requests, the same way
+// the Kubernetes scheduler does: init containers run one at a time before
+// the others, so the pod needs the most of either all of its containers or
+// any single init container.
+func PodRequests(spec corev1.PodSpec) corev1.ResourceList {
+	requests := emptyResourceList()
+	for _, container := range spec.Containers {
+		add(requests, container.Resources.Requests)
+	}
+
+	for _, container := range spec.InitContainers {
+		for _, name := range Resources {
+			q, ok := container.Resources.Requests[name]
+			if ok && q.Cmp(requests[name]) > 0 {
+				requests[name] = q.DeepCopy()
+			}
+		}
+	}
+
+	return requests
+}
+
+// Multiply returns the resources in list times n.
+func Multiply(list corev1.ResourceList, n int32) corev1.ResourceList {
+	product := emptyResourceList()
+	for _, name := range Resources {
+		q := list[name]
+		if name == corev1.ResourceCPU {
+			product[name] = *resource.NewMilliQuantity(q.MilliValue()*int64(n), resource.DecimalSI)
+		} else {
+			product[name] = *resource.NewQuantity(q.Value()*int64(n), resource.BinarySI)
+		}
+	}
+
+	return product
+}
+
+// Fits tells whether a cluster with the given headroom can take pods
+// requesting the given resources. Clusters with unknown headroom are
+// assumed to fit anything.
+func Fits(headroom *shipper.ClusterHeadroom, required corev1.ResourceList) bool {
+	if headroom == nil {
+		return true
+	}
+
+	for _, name := range Resources {
+		q, ok := required[name]
+		if !ok {
+			continue
+		}
+
+		available := headroom.Available[name]
+		if q.Cmp(available) > 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// String formats a list of resources for humans, like "cpu=2, memory=4Gi".
+func String(list corev1.ResourceList) string {
+	names := make([]string, 0, len(list))
+	for name := range list {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		q := list[corev1.ResourceName(name)]
+		parts = append(parts, fmt.Sprintf("%s=%s", name, q.String()))
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+func emptyResourceList() corev1.ResourceList {
+	list := make(corev1.ResourceList, len(Resources))
+	for _, name := range Resources {
+		list[name] = zero(name)
+	}
+	return list
+}
+
+func zero(name corev1.ResourceName) resource.Quantity {
+	if name == corev1.ResourceCPU {
+		return *resource.NewQuantity(0, resource.DecimalSI)
+	}
+	return *resource.NewQuantity(0, resource.BinarySI)
+}
+
+func add(total, list corev1.ResourceList) {
+	for _, name := range Resources {
+		q, ok := list[name]
+		if !ok {
+			continue
+		}
+
+		sum := total[name]
+		sum.Add(q)
+		total[name] = sum
+	}
+}
@@ -0,0 +1,117 @@
+package headroom
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func buildNode(name string, cpu, memory string, unschedulable bool) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec:       corev1.NodeSpec{Unschedulable: unschedulable},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(memory),
+			},
+		},
+	}
+}
+
+func buildPod(node string, phase corev1.PodPhase, cpu, memory string) *corev1.Pod {
+	return &corev1.Pod{
+		Spec: corev1.PodSpec{
+			NodeName: node,
+			Containers: []corev1.Container{
+				{
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse(cpu),
+							corev1.ResourceMemory: resource.MustParse(memory),
+						},
+					},
+				},
+			},
+		},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
+
+func TestCompute(t *testing.T) {
+	nodes := []*corev1.Node{
+		buildNode("node-a", "4", "8Gi", false),
+		buildNode("node-b", "4", "8Gi", false),
+		buildNode("node-c", "4", "8Gi", true),
+	}
+
+	pods := []*corev1.Pod{
+		buildPod("node-a", corev1.PodRunning, "1", "2Gi"),
+		buildPod("node-b", corev1.PodRunning, "500m", "1Gi"),
+		// Pods on unschedulable nodes, terminated pods and pods
+		// that aren't scheduled yet don't take up any headroom.
+		buildPod("node-c", corev1.PodRunning, "1", "1Gi"),
+		buildPod("node-a", corev1.PodSucceeded, "1", "1Gi"),
+		buildPod("", corev1.PodPending, "1", "1Gi"),
+	}
+
+	headroom := Compute(nodes, pods)
+
+	expected := map[corev1.ResourceName]string{
+		corev1.ResourceCPU:    "6500m",
+		corev1.ResourceMemory: "13Gi",
+	}
+	for name, value := range expected {
+		q := headroom.Available[name]
+		if q.Cmp(resource.MustParse(value)) != 0 {
+			t.Errorf("expected %s headroom to be %s, got %s", name, value, q.String())
+		}
+	}
+}
+
+func TestPodRequestsWithInitContainers(t *testing.T) {
+	spec := buildPod("", corev1.PodPending, "1", "1Gi").Spec
+	spec.InitContainers = []corev1.Container{
+		{
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("2"),
+				},
+			},
+		},
+	}
+
+	requests := PodRequests(spec)
+
+	cpu := requests[corev1.ResourceCPU]
+	if cpu.Cmp(resource.MustParse("2")) != 0 {
+		t.Errorf("expected the init container's cpu request to win, got %s", cpu.String())
+	}
+	memory := requests[corev1.ResourceMemory]
+	if memory.Cmp(resource.MustParse("1Gi")) != 0 {
+		t.Errorf("expected the containers' memory request to win, got %s", memory.String())
+	}
+}
+
+func TestFits(t *testing.T) {
+	headroom := Compute(
+		[]*corev1.Node{buildNode("node-a", "2", "4Gi", false)},
+		nil,
+	)
+
+	required := Multiply(PodRequests(buildPod("", corev1.PodPending, "500m", "1Gi").Spec), 4)
+	if !Fits(headroom, required) {
+		t.Errorf("expected %s to fit in %s", String(required), String(headroom.Available))
+	}
+
+	required = Multiply(PodRequests(buildPod("", corev1.PodPending, "500m", "1Gi").Spec), 5)
+	if Fits(headroom, required) {
+		t.Errorf("expected %s not to fit in %s", String(required), String(headroom.Available))
+	}
+
+	if !Fits(nil, required) {
+		t.Errorf("expected clusters with unknown headroom to fit anything")
+	}
+}
@@ -0,0 +1,63 @@
+package release
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+)
+
+// AppendHistory adds an entry to the end of a release's history, dropping
+// the oldest entries so there are at most shipper.ReleaseHistoryLimit left.
+func AppendHistory(status *shipper.ReleaseStatus, entry shipper.ReleaseHistoryEntry) {
+	status.History = append(status.History, entry)
+	if extra := len(status.History) - shipper.ReleaseHistoryLimit; extra > 0 {
+		status.History = append([]shipper.ReleaseHistoryEntry(nil), status.History[extra:]...)
+	}
+}
+
+// LastHistoryEntry returns the latest entry of the given type in a release's
+// history, or nil if there is none.
+func LastHistoryEntry(status *shipper.ReleaseStatus, entryType shipper.ReleaseHistoryEntryType) *shipper.ReleaseHistoryEntry {
+	for i := len(status.History) - 1; i >= 0; i-- {
+		if status.History[i].Type == entryType {
+			return &status.History[i]
+		}
+	}
+
+	return nil
+}
+
+// AppendAbortedHistory adds an Aborted entry about an aborted release to the
+// history of another one, as the aborted release is deleted right after. It
+// returns false if the last Aborted entry already said the same thing, and
+// nothing was added.
+func AppendAbortedHistory(status *shipper.ReleaseStatus, aborted *shipper.Release, reason string, now time.Time) bool {
+	message := fmt.Sprintf("release %q was aborted: %s", aborted.Name, reason)
+	if last := LastHistoryEntry(status, shipper.ReleaseHistoryAborted); last != nil && last.Message == message {
+		return false
+	}
+
+	AppendHistory(status, shipper.ReleaseHistoryEntry{
+		Type:     shipper.ReleaseHistoryAborted,
+		Time:     metav1.NewTime(now),
+		Step:     aborted.Spec.TargetStep,
+		StepName: StepName(aborted, aborted.Spec.TargetStep),
+		Message:  message,
+	})
+
+	return true
+}
+
+// StepName returns the name of a step in a release's strategy, or an empty
+// string if the strategy has no such step.
+func StepName(rel *shipper.Release, step int32) string {
+	strategy := rel.Spec.Environment.Strategy
+	if strategy == nil || step < 0 || int(step) >= len(strategy.Steps) {
+		return ""
+	}
+
+	return strategy.Steps[step].Name
+}
@@ -0,0 +1,34 @@
+package release
+
+import (
+	"testing"
+
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+)
+
+func TestAppendHistoryKeepsLatestEntries(t *testing.T) {
+	var status shipper.ReleaseStatus
+	for i := 0; i < shipper.ReleaseHistoryLimit+10; i++ {
+		AppendHistory(&status, shipper.ReleaseHistoryEntry{
+			Type: shipper.ReleaseHistoryTargetStepChanged,
+			Step: int32(i),
+		})
+	}
+
+	if len(status.History) != shipper.ReleaseHistoryLimit {
+		t.Fatalf("expected %d history entries, got %d", shipper.ReleaseHistoryLimit, len(status.History))
+	}
+
+	if first := status.History[0].Step; first != 10 {
+		t.Errorf("expected oldest entries to be dropped, first entry is for step %d", first)
+	}
+
+	last := LastHistoryEntry(&status, shipper.ReleaseHistoryTargetStepChanged)
+	if last == nil || last.Step != shipper.ReleaseHistoryLimit+9 {
+		t.Errorf("expected last entry to be for step %d, got %v", shipper.ReleaseHistoryLimit+9, last)
+	}
+
+	if entry := LastHistoryEntry(&status, shipper.ReleaseHistoryAborted); entry != nil {
+		t.Errorf("expected no Aborted entry, got %v", entry)
+	}
+}
@@ -0,0 +1,28 @@
+package release
+
+import (
+	"time"
+
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+)
+
+// LastProgressTime returns the last time anything happened in a release's
+// strategy: it was created, achieved a step, or any of its strategy
+// conditions changed (which includes users moving it to another step).
+func LastProgressTime(rel *shipper.Release) time.Time {
+	last := rel.CreationTimestamp.Time
+
+	if achieved := rel.Status.AchievedStep; achieved != nil && achieved.AchievedTime.After(last) {
+		last = achieved.AchievedTime.Time
+	}
+
+	if rel.Status.Strategy != nil {
+		for _, cond := range rel.Status.Strategy.Conditions {
+			if cond.LastTransitionTime.After(last) {
+				last = cond.LastTransitionTime.Time
+			}
+		}
+	}
+
+	return last
+}
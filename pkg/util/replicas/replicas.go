@@ -2,6 +2,7 @@ package replicas
 
 import (
 	"math"
+	"sort"
 )
 
 // CalculateDesiredNumberOfReplicas extracts the optimal replica count for
@@ -23,6 +24,37 @@ func CalculateDesiredReplicaCount(totalReplicaCount uint, desiredCapacityPercent
 	return uint(desiredReplicaCount)
 }
 
+// CalculateReplicasPercentage returns the smallest percentage of the given
+// totalReplicaCount that gets at least replicaCount replicas out of
+// CalculateDesiredReplicaCount. Asking for all of the replicas, or more,
+// gets 100.
+func CalculateReplicasPercentage(totalReplicaCount, replicaCount int32) int32 {
+	if replicaCount <= 0 {
+		return 0
+	}
+	if totalReplicaCount <= 0 || replicaCount >= totalReplicaCount {
+		return 100
+	}
+
+	// CalculateDesiredReplicaCount gets to replicaCount as soon as the
+	// percentage goes past the one that gets exactly replicaCount - 1.
+	return (replicaCount-1)*100/totalReplicaCount + 1
+}
+
+// CalculateDesiredReplicaCountFromReplicas clamps an absolute number of
+// replicas asked for by a strategy step to the given totalReplicaCount, so
+// a step never asks for more replicas than the release has.
+func CalculateDesiredReplicaCountFromReplicas(totalReplicaCount, replicaCount int32) int32 {
+	if replicaCount > totalReplicaCount {
+		return totalReplicaCount
+	}
+	if replicaCount < 0 {
+		return 0
+	}
+
+	return replicaCount
+}
+
 // AchievedDesiredCapacity verifies whether the given currentReplicaCount
 // and totalReplicaCount match the given desiredCapacityPercentage.
 //
@@ -37,3 +69,112 @@ func AchievedDesiredReplicaPercentage(totalReplicaCount, currentReplicaCount, de
 
 	return uint(currentReplicaCount) == CalculateDesiredReplicaCount(uint(totalReplicaCount), float64(desiredPercentage))
 }
+
+// CalculateReleasePodTarget returns how many of a release's pods should be
+// labeled to receive traffic for it to get releaseWeight out of totalWeight,
+// given that the application has totalPods pods across all of its releases.
+// It rounds up to the nearest pod, and never goes over the number of pods
+// the release actually has.
+func CalculateReleasePodTarget(releasePods int, releaseWeight uint32, totalPods int, totalWeight uint32) int {
+	// What percentage of the entire fleet (across all releases) should
+	// this set of pods represent.
+	var targetPercent float64
+	if totalWeight == 0 {
+		targetPercent = 0
+	} else {
+		targetPercent = float64(releaseWeight) / float64(totalWeight) * 100
+	}
+
+	// Round up to the nearest pod, clamped to the number of pods this
+	// release has.
+	targetPods := int(CalculateDesiredReplicaCount(uint(totalPods), float64(targetPercent)))
+	targetPods = int(math.Min(float64(releasePods), float64(targetPods)))
+
+	return targetPods
+}
+
+// WeightedReplicaCounts splits the replicas of clusters in a region that
+// weights them among those clusters according to their scheduler weights.
+// The region as a whole still runs replicaCount replicas per cluster.
+func WeightedReplicaCounts(replicaCount int32, weights map[string]int32) map[string]int32 {
+	return DistributeReplicaCount(replicaCount*int32(len(weights)), weights)
+}
+
+// DistributeReplicaCount splits totalReplicaCount among the given keys in
+// proportion to their weights. Replicas that don't divide evenly go to the
+// keys with the largest remainders, ties broken in key order, so the counts
+// always add up to totalReplicaCount. With no weight at all, replicas are
+// split evenly.
+//
+// As long as there are enough replicas to go round, every key gets at least
+// one, even those with no weight: keys are clusters a release is scheduled
+// on, and they all get traffic. The replicas they get are taken from the
+// keys with the most.
+func DistributeReplicaCount(totalReplicaCount int32, weights map[string]int32) map[string]int32 {
+	keys := make([]string, 0, len(weights))
+	var totalWeight int64
+	for key, weight := range weights {
+		keys = append(keys, key)
+		if weight > 0 {
+			totalWeight += int64(weight)
+		}
+	}
+	sort.Strings(keys)
+
+	counts := make(map[string]int32, len(keys))
+	if len(keys) == 0 {
+		return counts
+	}
+
+	even := totalWeight == 0
+	if even {
+		totalWeight = int64(len(keys))
+	}
+	weightOf := func(key string) int64 {
+		if even {
+			return 1
+		} else if weights[key] < 0 {
+			return 0
+		}
+		return int64(weights[key])
+	}
+
+	remainders := make(map[string]int64, len(keys))
+	var assigned int32
+	for _, key := range keys {
+		share := int64(totalReplicaCount) * weightOf(key)
+		counts[key] = int32(share / totalWeight)
+		remainders[key] = share % totalWeight
+		assigned += counts[key]
+	}
+
+	byRemainder := append([]string{}, keys...)
+	sort.SliceStable(byRemainder, func(i, j int) bool {
+		return remainders[byRemainder[i]] > remainders[byRemainder[j]]
+	})
+	for i := int32(0); i < totalReplicaCount-assigned; i++ {
+		counts[byRemainder[i]]++
+	}
+
+	if totalReplicaCount < int32(len(keys)) {
+		return counts
+	}
+
+	for _, key := range keys {
+		if counts[key] > 0 {
+			continue
+		}
+
+		largest := keys[0]
+		for _, other := range keys[1:] {
+			if counts[other] > counts[largest] {
+				largest = other
+			}
+		}
+
+		counts[largest]--
+		counts[key]++
+	}
+
+	return counts
+}
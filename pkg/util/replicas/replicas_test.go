@@ -0,0 +1,131 @@
+package replicas
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCalculateReplicasPercentage(t *testing.T) {
+	tests := []struct {
+		name              string
+		totalReplicaCount int32
+		replicaCount      int32
+		expected          int32
+	}{
+		{"no replicas", 10, 0, 0},
+		{"negative replicas", 10, -1, 0},
+		{"one of three", 3, 1, 1},
+		{"two of three", 3, 2, 34},
+		{"one of four", 4, 1, 1},
+		{"three of four", 4, 3, 51},
+		{"one of 400", 400, 1, 1},
+		{"two of 400", 400, 2, 1},
+		{"five of 400", 400, 5, 2},
+		{"all replicas", 3, 3, 100},
+		{"more than all replicas", 3, 5, 100},
+		{"no total", 0, 1, 100},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CalculateReplicasPercentage(tt.totalReplicaCount, tt.replicaCount)
+			if got != tt.expected {
+				t.Fatalf("expected %d%% of %d replicas to get %d replicas, got %d%%",
+					tt.expected, tt.totalReplicaCount, tt.replicaCount, got)
+			}
+
+			if tt.replicaCount <= 0 || tt.replicaCount >= tt.totalReplicaCount {
+				return
+			}
+
+			// The percentage has to get at least as many replicas, and
+			// one percent less must not.
+			desired := CalculateDesiredReplicaCount(uint(tt.totalReplicaCount), float64(got))
+			if desired < uint(tt.replicaCount) {
+				t.Errorf("expected %d%% of %d replicas to be at least %d, got %d",
+					got, tt.totalReplicaCount, tt.replicaCount, desired)
+			}
+
+			fewer := CalculateDesiredReplicaCount(uint(tt.totalReplicaCount), float64(got-1))
+			if fewer >= uint(tt.replicaCount) {
+				t.Errorf("expected %d%% to be the smallest percentage of %d replicas to get %d, but %d%% gets %d",
+					got, tt.totalReplicaCount, tt.replicaCount, got-1, fewer)
+			}
+		})
+	}
+}
+
+func TestDistributeReplicaCount(t *testing.T) {
+	tests := []struct {
+		name              string
+		totalReplicaCount int32
+		weights           map[string]int32
+		expected          map[string]int32
+	}{
+		{
+			"no keys",
+			10,
+			map[string]int32{},
+			map[string]int32{},
+		},
+		{
+			"proportional to weights",
+			20,
+			map[string]int32{"a": 70, "b": 30},
+			map[string]int32{"a": 14, "b": 6},
+		},
+		{
+			"remainders go to the largest ones",
+			10,
+			map[string]int32{"a": 1, "b": 1, "c": 2},
+			map[string]int32{"a": 3, "b": 2, "c": 5},
+		},
+		{
+			"no weight at all splits evenly",
+			6,
+			map[string]int32{"a": 0, "b": 0, "c": 0},
+			map[string]int32{"a": 2, "b": 2, "c": 2},
+		},
+		{
+			"zero weight still gets a replica",
+			4,
+			map[string]int32{"a": 100, "b": 0},
+			map[string]int32{"a": 3, "b": 1},
+		},
+		{
+			"negative weight still gets a replica",
+			4,
+			map[string]int32{"a": 100, "b": -5},
+			map[string]int32{"a": 3, "b": 1},
+		},
+		{
+			"not enough replicas to go round",
+			1,
+			map[string]int32{"a": 100, "b": 0},
+			map[string]int32{"a": 1, "b": 0},
+		},
+		{
+			"no replicas",
+			0,
+			map[string]int32{"a": 100, "b": 100},
+			map[string]int32{"a": 0, "b": 0},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DistributeReplicaCount(tt.totalReplicaCount, tt.weights)
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestWeightedReplicaCounts(t *testing.T) {
+	got := WeightedReplicaCounts(10, map[string]int32{"a": 300, "b": 100})
+	expected := map[string]int32{"a": 15, "b": 5}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
@@ -0,0 +1,47 @@
+package strategy
+
+import (
+	"k8s.io/apimachinery/pkg/labels"
+
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+	listers "github.com/bookingcom/shipper/pkg/client/listers/shipper/v1alpha1"
+	shippererrors "github.com/bookingcom/shipper/pkg/errors"
+)
+
+// StepRequiresApproval tells whether a strategy step can only be targeted
+// once it has been approved.
+func StepRequiresApproval(strategy *shipper.RolloutStrategy, step int32) bool {
+	if strategy == nil || step < 0 || int(step) >= len(strategy.Steps) {
+		return false
+	}
+
+	return strategy.Steps[step].RequiresApproval
+}
+
+// FindStepApproval returns the oldest StepApproval for the given release and
+// step, or nil if there is none.
+func FindStepApproval(
+	lister listers.StepApprovalLister,
+	rel *shipper.Release,
+	step int32,
+) (*shipper.StepApproval, error) {
+	selector := labels.Everything()
+	approvals, err := lister.StepApprovals(rel.Namespace).List(selector)
+	if err != nil {
+		return nil, shippererrors.NewKubeclientListError(
+			shipper.SchemeGroupVersion.WithKind("StepApproval"),
+			rel.Namespace, selector, err)
+	}
+
+	var oldest *shipper.StepApproval
+	for _, approval := range approvals {
+		if approval.Spec.Release != rel.Name || approval.Spec.Step != step {
+			continue
+		}
+		if oldest == nil || approval.CreationTimestamp.Before(&oldest.CreationTimestamp) {
+			oldest = approval
+		}
+	}
+
+	return oldest, nil
+}
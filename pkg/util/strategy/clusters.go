@@ -0,0 +1,106 @@
+package strategy
+
+import (
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+)
+
+// HasClusterSteps returns true if any step in the strategy targets a subset
+// of the release's clusters.
+func HasClusterSteps(strategy *shipper.RolloutStrategy) bool {
+	if strategy == nil {
+		return false
+	}
+	for _, step := range strategy.Steps {
+		if step.Clusters != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// StepSelectsCluster returns true if the strategy step applies to a cluster
+// with the given name, region and ordinal.
+func StepSelectsCluster(step shipper.RolloutStrategyStep, name, region string, ordinal int) bool {
+	if step.Clusters == nil {
+		return true
+	}
+	for _, n := range step.Clusters.Names {
+		if n == name {
+			return true
+		}
+	}
+	for _, r := range step.Clusters.Regions {
+		if region != "" && r == region {
+			return true
+		}
+	}
+	for _, o := range step.Clusters.Ordinals {
+		if int(o) == ordinal {
+			return true
+		}
+	}
+	return false
+}
+
+// ClusterStepIndices returns the strategy step each of the given clusters
+// should be at when the release targets targetStep: the last step up to
+// and including targetStep that selects the cluster, or -1 if there isn't
+// one. Clusters are expected to be sorted, as ordinals index into them.
+func ClusterStepIndices(
+	strategy *shipper.RolloutStrategy,
+	targetStep int32,
+	clusters []string,
+	regions map[string]string,
+) map[string]int32 {
+	steps := make(map[string]int32, len(clusters))
+	for i, cluster := range clusters {
+		steps[cluster] = -1
+		for s := targetStep; s >= 0; s-- {
+			if StepSelectsCluster(strategy.Steps[s], cluster, regions[cluster], i) {
+				steps[cluster] = s
+				break
+			}
+		}
+	}
+	return steps
+}
+
+// StepValue picks the contender or incumbent value of a step. Step -1 stands
+// for the state before any step applies: the incumbent gets everything and
+// the contender nothing.
+func StepValue(strategy *shipper.RolloutStrategy, step int32, isContender bool, pick func(shipper.RolloutStrategyStep) shipper.RolloutStrategyStepValue) int32 {
+	if step < 0 {
+		if isContender {
+			return 0
+		}
+		return 100
+	}
+	value := pick(strategy.Steps[step])
+	if isContender {
+		return value.Contender
+	}
+	return value.Incumbent
+}
+
+// StepReplicas picks the absolute number of replicas the contender or
+// incumbent capacity of a step asks for, or nil if it asks for a percentage
+// of them, as StepValue returns. Step -1 never asks for a number of
+// replicas.
+func StepReplicas(strategy *shipper.RolloutStrategy, step int32, isContender bool) *int32 {
+	if step < 0 {
+		return nil
+	}
+	value := strategy.Steps[step].Capacity
+	if isContender {
+		return value.ContenderReplicas
+	}
+	return value.IncumbentReplicas
+}
+
+func PickCapacity(step shipper.RolloutStrategyStep) shipper.RolloutStrategyStepValue {
+	return step.Capacity
+}
+
+func PickTraffic(step shipper.RolloutStrategyStep) shipper.RolloutStrategyStepValue {
+	return step.Traffic
+}
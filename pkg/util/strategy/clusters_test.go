@@ -0,0 +1,94 @@
+package strategy
+
+import (
+	"testing"
+
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+)
+
+var clusterByCluster = shipper.RolloutStrategy{
+	Steps: []shipper.RolloutStrategyStep{
+		{
+			Name:     "staging",
+			Capacity: shipper.RolloutStrategyStepValue{Incumbent: 100, Contender: 1},
+			Traffic:  shipper.RolloutStrategyStepValue{Incumbent: 100, Contender: 0},
+		},
+		{
+			Name:     "us-east full on",
+			Capacity: shipper.RolloutStrategyStepValue{Incumbent: 0, Contender: 100},
+			Traffic:  shipper.RolloutStrategyStepValue{Incumbent: 0, Contender: 100},
+			Clusters: &shipper.RolloutStrategyStepClusters{
+				Names: []string{"kube-us-east1-a"},
+			},
+		},
+		{
+			Name:     "eu-west full on",
+			Capacity: shipper.RolloutStrategyStepValue{Incumbent: 0, Contender: 100},
+			Traffic:  shipper.RolloutStrategyStepValue{Incumbent: 0, Contender: 100},
+			Clusters: &shipper.RolloutStrategyStepClusters{
+				Regions: []string{"eu-west"},
+			},
+		},
+	},
+}
+
+func TestClusterStepIndices(t *testing.T) {
+	clusters := []string{"kube-eu-west2-b", "kube-us-east1-a"}
+	regions := map[string]string{
+		"kube-eu-west2-b": "eu-west",
+		"kube-us-east1-a": "us-east",
+	}
+
+	tests := []struct {
+		name       string
+		strategy   shipper.RolloutStrategy
+		targetStep int32
+		expected   map[string]int32
+	}{
+		{
+			name:       "steps without cluster selectors apply everywhere",
+			strategy:   clusterByCluster,
+			targetStep: 0,
+			expected:   map[string]int32{"kube-eu-west2-b": 0, "kube-us-east1-a": 0},
+		},
+		{
+			name:       "selecting by name leaves other clusters behind",
+			strategy:   clusterByCluster,
+			targetStep: 1,
+			expected:   map[string]int32{"kube-eu-west2-b": 0, "kube-us-east1-a": 1},
+		},
+		{
+			name:       "selecting by region",
+			strategy:   clusterByCluster,
+			targetStep: 2,
+			expected:   map[string]int32{"kube-eu-west2-b": 2, "kube-us-east1-a": 1},
+		},
+		{
+			name: "selecting by ordinal, unselected clusters not started",
+			strategy: shipper.RolloutStrategy{
+				Steps: []shipper.RolloutStrategyStep{
+					{
+						Name:     "first cluster",
+						Capacity: shipper.RolloutStrategyStepValue{Incumbent: 0, Contender: 100},
+						Traffic:  shipper.RolloutStrategyStepValue{Incumbent: 0, Contender: 100},
+						Clusters: &shipper.RolloutStrategyStepClusters{
+							Ordinals: []int32{0},
+						},
+					},
+				},
+			},
+			targetStep: 0,
+			expected:   map[string]int32{"kube-eu-west2-b": 0, "kube-us-east1-a": -1},
+		},
+	}
+
+	for _, tt := range tests {
+		got := ClusterStepIndices(&tt.strategy, tt.targetStep, clusters, regions)
+		for cluster, step := range tt.expected {
+			if got[cluster] != step {
+				t.Errorf("%s: expected cluster %q to be at step %d, got %d",
+					tt.name, cluster, step, got[cluster])
+			}
+		}
+	}
+}
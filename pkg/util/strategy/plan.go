@@ -0,0 +1,180 @@
+package strategy
+
+import (
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+	"github.com/bookingcom/shipper/pkg/util/replicas"
+)
+
+// ReleasePlan is what a release looks like in a cluster at a strategy step.
+type ReleasePlan struct {
+	// Capacity is the percentage of the release's replicas the step asks
+	// for, and Pods how many pods that works out to. For steps asking for
+	// an absolute number of replicas, Pods is that number, and Capacity
+	// the percentage it works out to.
+	Capacity int32
+	Pods     int32
+
+	// TrafficWeight is the weight the step gives the release, and
+	// TrafficPercent the share of the traffic it actually gets once that
+	// weight is turned into a number of pods receiving traffic.
+	TrafficWeight  uint32
+	TrafficPercent float64
+}
+
+// ClusterPlan is what the contender and the incumbent look like in a
+// cluster at a strategy step.
+type ClusterPlan struct {
+	Name string
+
+	// Step is the step the cluster is at. It lags behind for clusters the
+	// step does not select, and is -1 for clusters no step selected yet.
+	Step int32
+
+	Contender ReleasePlan
+	// Incumbent is nil when planning for the first release of an
+	// application.
+	Incumbent *ReleasePlan
+}
+
+// StepPlan is what every cluster looks like once a release has achieved a
+// strategy step.
+type StepPlan struct {
+	Step     int32
+	Name     string
+	Clusters []ClusterPlan
+}
+
+// Plan works out how many pods the contender and the incumbent have, and how
+// much traffic they get, in every cluster at every step of a strategy. It
+// rounds pods the same way the capacity and traffic controllers do:
+// capacity is rounded up to the next pod, and so is the number of pods
+// labeled to receive traffic, which can't go over the number of pods a
+// release has. replicaCounts is the number of replicas the release runs in
+// each cluster, which is what the chart asks for unless the cluster's region
+// weights them, and clusters are expected to be sorted, as cluster ordinals
+// index into them.
+func Plan(
+	strategy *shipper.RolloutStrategy,
+	replicaCounts map[string]int32,
+	clusters []string,
+	regions map[string]string,
+	hasIncumbent bool,
+) []StepPlan {
+	plans := make([]StepPlan, 0, len(strategy.Steps))
+	for i, step := range strategy.Steps {
+		targetStep := int32(i)
+
+		var steps map[string]int32
+		if HasClusterSteps(strategy) {
+			steps = ClusterStepIndices(strategy, targetStep, clusters, regions)
+		}
+
+		plan := StepPlan{
+			Step:     targetStep,
+			Name:     step.Name,
+			Clusters: make([]ClusterPlan, 0, len(clusters)),
+		}
+
+		for _, cluster := range clusters {
+			clusterStep, ok := steps[cluster]
+			if !ok {
+				clusterStep = targetStep
+			}
+
+			plan.Clusters = append(plan.Clusters, planCluster(
+				strategy, clusterStep, cluster, replicaCounts[cluster], hasIncumbent))
+		}
+
+		plans = append(plans, plan)
+	}
+
+	return plans
+}
+
+func planCluster(
+	strategy *shipper.RolloutStrategy,
+	step int32,
+	cluster string,
+	replicaCount int32,
+	hasIncumbent bool,
+) ClusterPlan {
+	planRelease := func(isContender bool) *ReleasePlan {
+		capacity := StepValue(strategy, step, isContender, PickCapacity)
+		pods := int32(replicas.CalculateDesiredReplicaCount(uint(replicaCount), float64(capacity)))
+		if stepReplicas := StepReplicas(strategy, step, isContender); stepReplicas != nil {
+			pods = replicas.CalculateDesiredReplicaCountFromReplicas(replicaCount, *stepReplicas)
+			capacity = replicas.CalculateReplicasPercentage(replicaCount, pods)
+		}
+
+		return &ReleasePlan{
+			Capacity:      capacity,
+			Pods:          pods,
+			TrafficWeight: uint32(StepValue(strategy, step, isContender, PickTraffic)),
+		}
+	}
+
+	releases := []*ReleasePlan{planRelease(true)}
+	if hasIncumbent {
+		releases = append(releases, planRelease(false))
+	}
+
+	if strategy.Type == shipper.RolloutStrategyTypeBlueGreen {
+		planBlueGreenTraffic(releases)
+	} else {
+		planIncrementalTraffic(releases)
+	}
+
+	plan := ClusterPlan{
+		Name:      cluster,
+		Step:      step,
+		Contender: *releases[0],
+	}
+	if hasIncumbent {
+		plan.Incumbent = releases[1]
+	}
+
+	return plan
+}
+
+// planIncrementalTraffic splits traffic according to how many pods of each
+// release get labeled to receive it, as every one of them gets the same
+// share.
+func planIncrementalTraffic(releases []*ReleasePlan) {
+	var totalPods int
+	var totalWeight uint32
+	for _, release := range releases {
+		totalPods += int(release.Pods)
+		totalWeight += release.TrafficWeight
+	}
+
+	podsLabeled := make([]int, len(releases))
+	var totalPodsLabeled int
+	for i, release := range releases {
+		podsLabeled[i] = replicas.CalculateReleasePodTarget(
+			int(release.Pods), release.TrafficWeight, totalPods, totalWeight)
+		totalPodsLabeled += podsLabeled[i]
+	}
+
+	if totalPodsLabeled == 0 {
+		return
+	}
+
+	for i, release := range releases {
+		release.TrafficPercent = float64(podsLabeled[i]) / float64(totalPodsLabeled) * 100
+	}
+}
+
+// planBlueGreenTraffic sends all of the traffic to the release with the
+// highest weight, if any.
+func planBlueGreenTraffic(releases []*ReleasePlan) {
+	var active *ReleasePlan
+	for _, release := range releases {
+		if release.TrafficWeight > 0 && (active == nil || release.TrafficWeight > active.TrafficWeight) {
+			active = release
+		}
+	}
+
+	if active != nil {
+		active.TrafficPercent = 100
+	}
+}
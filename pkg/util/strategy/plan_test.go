@@ -0,0 +1,140 @@
+package strategy
+
+import (
+	"testing"
+
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+	shippertesting "github.com/bookingcom/shipper/pkg/testing"
+)
+
+func TestPlan(t *testing.T) {
+	strategy := buildStrategy()
+	clusters := []string{"kube-eu-west2-b", "kube-us-east1-a"}
+
+	plans := Plan(strategy, sameReplicaCounts(3, clusters), clusters, nil, true)
+
+	// With 3 replicas, 10% of capacity is a whole pod for the contender,
+	// and 90% is all 3 pods for the incumbent. Out of those 4 pods, the
+	// contender gets 1 labeled for traffic, so it gets a quarter of it
+	// rather than the 10% the step asks for.
+	canary := ClusterPlan{
+		Name: "kube-us-east1-a",
+		Step: 1,
+		Contender: ReleasePlan{
+			Capacity:       10,
+			Pods:           1,
+			TrafficWeight:  10,
+			TrafficPercent: 25,
+		},
+		Incumbent: &ReleasePlan{
+			Capacity:       90,
+			Pods:           3,
+			TrafficWeight:  90,
+			TrafficPercent: 75,
+		},
+	}
+
+	if len(plans) != len(strategy.Steps) {
+		t.Fatalf("expected a plan for each of the %d steps, got %d", len(strategy.Steps), len(plans))
+	}
+
+	if eq, diff := shippertesting.DeepEqualDiff(canary, plans[1].Clusters[1]); !eq {
+		t.Errorf("unexpected plan for the canary step:\n%s", diff)
+	}
+
+	fullOn := plans[2].Clusters[0]
+	if fullOn.Contender.Pods != 3 || fullOn.Contender.TrafficPercent != 100 ||
+		fullOn.Incumbent.Pods != 0 || fullOn.Incumbent.TrafficPercent != 0 {
+		t.Errorf("expected the contender to get all pods and traffic in the last step, got %+v and %+v",
+			fullOn.Contender, *fullOn.Incumbent)
+	}
+}
+
+func TestPlanBlueGreen(t *testing.T) {
+	strategy := buildStrategy()
+	strategy.Type = shipper.RolloutStrategyTypeBlueGreen
+
+	clusters := []string{"kube-us-east1-a"}
+	plans := Plan(strategy, sameReplicaCounts(3, clusters), clusters, nil, true)
+
+	canary := plans[1].Clusters[0]
+	if canary.Contender.TrafficPercent != 0 || canary.Incumbent.TrafficPercent != 100 {
+		t.Errorf("expected the incumbent to get all of the traffic, got %v%% and %v%%",
+			canary.Contender.TrafficPercent, canary.Incumbent.TrafficPercent)
+	}
+}
+
+// TestPlanReplicas verifies that steps asking for an absolute number of
+// replicas get that many pods, whatever the size of the release.
+func TestPlanReplicas(t *testing.T) {
+	strategy := buildStrategy()
+	replicas := int32(1)
+	strategy.Steps[0].Capacity = shipper.RolloutStrategyStepValue{Incumbent: 100, ContenderReplicas: &replicas}
+
+	clusters := []string{"kube-us-east1-a"}
+	for _, replicaCount := range []int32{3, 400} {
+		plans := Plan(strategy, sameReplicaCounts(replicaCount, clusters), clusters, nil, true)
+
+		staging := plans[0].Clusters[0].Contender
+		if staging.Pods != 1 {
+			t.Errorf("expected the contender to get 1 pod out of %d, got %d", replicaCount, staging.Pods)
+		}
+	}
+}
+
+// TestPlanClusterReplicaCounts verifies that every cluster is planned for
+// with its own number of replicas.
+func TestPlanClusterReplicaCounts(t *testing.T) {
+	strategy := buildStrategy()
+	clusters := []string{"kube-eu-west2-b", "kube-us-east1-a"}
+	replicaCounts := map[string]int32{
+		"kube-eu-west2-b": 15,
+		"kube-us-east1-a": 5,
+	}
+
+	plans := Plan(strategy, replicaCounts, clusters, nil, true)
+
+	fullOn := plans[2]
+	for i, cluster := range clusters {
+		if pods := fullOn.Clusters[i].Contender.Pods; pods != replicaCounts[cluster] {
+			t.Errorf("expected the contender to get all %d pods in %q, got %d",
+				replicaCounts[cluster], cluster, pods)
+		}
+	}
+}
+
+func TestPlanClusterSteps(t *testing.T) {
+	clusters := []string{"kube-eu-west2-b", "kube-us-east1-a"}
+	regions := map[string]string{
+		"kube-eu-west2-b": "eu-west",
+		"kube-us-east1-a": "us-east",
+	}
+
+	plans := Plan(&clusterByCluster, sameReplicaCounts(4, clusters), clusters, regions, false)
+
+	usEastFullOn := plans[1]
+	if usEastFullOn.Clusters[0].Step != 0 || usEastFullOn.Clusters[1].Step != 1 {
+		t.Fatalf("expected only kube-us-east1-a to move on, got steps %d and %d",
+			usEastFullOn.Clusters[0].Step, usEastFullOn.Clusters[1].Step)
+	}
+
+	// Without an incumbent, the contender gets all of the traffic as soon
+	// as it gets any weight at all.
+	if usEastFullOn.Clusters[0].Contender.TrafficPercent != 0 ||
+		usEastFullOn.Clusters[1].Contender.TrafficPercent != 100 {
+		t.Errorf("unexpected traffic for the contender: got %v%% and %v%%",
+			usEastFullOn.Clusters[0].Contender.TrafficPercent,
+			usEastFullOn.Clusters[1].Contender.TrafficPercent)
+	}
+	if usEastFullOn.Clusters[0].Incumbent != nil {
+		t.Errorf("expected no incumbent plan, got %+v", *usEastFullOn.Clusters[0].Incumbent)
+	}
+}
+
+func sameReplicaCounts(replicaCount int32, clusters []string) map[string]int32 {
+	replicaCounts := make(map[string]int32, len(clusters))
+	for _, cluster := range clusters {
+		replicaCounts[cluster] = replicaCount
+	}
+	return replicaCounts
+}
@@ -0,0 +1,37 @@
+package strategy
+
+import (
+	"fmt"
+
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+	listers "github.com/bookingcom/shipper/pkg/client/listers/shipper/v1alpha1"
+	shippererrors "github.com/bookingcom/shipper/pkg/errors"
+)
+
+// ResolveStrategyReference returns a copy of the strategy a StrategyReference
+// points at. StrategyTemplates are looked up in the given namespace.
+func ResolveStrategyReference(
+	ref *shipper.StrategyReference,
+	namespace string,
+	stLister listers.StrategyTemplateLister,
+	cstLister listers.ClusterStrategyTemplateLister,
+) (*shipper.RolloutStrategy, error) {
+	switch ref.Kind {
+	case "", shipper.StrategyTemplateKind:
+		st, err := stLister.StrategyTemplates(namespace).Get(ref.Name)
+		if err != nil {
+			return nil, shippererrors.NewKubeclientGetError(namespace, ref.Name, err).
+				WithShipperKind(shipper.StrategyTemplateKind)
+		}
+		return st.Spec.DeepCopy(), nil
+	case shipper.ClusterStrategyTemplateKind:
+		cst, err := cstLister.Get(ref.Name)
+		if err != nil {
+			return nil, shippererrors.NewKubeclientGetError("", ref.Name, err).
+				WithShipperKind(shipper.ClusterStrategyTemplateKind)
+		}
+		return cst.Spec.DeepCopy(), nil
+	default:
+		return nil, shippererrors.NewUnrecoverableError(fmt.Errorf("unknown strategy template kind %q", ref.Kind))
+	}
+}
@@ -0,0 +1,247 @@
+package strategy
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"text/template"
+
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+)
+
+// ValidateStrategy checks a rollout strategy for values the release
+// controller can't work with. Errors about a particular step mention its
+// index.
+func ValidateStrategy(strategy *shipper.RolloutStrategy) error {
+	if len(strategy.Steps) == 0 {
+		return fmt.Errorf("strategy must have at least one step")
+	}
+
+	switch strategy.Type {
+	case "", shipper.RolloutStrategyTypeIncremental, shipper.RolloutStrategyTypeBlueGreen:
+	default:
+		return fmt.Errorf(
+			"type must be either %s or %s, got %q",
+			shipper.RolloutStrategyTypeIncremental, shipper.RolloutStrategyTypeBlueGreen, strategy.Type,
+		)
+	}
+
+	if seconds := strategy.ProgressDeadlineSeconds; seconds != nil && *seconds < 0 {
+		return fmt.Errorf("progressDeadlineSeconds must not be negative, got %d", *seconds)
+	}
+
+	names := make(map[string]int, len(strategy.Steps))
+	for i, step := range strategy.Steps {
+		if step.Name == "" {
+			return fmt.Errorf("step [%d]: name must not be empty", i)
+		}
+		if j, ok := names[step.Name]; ok {
+			return fmt.Errorf("step [%d]: name %q is already used by step [%d]", i, step.Name, j)
+		}
+		names[step.Name] = i
+
+		if err := validateStep(step); err != nil {
+			return fmt.Errorf("step [%d]: %s", i, err)
+		}
+
+		if strategy.Type == shipper.RolloutStrategyTypeBlueGreen {
+			if err := validateBlueGreenStep(step); err != nil {
+				return fmt.Errorf("step [%d]: %s", i, err)
+			}
+		}
+	}
+
+	last := len(strategy.Steps) - 1
+	lastStep := strategy.Steps[last]
+	if lastStep.Capacity.ContenderReplicas != nil || lastStep.Capacity.IncumbentReplicas != nil {
+		return fmt.Errorf(
+			"step [%d]: the last step must have 100 contender and 0 incumbent capacity as percentages, not replicas",
+			last,
+		)
+	}
+	if lastStep.Clusters != nil {
+		// Clusters no step selects would never move over to the
+		// contender, even though the release completes.
+		return fmt.Errorf("step [%d]: the last step must apply to every cluster, not select clusters", last)
+	}
+	if lastStep.Capacity.Contender != 100 || lastStep.Capacity.Incumbent != 0 {
+		return fmt.Errorf(
+			"step [%d]: the last step must have 100 contender and 0 incumbent capacity, got %d and %d",
+			last, lastStep.Capacity.Contender, lastStep.Capacity.Incumbent,
+		)
+	}
+	if lastStep.Traffic.Contender == 0 || lastStep.Traffic.Incumbent != 0 {
+		return fmt.Errorf(
+			"step [%d]: the last step must send all traffic to the contender, got weights %d and %d",
+			last, lastStep.Traffic.Contender, lastStep.Traffic.Incumbent,
+		)
+	}
+
+	return nil
+}
+
+// ValidateTargetStep checks that a release targets a step its strategy
+// actually has.
+func ValidateTargetStep(strategy *shipper.RolloutStrategy, targetStep int32) error {
+	if targetStep < 0 || int(targetStep) >= len(strategy.Steps) {
+		return fmt.Errorf(
+			"targetStep %d is out of range, strategy has %d steps",
+			targetStep, len(strategy.Steps),
+		)
+	}
+
+	return nil
+}
+
+func validateStep(step shipper.RolloutStrategyStep) error {
+	if err := validatePercentage("capacity.incumbent", step.Capacity.Incumbent); err != nil {
+		return err
+	}
+	if err := validatePercentage("capacity.contender", step.Capacity.Contender); err != nil {
+		return err
+	}
+	if err := validateReplicas("capacity.incumbent.replicas", step.Capacity.IncumbentReplicas); err != nil {
+		return err
+	}
+	if err := validateReplicas("capacity.contender.replicas", step.Capacity.ContenderReplicas); err != nil {
+		return err
+	}
+	if step.Traffic.IncumbentReplicas != nil || step.Traffic.ContenderReplicas != nil {
+		return fmt.Errorf("traffic must be given as weights, not replicas")
+	}
+	if step.Traffic.Incumbent < 0 {
+		return fmt.Errorf("traffic.incumbent must not be negative, got %d", step.Traffic.Incumbent)
+	}
+	if step.Traffic.Contender < 0 {
+		return fmt.Errorf("traffic.contender must not be negative, got %d", step.Traffic.Contender)
+	}
+
+	if step.Pause != nil && step.Pause.Duration < 0 {
+		return fmt.Errorf("pause must not be negative, got %s", step.Pause.Duration)
+	}
+
+	if seconds := step.ProgressDeadlineSeconds; seconds != nil && *seconds < 0 {
+		return fmt.Errorf("progressDeadlineSeconds must not be negative, got %d", *seconds)
+	}
+
+	if step.Analysis != nil {
+		if err := validateAnalysis(step.Analysis); err != nil {
+			return fmt.Errorf("analysis: %s", err)
+		}
+	}
+
+	if step.Hooks != nil {
+		if err := validateHookURL("hooks.preStep", step.Hooks.PreStep); err != nil {
+			return err
+		}
+		if err := validateHookURL("hooks.postStep", step.Hooks.PostStep); err != nil {
+			return err
+		}
+	}
+
+	if step.Clusters != nil {
+		for _, ordinal := range step.Clusters.Ordinals {
+			if ordinal < 0 {
+				return fmt.Errorf("clusters.ordinals must not be negative, got %d", ordinal)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateBlueGreenStep makes sure a step sends all of the traffic to
+// either release, and that the release getting it is fully scaled up, as
+// traffic is flipped from one release to the other in one go.
+func validateBlueGreenStep(step shipper.RolloutStrategyStep) error {
+	if step.Traffic.Contender != 0 && step.Traffic.Incumbent != 0 {
+		return fmt.Errorf(
+			"blue/green steps must send all traffic to either the contender or the incumbent, got weights %d and %d",
+			step.Traffic.Contender, step.Traffic.Incumbent,
+		)
+	}
+
+	if step.Traffic.Contender != 0 && step.Capacity.Contender != 100 {
+		return fmt.Errorf(
+			"blue/green steps sending traffic to the contender must have 100 contender capacity, got %d",
+			step.Capacity.Contender,
+		)
+	}
+
+	if step.Traffic.Incumbent != 0 && step.Capacity.Incumbent != 100 {
+		return fmt.Errorf(
+			"blue/green steps sending traffic to the incumbent must have 100 incumbent capacity, got %d",
+			step.Capacity.Incumbent,
+		)
+	}
+
+	return nil
+}
+
+func validateHookURL(field, hookURL string) error {
+	if hookURL == "" {
+		return nil
+	}
+
+	u, err := url.Parse(hookURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%s must be an http or https URL, got %q", field, hookURL)
+	}
+
+	return nil
+}
+
+func validatePercentage(field string, value int32) error {
+	if value < 0 || value > 100 {
+		return fmt.Errorf("%s must be between 0 and 100, got %d", field, value)
+	}
+
+	return nil
+}
+
+func validateReplicas(field string, value *int32) error {
+	if value != nil && *value < 0 {
+		return fmt.Errorf("%s must not be negative, got %d", field, *value)
+	}
+
+	return nil
+}
+
+func validateAnalysis(analysis *shipper.RolloutStrategyStepAnalysis) error {
+	if analysis.URL == "" {
+		return fmt.Errorf("url must not be empty")
+	}
+
+	if len(analysis.Metrics) == 0 {
+		return fmt.Errorf("at least one metric is required")
+	}
+
+	for i, metric := range analysis.Metrics {
+		if metric.Name == "" {
+			return fmt.Errorf("metric [%d]: name must not be empty", i)
+		}
+
+		if metric.Query == "" {
+			return fmt.Errorf("metric %q: query must not be empty", metric.Name)
+		}
+		if _, err := template.New("query").Parse(metric.Query); err != nil {
+			return fmt.Errorf("metric %q: invalid query: %s", metric.Name, err)
+		}
+
+		thresholds := []struct{ field, value string }{
+			{"min", metric.Min},
+			{"max", metric.Max},
+			{"maxIncumbentRatio", metric.MaxIncumbentRatio},
+		}
+		for _, threshold := range thresholds {
+			if threshold.value == "" {
+				continue
+			}
+			if _, err := strconv.ParseFloat(threshold.value, 64); err != nil {
+				return fmt.Errorf("metric %q: %s must be a number, got %q", metric.Name, threshold.field, threshold.value)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,195 @@
+package strategy
+
+import (
+	"testing"
+
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+)
+
+func buildStrategy() *shipper.RolloutStrategy {
+	return &shipper.RolloutStrategy{
+		Steps: []shipper.RolloutStrategyStep{
+			{
+				Name:     "staging",
+				Capacity: shipper.RolloutStrategyStepValue{Incumbent: 100, Contender: 1},
+				Traffic:  shipper.RolloutStrategyStepValue{Incumbent: 100, Contender: 0},
+			},
+			{
+				Name:     "canary",
+				Capacity: shipper.RolloutStrategyStepValue{Incumbent: 90, Contender: 10},
+				Traffic:  shipper.RolloutStrategyStepValue{Incumbent: 90, Contender: 10},
+			},
+			{
+				Name:     "full on",
+				Capacity: shipper.RolloutStrategyStepValue{Incumbent: 0, Contender: 100},
+				Traffic:  shipper.RolloutStrategyStepValue{Incumbent: 0, Contender: 100},
+			},
+		},
+	}
+}
+
+func TestValidateStrategy(t *testing.T) {
+	tests := []struct {
+		Name     string
+		Mutate   func(*shipper.RolloutStrategy)
+		Expected string
+	}{
+		{
+			"valid strategy",
+			func(s *shipper.RolloutStrategy) {},
+			"",
+		},
+		{
+			"no steps",
+			func(s *shipper.RolloutStrategy) { s.Steps = nil },
+			"strategy must have at least one step",
+		},
+		{
+			"capacity above 100",
+			func(s *shipper.RolloutStrategy) { s.Steps[1].Capacity.Contender = 120 },
+			"step [1]: capacity.contender must be between 0 and 100, got 120",
+		},
+		{
+			"negative traffic",
+			func(s *shipper.RolloutStrategy) { s.Steps[0].Traffic.Incumbent = -1 },
+			"step [0]: traffic.incumbent must not be negative, got -1",
+		},
+		{
+			"contender replicas",
+			func(s *shipper.RolloutStrategy) {
+				replicas := int32(1)
+				s.Steps[0].Capacity = shipper.RolloutStrategyStepValue{Incumbent: 100, ContenderReplicas: &replicas}
+			},
+			"",
+		},
+		{
+			"negative replicas",
+			func(s *shipper.RolloutStrategy) {
+				replicas := int32(-1)
+				s.Steps[0].Capacity.ContenderReplicas = &replicas
+			},
+			"step [0]: capacity.contender.replicas must not be negative, got -1",
+		},
+		{
+			"traffic replicas",
+			func(s *shipper.RolloutStrategy) {
+				replicas := int32(1)
+				s.Steps[0].Traffic.ContenderReplicas = &replicas
+			},
+			"step [0]: traffic must be given as weights, not replicas",
+		},
+		{
+			"last step replicas",
+			func(s *shipper.RolloutStrategy) {
+				replicas := int32(100)
+				s.Steps[2].Capacity.ContenderReplicas = &replicas
+			},
+			"step [2]: the last step must have 100 contender and 0 incumbent capacity as percentages, not replicas",
+		},
+		{
+			"last step clusters",
+			func(s *shipper.RolloutStrategy) {
+				s.Steps[2].Clusters = &shipper.RolloutStrategyStepClusters{Names: []string{"cluster-a"}}
+			},
+			"step [2]: the last step must apply to every cluster, not select clusters",
+		},
+		{
+			"duplicate step names",
+			func(s *shipper.RolloutStrategy) { s.Steps[1].Name = "staging" },
+			`step [1]: name "staging" is already used by step [0]`,
+		},
+		{
+			"incomplete last step",
+			func(s *shipper.RolloutStrategy) { s.Steps = s.Steps[:2] },
+			"step [1]: the last step must have 100 contender and 0 incumbent capacity, got 10 and 90",
+		},
+		{
+			"invalid hook URL",
+			func(s *shipper.RolloutStrategy) {
+				s.Steps[0].Hooks = &shipper.RolloutStrategyStepHooks{PostStep: "smoke-tests:8080"}
+			},
+			`step [0]: hooks.postStep must be an http or https URL, got "smoke-tests:8080"`,
+		},
+		{
+			"unknown type",
+			func(s *shipper.RolloutStrategy) { s.Type = "Canary" },
+			`type must be either Incremental or BlueGreen, got "Canary"`,
+		},
+		{
+			"blue/green strategy",
+			func(s *shipper.RolloutStrategy) {
+				s.Type = shipper.RolloutStrategyTypeBlueGreen
+				s.Steps = []shipper.RolloutStrategyStep{
+					{
+						Name:     "staging",
+						Capacity: shipper.RolloutStrategyStepValue{Incumbent: 100, Contender: 100},
+						Traffic:  shipper.RolloutStrategyStepValue{Incumbent: 100, Contender: 0},
+					},
+					{
+						Name:     "switch",
+						Capacity: shipper.RolloutStrategyStepValue{Incumbent: 100, Contender: 100},
+						Traffic:  shipper.RolloutStrategyStepValue{Incumbent: 0, Contender: 100},
+					},
+					{
+						Name:     "full on",
+						Capacity: shipper.RolloutStrategyStepValue{Incumbent: 0, Contender: 100},
+						Traffic:  shipper.RolloutStrategyStepValue{Incumbent: 0, Contender: 100},
+					},
+				}
+			},
+			"",
+		},
+		{
+			"blue/green strategy with split traffic",
+			func(s *shipper.RolloutStrategy) { s.Type = shipper.RolloutStrategyTypeBlueGreen },
+			"step [1]: blue/green steps must send all traffic to either the contender or the incumbent, got weights 10 and 90",
+		},
+		{
+			"invalid analysis threshold",
+			func(s *shipper.RolloutStrategy) {
+				s.Steps[1].Analysis = &shipper.RolloutStrategyStepAnalysis{
+					URL: "http://prometheus:9090",
+					Metrics: []shipper.RolloutStrategyStepMetric{
+						{Name: "errors", Query: "errors", Max: "a lot"},
+					},
+				}
+			},
+			`step [1]: analysis: metric "errors": max must be a number, got "a lot"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.Name, func(t *testing.T) {
+			strategy := buildStrategy()
+			tt.Mutate(strategy)
+
+			err := ValidateStrategy(strategy)
+			if tt.Expected == "" {
+				if err != nil {
+					t.Errorf("Unexpected error returned by ValidateStrategy(): %s", err)
+				}
+				return
+			}
+
+			if err == nil || err.Error() != tt.Expected {
+				t.Errorf("Unexpected result returned by ValidateStrategy(): got: %v, want: %s", err, tt.Expected)
+			}
+		})
+	}
+}
+
+func TestValidateTargetStep(t *testing.T) {
+	strategy := buildStrategy()
+
+	for _, targetStep := range []int32{0, 2} {
+		if err := ValidateTargetStep(strategy, targetStep); err != nil {
+			t.Errorf("Unexpected error returned by ValidateTargetStep() for step %d: %s", targetStep, err)
+		}
+	}
+
+	for _, targetStep := range []int32{-1, 3} {
+		if err := ValidateTargetStep(strategy, targetStep); err == nil {
+			t.Errorf("Expected ValidateTargetStep() to reject step %d", targetStep)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	admission "k8s.io/api/admission/v1beta1"
+	kubeclient "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+	strategyutil "github.com/bookingcom/shipper/pkg/util/strategy"
+)
+
+type jsonPatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// mutateHandlerFunc records who approved a step in the StepApprovals they
+// create, who changed the target step of a release, and who changed the
+// spec of an application. Everything else is let through untouched.
+func (c *Webhook) mutateHandlerFunc(review *admission.AdmissionReview) *admission.AdmissionResponse {
+	request := review.Request
+
+	var patchOperations []jsonPatchOperation
+	var err error
+	switch request.Kind.Kind {
+	case "StepApproval":
+		if request.Operation == kubeclient.Create {
+			patchOperations = []jsonPatchOperation{
+				{
+					Op:    "add",
+					Path:  "/spec/approvedBy",
+					Value: request.UserInfo.Username,
+				},
+			}
+		}
+	case "Release":
+		patchOperations, err = mutateReleaseTargetStep(request)
+	case "Application":
+		patchOperations, err = mutateApplicationSpec(request)
+	}
+
+	if err == nil && len(patchOperations) == 0 {
+		return &admission.AdmissionResponse{
+			Allowed: true,
+		}
+	}
+
+	var patch []byte
+	if err == nil {
+		patch, err = json.Marshal(patchOperations)
+	}
+	if err != nil {
+		return &admission.AdmissionResponse{
+			Result: &metav1.Status{
+				Message: err.Error(),
+			},
+		}
+	}
+
+	patchType := admission.PatchTypeJSONPatch
+	return &admission.AdmissionResponse{
+		Allowed:   true,
+		Patch:     patch,
+		PatchType: &patchType,
+	}
+}
+
+// validateStepApproval makes sure approvals are made by the user they name,
+// who isn't the one who asked for the release or moved it along, for a step
+// that requires approval, and are never changed afterwards.
+func (c *Webhook) validateStepApproval(request *admission.AdmissionRequest, approval shipper.StepApproval) error {
+	switch request.Operation {
+	case kubeclient.Create:
+		if approval.Spec.ApprovedBy != request.UserInfo.Username {
+			return fmt.Errorf(
+				"approvedBy must be the approving user %q, got %q",
+				request.UserInfo.Username, approval.Spec.ApprovedBy,
+			)
+		}
+
+		rel, err := c.shipperClientset.ShipperV1alpha1().Releases(approval.Namespace).
+			Get(approval.Spec.Release, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("cannot approve release %q: %s", approval.Spec.Release, err)
+		}
+
+		// Approvals are a second pair of eyes on a rollout, so whoever
+		// asked for the release, by changing its application or creating
+		// it, or last moved its target step can't approve it themselves.
+		// Releases Shipper creates are created and moved along by its
+		// own service account, so what counts is who requested them.
+		if requestedBy := rel.Annotations[shipper.ReleaseRequestedByAnnotation]; requestedBy == request.UserInfo.Username {
+			return fmt.Errorf(
+				"user %q cannot approve release %q, as they requested it",
+				request.UserInfo.Username, approval.Spec.Release,
+			)
+		}
+
+		if changedBy := rel.Annotations[shipper.ReleaseTargetStepChangedByAnnotation]; changedBy == request.UserInfo.Username {
+			return fmt.Errorf(
+				"user %q cannot approve release %q, as they last changed its target step",
+				request.UserInfo.Username, approval.Spec.Release,
+			)
+		}
+
+		if !strategyutil.StepRequiresApproval(rel.Spec.Environment.Strategy, approval.Spec.Step) {
+			return fmt.Errorf(
+				"step [%d] of release %q does not require approval",
+				approval.Spec.Step, approval.Spec.Release,
+			)
+		}
+	case kubeclient.Update:
+		var oldApproval shipper.StepApproval
+		if err := json.Unmarshal(request.OldObject.Raw, &oldApproval); err != nil {
+			return err
+		}
+
+		if !reflect.DeepEqual(approval.Spec, oldApproval.Spec) {
+			return fmt.Errorf("step approvals cannot be changed")
+		}
+	}
+
+	return nil
+}
+
+// validateTargetStepApproval rejects releases moved on to a step that
+// requires approval before it was approved.
+func (c *Webhook) validateTargetStepApproval(release shipper.Release) error {
+	targetStep := release.Spec.TargetStep
+	if !strategyutil.StepRequiresApproval(release.Spec.Environment.Strategy, targetStep) {
+		return nil
+	}
+
+	approval, err := strategyutil.FindStepApproval(c.stepApprovalsLister, &release, targetStep)
+	if err != nil {
+		return err
+	}
+
+	if approval == nil {
+		return fmt.Errorf(
+			"step [%d] requires approval: create a StepApproval for release %q and step %d instead",
+			targetStep, release.Name, targetStep,
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,192 @@
+package webhook
+
+import (
+	"strings"
+	"testing"
+
+	admission "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+	shipperfake "github.com/bookingcom/shipper/pkg/client/clientset/versioned/fake"
+	informers "github.com/bookingcom/shipper/pkg/client/informers/externalversions"
+	shippertesting "github.com/bookingcom/shipper/pkg/testing"
+)
+
+const testReleaseName = "test-release"
+
+func TestMutateStepApproval(t *testing.T) {
+	approval := buildStepApproval(1, "")
+
+	patch := runMutation(t, buildAdmissionRequest("StepApproval", admission.Create, "alice", approval, nil))
+
+	expected := []jsonPatchOperation{
+		{Op: "add", Path: "/spec/approvedBy", Value: "alice"},
+	}
+	if eq, diff := shippertesting.DeepEqualDiff(expected, patch); !eq {
+		t.Errorf("unexpected patch:\n%s", diff)
+	}
+}
+
+// TestValidateStepApproval verifies that nobody approves a step on someone
+// else's behalf, or a release they asked for or moved along themselves,
+// and that approvals can't be changed.
+func TestValidateStepApproval(t *testing.T) {
+	tests := []struct {
+		name        string
+		operation   admission.Operation
+		user        string
+		approval    *shipper.StepApproval
+		oldApproval *shipper.StepApproval
+		annotations map[string]string
+		expectedErr string
+	}{
+		{
+			name:      "approved by someone else",
+			operation: admission.Create,
+			user:      "carol",
+			approval:  buildStepApproval(1, "carol"),
+			annotations: map[string]string{
+				shipper.ReleaseRequestedByAnnotation:         "alice",
+				shipper.ReleaseTargetStepChangedByAnnotation: "shipper-service-account",
+			},
+		},
+		{
+			name:        "approving on behalf of someone else",
+			operation:   admission.Create,
+			user:        "carol",
+			approval:    buildStepApproval(1, "dave"),
+			expectedErr: `approvedBy must be the approving user "carol", got "dave"`,
+		},
+		{
+			name:      "approved by the requester",
+			operation: admission.Create,
+			user:      "alice",
+			approval:  buildStepApproval(1, "alice"),
+			annotations: map[string]string{
+				shipper.ReleaseRequestedByAnnotation:         "alice",
+				shipper.ReleaseTargetStepChangedByAnnotation: "shipper-service-account",
+			},
+			expectedErr: `user "alice" cannot approve release "test-release", as they requested it`,
+		},
+		{
+			name:      "approved by whoever moved the target step",
+			operation: admission.Create,
+			user:      "bob",
+			approval:  buildStepApproval(1, "bob"),
+			annotations: map[string]string{
+				shipper.ReleaseRequestedByAnnotation:         "alice",
+				shipper.ReleaseTargetStepChangedByAnnotation: "bob",
+			},
+			expectedErr: `user "bob" cannot approve release "test-release", as they last changed its target step`,
+		},
+		{
+			name:        "step without approval",
+			operation:   admission.Create,
+			user:        "carol",
+			approval:    buildStepApproval(0, "carol"),
+			expectedErr: `step [0] of release "test-release" does not require approval`,
+		},
+		{
+			name:        "changed approval",
+			operation:   admission.Update,
+			user:        "carol",
+			approval:    buildStepApproval(1, "dave"),
+			oldApproval: buildStepApproval(1, "carol"),
+			expectedErr: "step approvals cannot be changed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			webhook := newTestWebhook(buildRelease(tt.annotations, 0))
+			request := buildAdmissionRequest("StepApproval", tt.operation, tt.user, tt.approval, tt.oldApproval)
+
+			response := webhook.validateHandlerFunc(&admission.AdmissionReview{Request: request})
+			if tt.expectedErr == "" {
+				if !response.Allowed {
+					t.Fatalf("expected approval to be allowed, got %q", response.Result.Message)
+				}
+				return
+			}
+
+			if response.Allowed {
+				t.Fatalf("expected approval to be rejected with %q", tt.expectedErr)
+			}
+			if !strings.Contains(response.Result.Message, tt.expectedErr) {
+				t.Errorf("expected error %q, got %q", tt.expectedErr, response.Result.Message)
+			}
+		})
+	}
+}
+
+func newTestWebhook(objects ...runtime.Object) *Webhook {
+	clientset := shipperfake.NewSimpleClientset(objects...)
+	informerFactory := informers.NewSharedInformerFactory(clientset, 0)
+	return NewWebhook("", "", "", "", clientset, informerFactory)
+}
+
+func buildStepApproval(step int32, approvedBy string) *shipper.StepApproval {
+	return &shipper.StepApproval{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: shipper.SchemeGroupVersion.String(),
+			Kind:       "StepApproval",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-approval",
+			Namespace: shippertesting.TestNamespace,
+		},
+		Spec: shipper.StepApprovalSpec{
+			Release:    testReleaseName,
+			Step:       step,
+			ApprovedBy: approvedBy,
+		},
+	}
+}
+
+func buildRelease(annotations map[string]string, targetStep int32) *shipper.Release {
+	return &shipper.Release{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: shipper.SchemeGroupVersion.String(),
+			Kind:       "Release",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        testReleaseName,
+			Namespace:   shippertesting.TestNamespace,
+			Annotations: annotations,
+		},
+		Spec: shipper.ReleaseSpec{
+			TargetStep: targetStep,
+			Environment: shipper.ReleaseEnvironment{
+				Strategy: &shipper.RolloutStrategy{
+					Steps: []shipper.RolloutStrategyStep{
+						{Name: "staging"},
+						{Name: "full on", RequiresApproval: true},
+					},
+				},
+			},
+		},
+	}
+}
+
+func buildApplication(chartVersion string) *shipper.Application {
+	return &shipper.Application{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: shipper.SchemeGroupVersion.String(),
+			Kind:       "Application",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-app",
+			Namespace: shippertesting.TestNamespace,
+			Annotations: map[string]string{
+				shipper.AppChartNameAnnotation: "test-chart",
+			},
+		},
+		Spec: shipper.ApplicationSpec{
+			Template: shipper.ReleaseEnvironment{
+				Chart: shipper.Chart{Name: "test-chart", Version: chartVersion},
+			},
+		},
+	}
+}
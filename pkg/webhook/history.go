@@ -0,0 +1,116 @@
+package webhook
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	admission "k8s.io/api/admission/v1beta1"
+	kubeclient "k8s.io/api/admission/v1beta1"
+
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+)
+
+// mutateReleaseTargetStep annotates releases with the user that created
+// them or changed their target step, so the release controller can tell
+// who it was when it records the change in the release's history. Releases
+// created by hand are also annotated with who requested them, which those
+// Shipper creates for an application already are.
+func mutateReleaseTargetStep(request *admission.AdmissionRequest) ([]jsonPatchOperation, error) {
+	var release shipper.Release
+	if err := json.Unmarshal(request.Object.Raw, &release); err != nil {
+		return nil, err
+	}
+
+	user := request.UserInfo.Username
+	values := map[string]string{
+		shipper.ReleaseTargetStepChangedByAnnotation: user,
+	}
+
+	switch request.Operation {
+	case kubeclient.Create:
+		if _, ok := release.Annotations[shipper.ReleaseRequestedByAnnotation]; !ok {
+			values[shipper.ReleaseRequestedByAnnotation] = user
+		}
+	case kubeclient.Update:
+		var oldRelease shipper.Release
+		if err := json.Unmarshal(request.OldObject.Raw, &oldRelease); err != nil {
+			return nil, err
+		}
+
+		if release.Spec.TargetStep == oldRelease.Spec.TargetStep {
+			return nil, nil
+		}
+	default:
+		return nil, nil
+	}
+
+	return annotationPatch(release.Annotations, values), nil
+}
+
+// mutateApplicationSpec annotates applications with the user that created
+// them or last changed their spec, so the releases created for them can
+// tell who asked for them.
+func mutateApplicationSpec(request *admission.AdmissionRequest) ([]jsonPatchOperation, error) {
+	var app shipper.Application
+	if err := json.Unmarshal(request.Object.Raw, &app); err != nil {
+		return nil, err
+	}
+
+	switch request.Operation {
+	case kubeclient.Create:
+	case kubeclient.Update:
+		var oldApp shipper.Application
+		if err := json.Unmarshal(request.OldObject.Raw, &oldApp); err != nil {
+			return nil, err
+		}
+
+		if reflect.DeepEqual(app.Spec, oldApp.Spec) {
+			return nil, nil
+		}
+	default:
+		return nil, nil
+	}
+
+	return annotationPatch(app.Annotations, map[string]string{
+		shipper.AppSpecChangedByAnnotation: request.UserInfo.Username,
+	}), nil
+}
+
+// annotationPatch sets the given annotations on an object that currently
+// has the given ones.
+func annotationPatch(annotations map[string]string, values map[string]string) []jsonPatchOperation {
+	if annotations == nil {
+		return []jsonPatchOperation{
+			{
+				Op:    "add",
+				Path:  "/metadata/annotations",
+				Value: values,
+			},
+		}
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	patch := make([]jsonPatchOperation, 0, len(keys))
+	for _, key := range keys {
+		patch = append(patch, jsonPatchOperation{
+			Op:    "add",
+			Path:  "/metadata/annotations/" + escapeJSONPointer(key),
+			Value: values[key],
+		})
+	}
+
+	return patch
+}
+
+// escapeJSONPointer escapes a key to be used as a single reference token in
+// a JSON pointer, as described in RFC 6901.
+func escapeJSONPointer(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
@@ -0,0 +1,181 @@
+package webhook
+
+import (
+	"encoding/json"
+	"testing"
+
+	admission "k8s.io/api/admission/v1beta1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	shipper "github.com/bookingcom/shipper/pkg/apis/shipper/v1alpha1"
+	shippertesting "github.com/bookingcom/shipper/pkg/testing"
+)
+
+func TestMutateReleaseTargetStep(t *testing.T) {
+	changedByPath := "/metadata/annotations/" + escapeJSONPointer(shipper.ReleaseTargetStepChangedByAnnotation)
+	requestedByPath := "/metadata/annotations/" + escapeJSONPointer(shipper.ReleaseRequestedByAnnotation)
+
+	tests := []struct {
+		name        string
+		operation   admission.Operation
+		annotations map[string]string
+		oldStep     int32
+		expected    []jsonPatchOperation
+	}{
+		{
+			name:      "created by hand",
+			operation: admission.Create,
+			expected: []jsonPatchOperation{
+				{
+					Op:   "add",
+					Path: "/metadata/annotations",
+					Value: map[string]interface{}{
+						shipper.ReleaseRequestedByAnnotation:         "alice",
+						shipper.ReleaseTargetStepChangedByAnnotation: "alice",
+					},
+				},
+			},
+		},
+		{
+			name:        "created for an application",
+			operation:   admission.Create,
+			annotations: map[string]string{shipper.ReleaseRequestedByAnnotation: "bob"},
+			expected: []jsonPatchOperation{
+				{Op: "add", Path: changedByPath, Value: "alice"},
+			},
+		},
+		{
+			name:        "created with other annotations",
+			operation:   admission.Create,
+			annotations: map[string]string{shipper.ReleaseClustersAnnotation: "kube-a"},
+			expected: []jsonPatchOperation{
+				{Op: "add", Path: requestedByPath, Value: "alice"},
+				{Op: "add", Path: changedByPath, Value: "alice"},
+			},
+		},
+		{
+			name:        "target step changed",
+			operation:   admission.Update,
+			annotations: map[string]string{shipper.ReleaseRequestedByAnnotation: "bob"},
+			oldStep:     0,
+			expected: []jsonPatchOperation{
+				{Op: "add", Path: changedByPath, Value: "alice"},
+			},
+		},
+		{
+			name:        "target step unchanged",
+			operation:   admission.Update,
+			annotations: map[string]string{shipper.ReleaseRequestedByAnnotation: "bob"},
+			oldStep:     1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rel := buildRelease(tt.annotations, 1)
+			oldRel := buildRelease(tt.annotations, tt.oldStep)
+
+			patch := runMutation(t, buildAdmissionRequest("Release", tt.operation, "alice", rel, oldRel))
+			if eq, diff := shippertesting.DeepEqualDiff(tt.expected, patch); !eq {
+				t.Errorf("unexpected patch:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestMutateApplicationSpec(t *testing.T) {
+	tests := []struct {
+		name      string
+		operation admission.Operation
+		oldChart  string
+		expected  []jsonPatchOperation
+	}{
+		{
+			name:      "created",
+			operation: admission.Create,
+			expected: []jsonPatchOperation{
+				{
+					Op:    "add",
+					Path:  "/metadata/annotations/" + escapeJSONPointer(shipper.AppSpecChangedByAnnotation),
+					Value: "alice",
+				},
+			},
+		},
+		{
+			name:      "spec changed",
+			operation: admission.Update,
+			oldChart:  "0.0.1",
+			expected: []jsonPatchOperation{
+				{
+					Op:    "add",
+					Path:  "/metadata/annotations/" + escapeJSONPointer(shipper.AppSpecChangedByAnnotation),
+					Value: "alice",
+				},
+			},
+		},
+		{
+			name:      "spec unchanged",
+			operation: admission.Update,
+			oldChart:  "0.0.2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := buildApplication("0.0.2")
+			oldApp := buildApplication(tt.oldChart)
+
+			patch := runMutation(t, buildAdmissionRequest("Application", tt.operation, "alice", app, oldApp))
+			if eq, diff := shippertesting.DeepEqualDiff(tt.expected, patch); !eq {
+				t.Errorf("unexpected patch:\n%s", diff)
+			}
+		})
+	}
+}
+
+// runMutation runs a request through the mutating webhook, and returns the
+// patch it answered with.
+func runMutation(t *testing.T, request *admission.AdmissionRequest) []jsonPatchOperation {
+	webhook := newTestWebhook()
+
+	response := webhook.mutateHandlerFunc(&admission.AdmissionReview{Request: request})
+	if !response.Allowed {
+		t.Fatalf("expected request to be allowed, got %+v", response.Result)
+	}
+
+	if response.Patch == nil {
+		return nil
+	}
+
+	var patch []jsonPatchOperation
+	if err := json.Unmarshal(response.Patch, &patch); err != nil {
+		t.Fatalf("could not decode patch: %s", err)
+	}
+
+	return patch
+}
+
+func buildAdmissionRequest(kind string, operation admission.Operation, user string, obj, oldObj runtime.Object) *admission.AdmissionRequest {
+	request := &admission.AdmissionRequest{
+		Kind:      metav1.GroupVersionKind{Kind: kind},
+		Operation: operation,
+		UserInfo:  authenticationv1.UserInfo{Username: user},
+		Object:    runtime.RawExtension{Raw: mustMarshal(obj)},
+	}
+
+	if operation == admission.Update {
+		request.OldObject = runtime.RawExtension{Raw: mustMarshal(oldObj)}
+	}
+
+	return request
+}
+
+func mustMarshal(obj runtime.Object) []byte {
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		panic(err)
+	}
+	return raw
+}
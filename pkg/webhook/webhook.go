@@ -22,6 +22,7 @@ import (
 	informers "github.com/bookingcom/shipper/pkg/client/informers/externalversions"
 	listers "github.com/bookingcom/shipper/pkg/client/listers/shipper/v1alpha1"
 	"github.com/bookingcom/shipper/pkg/util/rolloutblock"
+	strategyutil "github.com/bookingcom/shipper/pkg/util/strategy"
 )
 
 const (
@@ -33,6 +34,14 @@ type Webhook struct {
 	rolloutBlocksLister listers.RolloutBlockLister
 	rolloutBlocksSynced cache.InformerSynced
 
+	strategyTemplatesLister        listers.StrategyTemplateLister
+	strategyTemplatesSynced        cache.InformerSynced
+	clusterStrategyTemplatesLister listers.ClusterStrategyTemplateLister
+	clusterStrategyTemplatesSynced cache.InformerSynced
+
+	stepApprovalsLister listers.StepApprovalLister
+	stepApprovalsSynced cache.InformerSynced
+
 	bindAddr string
 	bindPort string
 
@@ -52,12 +61,23 @@ func NewWebhook(
 	shipperInformerFactory informers.SharedInformerFactory,
 ) *Webhook {
 	rolloutBlocksInformer := shipperInformerFactory.Shipper().V1alpha1().RolloutBlocks()
+	strategyTemplatesInformer := shipperInformerFactory.Shipper().V1alpha1().StrategyTemplates()
+	clusterStrategyTemplatesInformer := shipperInformerFactory.Shipper().V1alpha1().ClusterStrategyTemplates()
+	stepApprovalsInformer := shipperInformerFactory.Shipper().V1alpha1().StepApprovals()
 
 	return &Webhook{
 		shipperClientset:    shipperClientset,
 		rolloutBlocksLister: rolloutBlocksInformer.Lister(),
 		rolloutBlocksSynced: rolloutBlocksInformer.Informer().HasSynced,
 
+		strategyTemplatesLister:        strategyTemplatesInformer.Lister(),
+		strategyTemplatesSynced:        strategyTemplatesInformer.Informer().HasSynced,
+		clusterStrategyTemplatesLister: clusterStrategyTemplatesInformer.Lister(),
+		clusterStrategyTemplatesSynced: clusterStrategyTemplatesInformer.Informer().HasSynced,
+
+		stepApprovalsLister: stepApprovalsInformer.Lister(),
+		stepApprovalsSynced: stepApprovalsInformer.Informer().HasSynced,
+
 		bindAddr: bindAddr,
 		bindPort: bindPort,
 
@@ -74,7 +94,7 @@ func (c *Webhook) Run(stopCh <-chan struct{}) {
 		Handler: mux,
 	}
 
-	if !cache.WaitForCacheSync(stopCh, c.rolloutBlocksSynced) {
+	if !cache.WaitForCacheSync(stopCh, c.rolloutBlocksSynced, c.strategyTemplatesSynced, c.clusterStrategyTemplatesSynced, c.stepApprovalsSynced) {
 		klog.Fatalf("failed to wait for caches to sync")
 		return
 	}
@@ -106,6 +126,7 @@ func (c *Webhook) Run(stopCh <-chan struct{}) {
 func (c *Webhook) initializeHandlers() *http.ServeMux {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/validate", adaptHandler(c.validateHandlerFunc))
+	mux.HandleFunc("/mutate", adaptHandler(c.mutateHandlerFunc))
 	return mux
 }
 
@@ -200,6 +221,24 @@ func (c *Webhook) validateHandlerFunc(review *admission.AdmissionReview) *admiss
 	case "RolloutBlock":
 		var rolloutBlock shipper.RolloutBlock
 		err = json.Unmarshal(request.Object.Raw, &rolloutBlock)
+	case "StrategyTemplate":
+		var strategyTemplate shipper.StrategyTemplate
+		err = json.Unmarshal(request.Object.Raw, &strategyTemplate)
+		if err == nil {
+			err = strategyutil.ValidateStrategy(&strategyTemplate.Spec)
+		}
+	case "ClusterStrategyTemplate":
+		var clusterStrategyTemplate shipper.ClusterStrategyTemplate
+		err = json.Unmarshal(request.Object.Raw, &clusterStrategyTemplate)
+		if err == nil {
+			err = strategyutil.ValidateStrategy(&clusterStrategyTemplate.Spec)
+		}
+	case "StepApproval":
+		var stepApproval shipper.StepApproval
+		err = json.Unmarshal(request.Object.Raw, &stepApproval)
+		if err == nil {
+			err = c.validateStepApproval(request, stepApproval)
+		}
 	}
 
 	if err != nil {
@@ -226,6 +265,12 @@ func (c *Webhook) validateRelease(request *admission.AdmissionRequest, release s
 	}
 	switch request.Operation {
 	case kubeclient.Create:
+		if err = validateReleaseStrategy(release); err != nil {
+			return err
+		}
+		if err = validateClusterSelector(release.Spec.Environment.ClusterRequirements); err != nil {
+			return err
+		}
 		err = rolloutblock.ValidateBlocks(existingBlocks, overrides)
 	case kubeclient.Update:
 		var oldRelease shipper.Release
@@ -235,6 +280,17 @@ func (c *Webhook) validateRelease(request *admission.AdmissionRequest, release s
 		}
 
 		if !reflect.DeepEqual(release.Spec, oldRelease.Spec) {
+			if err = validateReleaseStrategy(release); err != nil {
+				return err
+			}
+			if err = validateClusterSelector(release.Spec.Environment.ClusterRequirements); err != nil {
+				return err
+			}
+			if release.Spec.TargetStep != oldRelease.Spec.TargetStep {
+				if err = c.validateTargetStepApproval(release); err != nil {
+					return err
+				}
+			}
 			err = rolloutblock.ValidateBlocks(existingBlocks, overrides)
 		}
 	}
@@ -242,6 +298,21 @@ func (c *Webhook) validateRelease(request *admission.AdmissionRequest, release s
 	return err
 }
 
+// validateReleaseStrategy checks the strategy of a release, and that its
+// target step is one of the strategy's steps.
+func validateReleaseStrategy(release shipper.Release) error {
+	strategy := release.Spec.Environment.Strategy
+	if strategy == nil {
+		return fmt.Errorf("strategy must be set")
+	}
+
+	if err := strategyutil.ValidateStrategy(strategy); err != nil {
+		return fmt.Errorf("invalid strategy: %s", err)
+	}
+
+	return strategyutil.ValidateTargetStep(strategy, release.Spec.TargetStep)
+}
+
 func (c *Webhook) validateApplication(request *admission.AdmissionRequest, application shipper.Application) error {
 	var err error
 	overrides, existingBlocks, err := rolloutblock.GetAllBlocks(c.rolloutBlocksLister, &application)
@@ -253,6 +324,15 @@ func (c *Webhook) validateApplication(request *admission.AdmissionRequest, appli
 	}
 	switch request.Operation {
 	case kubeclient.Create:
+		if err = c.validateStrategyReference(application, nil); err != nil {
+			return err
+		}
+		if err = validateApplicationStrategy(application); err != nil {
+			return err
+		}
+		if err = validateClusterSelector(application.Spec.Template.ClusterRequirements); err != nil {
+			return err
+		}
 		err = rolloutblock.ValidateBlocks(existingBlocks, overrides)
 	case kubeclient.Update:
 		var oldApp shipper.Application
@@ -261,10 +341,84 @@ func (c *Webhook) validateApplication(request *admission.AdmissionRequest, appli
 			return err
 		}
 
+		if err = c.validateStrategyReference(application, &oldApp); err != nil {
+			return err
+		}
+
 		if !reflect.DeepEqual(application.Spec, oldApp.Spec) {
+			if err = validateApplicationStrategy(application); err != nil {
+				return err
+			}
+			if err = validateClusterSelector(application.Spec.Template.ClusterRequirements); err != nil {
+				return err
+			}
 			err = rolloutblock.ValidateBlocks(existingBlocks, overrides)
 		}
 	}
 
 	return err
 }
+
+// validateApplicationStrategy checks an application's inline strategy.
+// Strategies referred to by name are checked when their template is created.
+func validateApplicationStrategy(app shipper.Application) error {
+	strategy := app.Spec.Template.Strategy
+	if strategy == nil {
+		return nil
+	}
+
+	if err := strategyutil.ValidateStrategy(strategy); err != nil {
+		return fmt.Errorf("invalid strategy: %s", err)
+	}
+
+	return nil
+}
+
+// validateClusterSelector makes sure the label selector in cluster
+// requirements, if any, is one the scheduler can use.
+func validateClusterSelector(requirements shipper.ClusterRequirements) error {
+	if requirements.Selector == nil {
+		return nil
+	}
+
+	if _, err := metav1.LabelSelectorAsSelector(requirements.Selector); err != nil {
+		return fmt.Errorf("invalid clusterRequirements.selector: %s", err)
+	}
+
+	return nil
+}
+
+// validateStrategyReference makes sure an application has exactly one of an
+// inline strategy and a reference to a strategy template, and that the
+// template exists. Templates are only looked up when the reference is new,
+// so deleting a template doesn't prevent updates to the applications that
+// were already using it.
+func (c *Webhook) validateStrategyReference(app shipper.Application, oldApp *shipper.Application) error {
+	tmpl := app.Spec.Template
+	if tmpl.StrategyRef == nil {
+		if tmpl.Strategy == nil {
+			return fmt.Errorf("either strategy or strategyRef must be set")
+		}
+		return nil
+	}
+
+	if tmpl.Strategy != nil {
+		return fmt.Errorf("strategy and strategyRef are mutually exclusive")
+	}
+
+	if oldApp != nil && reflect.DeepEqual(tmpl.StrategyRef, oldApp.Spec.Template.StrategyRef) {
+		return nil
+	}
+
+	_, err := strategyutil.ResolveStrategyReference(
+		tmpl.StrategyRef,
+		app.Namespace,
+		c.strategyTemplatesLister,
+		c.clusterStrategyTemplatesLister,
+	)
+	if err != nil {
+		return fmt.Errorf("invalid strategyRef: %s", err)
+	}
+
+	return nil
+}